@@ -2,6 +2,7 @@ package np
 
 import (
 	"fmt"
+	"github.com/mdcfrancis/gonp/internal/vec"
 	"github.com/montanaflynn/stats"
 	"math"
 )
@@ -42,19 +43,43 @@ func (a NpArray) AlmostEqual(b NpArray, epsilon float64) bool {
 }
 
 func (a NpArray) Shuffle(idx []int) NpArray {
-	ret := make(NpArray, len(a))
-	for i, v := range idx {
-		ret[i] = a[v]
+	return ShuffleInto(make(NpArray, len(a)), a, idx)
+}
+
+// Summation selects how Sum and Dot accumulate. The zero value is numpy's
+// pairwise summation.
+type Summation int
+
+const (
+	// SumPairwise adds in blocks of eight accumulators combined pairwise, as
+	// numpy does.
+	SumPairwise Summation = iota
+	// SumKahan uses compensated summation, whose error does not grow with
+	// the length.
+	SumKahan
+)
+
+func (s Summation) String() string {
+	switch s {
+	case SumPairwise:
+		return "pairwise"
+	case SumKahan:
+		return "kahan"
 	}
-	return ret
+	return fmt.Sprintf("Summation(%d)", int(s))
 }
 
+// Sum adds the values pairwise, like numpy.sum.
 func (a NpArray) Sum() float64 {
-	sum := 0.0
-	for _, v := range a {
-		sum += v
+	return vec.Sum(a)
+}
+
+// SumWith adds the values with the given summation.
+func (a NpArray) SumWith(s Summation) float64 {
+	if s == SumKahan {
+		return vec.KahanSum(a)
 	}
-	return sum
+	return vec.Sum(a)
 }
 
 func (a NpArray) Mean() float64 {
@@ -67,137 +92,84 @@ func (a NpArray) StandardDeviation() float64 {
 }
 
 func (a NpArray) Sub(b NpArray) NpArray {
-	if len(a) != len(b) {
-		panic(fmt.Errorf("Arrays must be same length %d %d", len(a), len(b)))
+	if err := sameLength("Sub", len(a), len(b)); err != nil {
+		panic(err)
 	}
 	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] - b[i]
-	}
+	vec.Sub(ret, a, b)
 	return ret
 }
 
 func (a NpArray) Div(b NpArray) NpArray {
-	if len(a) != len(b) {
-		panic(fmt.Errorf("Arrays must be same length %d %d", len(a), len(b)))
+	if err := sameLength("Div", len(a), len(b)); err != nil {
+		panic(err)
 	}
 	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] / b[i]
-	}
+	vec.Div(ret, a, b)
 	return ret
 }
 
 func (a NpArray) Mul(b NpArray) NpArray {
-	if len(a) != len(b) {
-		panic(fmt.Errorf("Arrays must be same length %d %d", len(a), len(b)))
+	if err := sameLength("Mul", len(a), len(b)); err != nil {
+		panic(err)
 	}
 	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] * b[i]
-	}
+	vec.Mul(ret, a, b)
 	return ret
 }
 
 func (a NpArray) PowFloat64(power float64) NpArray {
-	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = math.Pow(a[i], power)
-	}
-	return ret
+	return PowFloat64Into(make(NpArray, len(a)), a, power)
 }
 
 func (a NpArray) Add(b NpArray) NpArray {
-	if len(a) != len(b) {
-		panic(fmt.Errorf("Arrays must be same length %d %d", len(a), len(b)))
+	if err := sameLength("Add", len(a), len(b)); err != nil {
+		panic(err)
 	}
 	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] + b[i]
-	}
+	vec.Add(ret, a, b)
 	return ret
 }
 
+// Dot returns the sum of the products a[i]*b[i], added pairwise.
 func (a NpArray) Dot(b NpArray) float64 {
-	if len(a) != len(b) {
-		panic(fmt.Errorf("doProdut : arrays must be same length %d %d", len(a), len(b)))
+	return a.DotWith(b, SumPairwise)
+}
+
+// DotWith returns the sum of the products a[i]*b[i], added with the given
+// summation.
+func (a NpArray) DotWith(b NpArray, s Summation) float64 {
+	if err := sameLength("Dot", len(a), len(b)); err != nil {
+		panic(err)
 	}
-	ret := 0.0
-	for i := 0; i < len(a); i++ {
-		ret += a[i] * b[i]
+	if s == SumKahan {
+		return vec.KahanDot(a, b)
 	}
-	return ret
+	return vec.Dot(a, b)
 }
 
 func (a NpArray) DivFloat64(b float64) NpArray {
-	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] / b
-	}
-	return ret
+	return DivFloat64Into(make(NpArray, len(a)), a, b)
 }
 
 func (a NpArray) MulFloat64(b float64) NpArray {
-	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] * b
-	}
-	return ret
+	return MulFloat64Into(make(NpArray, len(a)), a, b)
 }
 
 func (a NpArray) SubSlice(b NpArray, start, end int) NpArray {
-	ret := make(NpArray, end-start)
-	for i := start; i < end; i++ {
-		ret[i-start] = a[i] - b[i]
-	}
-	return ret
+	return SubSliceInto(make(NpArray, end-start), a, b, start, end)
 }
 
 func (a NpArray) DivSlice(b float64, start, end int) NpArray {
-	ret := make(NpArray, end-start)
-	for i := start; i < end; i++ {
-		ret[i-start] = a[i] / b
-	}
-	return ret
+	return DivSliceInto(make(NpArray, end-start), a, b, start, end)
 }
 
 func (a NpArray) SubFloat64(b float64) NpArray {
-	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] - b
-	}
-	return ret
-}
-
-// interp1D performs a 1D linear interpolation,
-// given x and y arrays, and a new x array xi.
-func interp1D(x, y, xi NpArray) (NpArray, error) {
-	if len(x) != len(y) {
-		return nil, fmt.Errorf("x and y must have the same length")
-	}
-
-	yi := make(NpArray, len(xi))
-
-	for i, v := range xi {
-		if v <= x[0] {
-			yi[i] = y[0]
-		} else if v >= x[len(x)-1] {
-			yi[i] = y[len(y)-1]
-		} else {
-			for j := 0; j < len(x)-1; j++ {
-				if v >= x[j] && v <= x[j+1] {
-					yi[i] = y[j] + (y[j+1]-y[j])*(v-x[j])/(x[j+1]-x[j])
-					break
-				}
-			}
-		}
-	}
-
-	return yi, nil
+	return SubFloat64Into(make(NpArray, len(a)), a, b)
 }
 
 func (a NpArray) LinearInterpolate(scale, new_scale NpArray) NpArray {
-	new_a, err := interp1D(scale, a, new_scale)
+	new_a, err := Interp(new_scale, scale, a, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -205,11 +177,7 @@ func (a NpArray) LinearInterpolate(scale, new_scale NpArray) NpArray {
 }
 
 func (a NpArray) Cond(f func(float64) float64) NpArray {
-	ret := make(NpArray, len(a))
-	for i, v := range a {
-		ret[i] = f(v)
-	}
-	return ret
+	return CondInto(make(NpArray, len(a)), a, f)
 }
 
 func (a NpArray) Min() float64 {
@@ -223,9 +191,5 @@ func (a NpArray) Max() float64 {
 }
 
 func (a NpArray) AddFloat64(b float64) NpArray {
-	ret := make(NpArray, len(a))
-	for i := 0; i < len(a); i++ {
-		ret[i] = a[i] + b
-	}
-	return ret
+	return AddFloat64Into(make(NpArray, len(a)), a, b)
 }
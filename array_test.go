@@ -160,6 +160,38 @@ func TestNpArray_Sum(t *testing.T) {
 	assert.Equal(t, expected, arr.Sum())
 }
 
+func TestNpArray_SumWith(t *testing.T) {
+	arr := make(NpArray, 1001)
+	ones := make(NpArray, len(arr))
+	arr[0] = 1
+	for i := 1; i < len(arr); i++ {
+		arr[i] = 1e-16
+		ones[i] = 1
+	}
+	ones[0] = 1
+	assert.Equal(t, pairwiseSum(arr), arr.SumWith(SumPairwise))
+	assert.Equal(t, 1+1e-13, arr.SumWith(SumKahan))
+	assert.Equal(t, arr.Sum(), arr.Dot(ones))
+	assert.Equal(t, 1+1e-13, arr.DotWith(ones, SumKahan))
+	assert.Equal(t, "kahan", SumKahan.String())
+	assert.Panics(t, func() { arr.DotWith(NpArray{1}, SumKahan) })
+}
+
+func TestNpArray_ArithmeticLong(t *testing.T) {
+	// long enough for the vector kernels and a scalar tail
+	a, b := make(NpArray, 37), make(NpArray, 37)
+	for i := range a {
+		a[i], b[i] = float64(i)+0.5, float64(2*i+1)
+	}
+	sum, diff, prod, quot := a.Add(b), a.Sub(b), a.Mul(b), a.Div(b)
+	for i := range a {
+		assert.Equal(t, a[i]+b[i], sum[i])
+		assert.Equal(t, a[i]-b[i], diff[i])
+		assert.Equal(t, a[i]*b[i], prod[i])
+		assert.Equal(t, a[i]/b[i], quot[i])
+	}
+}
+
 func TestNpArray_AlmostEqualReturnsTrueForEqualArrays(t *testing.T) {
 	arr1 := NpArray{1.0, 2.0, 3.0}
 	arr2 := NpArray{1.0, 2.0, 3.0}
@@ -0,0 +1,251 @@
+package np
+
+import (
+	"math/bits"
+)
+
+// BitGenerator is a source of raw random bits for a Generator, as numpy's
+// BitGenerator classes are.
+type BitGenerator interface {
+	Uint64() uint64
+	Uint32() uint32
+}
+
+// The SeedSequence constants and hashing are those of numpy's
+// bit_generator.pyx, itself after Melissa O'Neill's seed_seq_fe.
+const (
+	seedPoolSize = 4
+	seedInitA    = 0x43b0d7e5
+	seedMultA    = 0x931e8875
+	seedInitB    = 0x8b51f9dd
+	seedMultB    = 0x58f38ded
+	seedMixL     = 0xca01f9dd
+	seedMixR     = 0x4973f715
+	seedXShift   = 16
+)
+
+// SeedSequence mixes a seed into a well distributed pool of state, as
+// numpy.random.SeedSequence does.
+type SeedSequence struct {
+	entropy []uint32
+	pool    [seedPoolSize]uint32
+}
+
+// NewSeedSequence builds a SeedSequence from one or more integers, as
+// SeedSequence(seed) or SeedSequence([a, b, ...]) would.
+func NewSeedSequence(entropy ...uint64) *SeedSequence {
+	ss := &SeedSequence{}
+	for _, e := range entropy {
+		// each integer contributes its 32 bit words, least significant first
+		ss.entropy = append(ss.entropy, uint32(e))
+		if e>>32 != 0 {
+			ss.entropy = append(ss.entropy, uint32(e>>32))
+		}
+	}
+	ss.mixEntropy()
+	return ss
+}
+
+func (ss *SeedSequence) mixEntropy() {
+	hashConst := uint32(seedInitA)
+	hashmix := func(value uint32) uint32 {
+		value ^= hashConst
+		hashConst *= seedMultA
+		value *= hashConst
+		value ^= value >> seedXShift
+		return value
+	}
+	mix := func(x, y uint32) uint32 {
+		result := seedMixL*x - seedMixR*y
+		result ^= result >> seedXShift
+		return result
+	}
+	for i := range ss.pool {
+		if i < len(ss.entropy) {
+			ss.pool[i] = hashmix(ss.entropy[i])
+		} else {
+			ss.pool[i] = hashmix(0)
+		}
+	}
+	for src := range ss.pool {
+		for dst := range ss.pool {
+			if src != dst {
+				ss.pool[dst] = mix(ss.pool[dst], hashmix(ss.pool[src]))
+			}
+		}
+	}
+	for src := len(ss.pool); src < len(ss.entropy); src++ {
+		for dst := range ss.pool {
+			ss.pool[dst] = mix(ss.pool[dst], hashmix(ss.entropy[src]))
+		}
+	}
+}
+
+// GenerateState32 returns n words of seed state, like generate_state(n).
+func (ss *SeedSequence) GenerateState32(n int) []uint32 {
+	hashConst := uint32(seedInitB)
+	ret := make([]uint32, n)
+	for i := range ret {
+		v := ss.pool[i%seedPoolSize]
+		v ^= hashConst
+		hashConst *= seedMultB
+		v *= hashConst
+		v ^= v >> seedXShift
+		ret[i] = v
+	}
+	return ret
+}
+
+// GenerateState64 returns n words of seed state, like
+// generate_state(n, np.uint64).
+func (ss *SeedSequence) GenerateState64(n int) []uint64 {
+	words := ss.GenerateState32(2 * n)
+	ret := make([]uint64, n)
+	for i := range ret {
+		ret[i] = uint64(words[2*i]) | uint64(words[2*i+1])<<32
+	}
+	return ret
+}
+
+// uint32Buffer hands out the two halves of a 64 bit draw, low half first, as
+// numpy's bit generators do for 32 bit requests.
+type uint32Buffer struct {
+	has   bool
+	value uint32
+}
+
+func (b *uint32Buffer) next(next64 func() uint64) uint32 {
+	if b.has {
+		b.has = false
+		return b.value
+	}
+	v := next64()
+	b.has = true
+	b.value = uint32(v >> 32)
+	return uint32(v)
+}
+
+// 128 bit LCG multiplier of PCG64.
+const (
+	pcgMultHi = 2549297995355413924
+	pcgMultLo = 4865540595714422341
+)
+
+// PCG64 is the 128 bit permuted congruential generator with the XSL-RR
+// output function, numpy's default bit generator.
+type PCG64 struct {
+	stateHi, stateLo uint64
+	incHi, incLo     uint64
+	buf              uint32Buffer
+}
+
+func NewPCG64(ss *SeedSequence) *PCG64 {
+	seed := ss.GenerateState64(4)
+	g := &PCG64{}
+	// inc = (initseq << 1) | 1
+	g.incHi = seed[2]<<1 | seed[3]>>63
+	g.incLo = seed[3]<<1 | 1
+	g.step()
+	var carry uint64
+	g.stateLo, carry = bits.Add64(g.stateLo, seed[1], 0)
+	g.stateHi, _ = bits.Add64(g.stateHi, seed[0], carry)
+	g.step()
+	return g
+}
+
+func (g *PCG64) step() {
+	hi, lo := bits.Mul64(g.stateLo, pcgMultLo)
+	hi += g.stateHi*pcgMultLo + g.stateLo*pcgMultHi
+	var carry uint64
+	g.stateLo, carry = bits.Add64(lo, g.incLo, 0)
+	g.stateHi, _ = bits.Add64(hi, g.incHi, carry)
+}
+
+func (g *PCG64) Uint64() uint64 {
+	g.step()
+	return bits.RotateLeft64(g.stateHi^g.stateLo, -int(g.stateHi>>58))
+}
+
+func (g *PCG64) Uint32() uint32 {
+	return g.buf.next(g.Uint64)
+}
+
+// Philox4x64-10 constants from Random123.
+const (
+	philoxM0 = 0xD2E7470EE14C6C93
+	philoxM1 = 0xCA5A826395121157
+	philoxW0 = 0x9E3779B97F4A7C15
+	philoxW1 = 0xBB67AE8584CAA73B
+)
+
+// Philox is the 4x64 counter based generator with 10 rounds.
+type Philox struct {
+	ctr    [4]uint64
+	key    [2]uint64
+	out    [4]uint64
+	outPos int
+	buf    uint32Buffer
+}
+
+func NewPhilox(ss *SeedSequence) *Philox {
+	key := ss.GenerateState64(2)
+	return &Philox{key: [2]uint64{key[0], key[1]}, outPos: 4}
+}
+
+func (g *Philox) Uint64() uint64 {
+	if g.outPos < 4 {
+		g.outPos++
+		return g.out[g.outPos-1]
+	}
+	for i := range g.ctr {
+		g.ctr[i]++
+		if g.ctr[i] != 0 {
+			break
+		}
+	}
+	ctr, key := g.ctr, g.key
+	for r := 0; r < 10; r++ {
+		if r > 0 {
+			key[0] += philoxW0
+			key[1] += philoxW1
+		}
+		hi0, lo0 := bits.Mul64(philoxM0, ctr[0])
+		hi1, lo1 := bits.Mul64(philoxM1, ctr[2])
+		ctr = [4]uint64{hi1 ^ ctr[1] ^ key[0], lo1, hi0 ^ ctr[3] ^ key[1], lo0}
+	}
+	g.out = ctr
+	g.outPos = 1
+	return g.out[0]
+}
+
+func (g *Philox) Uint32() uint32 {
+	return g.buf.next(g.Uint64)
+}
+
+// SFC64 is Chris Doty-Humphrey's Small Fast Chaotic generator.
+type SFC64 struct {
+	s   [4]uint64
+	buf uint32Buffer
+}
+
+func NewSFC64(ss *SeedSequence) *SFC64 {
+	seed := ss.GenerateState64(3)
+	g := &SFC64{s: [4]uint64{seed[0], seed[1], seed[2], 1}}
+	for i := 0; i < 12; i++ {
+		g.Uint64()
+	}
+	return g
+}
+
+func (g *SFC64) Uint64() uint64 {
+	tmp := g.s[0] + g.s[1] + g.s[3]
+	g.s[3]++
+	g.s[0] = g.s[1] ^ (g.s[1] >> 11)
+	g.s[1] = g.s[2] + (g.s[2] << 3)
+	g.s[2] = bits.RotateLeft64(g.s[2], 24) + tmp
+	return tmp
+}
+
+func (g *SFC64) Uint32() uint32 {
+	return g.buf.next(g.Uint64)
+}
@@ -0,0 +1,176 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AsNDArray converts a float64, int, NpArray, NpStack or NDArray into an
+// NDArray. Scalars become 0-d arrays and NpArray becomes a row vector, an
+// NpStack with a single column can be used as a column vector.
+func AsNDArray(v interface{}) (NDArray, error) {
+	switch t := v.(type) {
+	case NDArray:
+		return t, nil
+	case NpArray:
+		return t.ToNDArray(), nil
+	case []float64:
+		return NpArray(t).ToNDArray(), nil
+	case NpStack:
+		return t.ToNDArray()
+	case float64:
+		return NDArray{data: []float64{t}, shape: []int{}, strides: []int{}}, nil
+	case int:
+		return NDArray{data: []float64{float64(t)}, shape: []int{}, strides: []int{}}, nil
+	}
+	return NDArray{}, fmt.Errorf("cannot convert %T to an array", v)
+}
+
+// BroadcastShapes returns the shape the given shapes broadcast to under the
+// numpy rules: shapes are right aligned and each axis must match or be 1.
+func BroadcastShapes(shapes ...[]int) ([]int, error) {
+	n := 0
+	for _, s := range shapes {
+		if len(s) > n {
+			n = len(s)
+		}
+	}
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = 1
+	}
+	for _, s := range shapes {
+		for i, d := range s {
+			j := n - len(s) + i
+			switch {
+			case d == ret[j] || d == 1:
+			case ret[j] == 1:
+				ret[j] = d
+			default:
+				return nil, &ShapeError{Shapes: shapes}
+			}
+		}
+	}
+	return ret, nil
+}
+
+// BroadcastTo returns a read only view of a with the given shape, repeated
+// axes have a stride of zero.
+func (a Array[T]) BroadcastTo(shape ...int) (Array[T], error) {
+	if len(shape) < len(a.shape) {
+		return Array[T]{}, fmt.Errorf("input operand has more dimensions than allowed by the axis remapping")
+	}
+	strides := make([]int, len(shape))
+	lead := len(shape) - len(a.shape)
+	for i, d := range a.shape {
+		switch {
+		case d == shape[lead+i]:
+			strides[lead+i] = a.strides[i]
+		case d == 1:
+			strides[lead+i] = 0
+		default:
+			return Array[T]{}, fmt.Errorf("cannot broadcast array from shape %s into shape %s", formatShape(a.shape), formatShape(shape))
+		}
+	}
+	return Array[T]{data: a.data, shape: copyInts(shape), strides: strides, offset: a.offset}, nil
+}
+
+// Add returns a + b with numpy broadcasting, the operands may be any of the
+// types accepted by AsNDArray.
+func Add(a, b interface{}) (NDArray, error) {
+	return broadcastOp(a, b, func(x, y float64) float64 { return x + y })
+}
+
+// Sub returns a - b with numpy broadcasting.
+func Sub(a, b interface{}) (NDArray, error) {
+	return broadcastOp(a, b, func(x, y float64) float64 { return x - y })
+}
+
+// Mul returns a * b with numpy broadcasting.
+func Mul(a, b interface{}) (NDArray, error) {
+	return broadcastOp(a, b, func(x, y float64) float64 { return x * y })
+}
+
+// Div returns a / b with numpy broadcasting.
+func Div(a, b interface{}) (NDArray, error) {
+	return broadcastOp(a, b, func(x, y float64) float64 { return x / y })
+}
+
+// Pow returns a ** b with numpy broadcasting.
+func Pow(a, b interface{}) (NDArray, error) {
+	return broadcastOp(a, b, math.Pow)
+}
+
+func broadcastOp(a, b interface{}, op func(x, y float64) float64) (NDArray, error) {
+	x, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	y, err := AsNDArray(b)
+	if err != nil {
+		return NDArray{}, err
+	}
+	shape, err := BroadcastShapes(x.shape, y.shape)
+	if err != nil {
+		return NDArray{}, err
+	}
+	// BroadcastShapes has already checked the shapes are compatible
+	x, _ = x.BroadcastTo(shape...)
+	y, _ = y.BroadcastTo(shape...)
+	ret := NDZeros(shape...)
+	i := 0
+	eachPair(x, y, func(px, py int) {
+		ret.data[i] = op(x.data[px], y.data[py])
+		i++
+	})
+	return ret, nil
+}
+
+// eachPair walks two arrays of the same shape in C order calling f with the
+// buffer positions of matching elements.
+func eachPair[A, B Element](a Array[A], b Array[B], f func(pa, pb int)) {
+	if a.Size() == 0 {
+		return
+	}
+	idx := make([]int, len(a.shape))
+	pa, pb := a.offset, b.offset
+	for {
+		f(pa, pb)
+		axis := len(a.shape) - 1
+		for ; axis >= 0; axis-- {
+			idx[axis]++
+			pa += a.strides[axis]
+			pb += b.strides[axis]
+			if idx[axis] < a.shape[axis] {
+				break
+			}
+			pa -= idx[axis] * a.strides[axis]
+			pb -= idx[axis] * b.strides[axis]
+			idx[axis] = 0
+		}
+		if axis < 0 {
+			return
+		}
+	}
+}
+
+// formatShape prints a shape the way numpy does, for example (2,3) or (4,).
+func formatShape(shape []int) string {
+	parts := make([]string, len(shape))
+	for i, d := range shape {
+		parts[i] = fmt.Sprint(d)
+	}
+	if len(shape) == 1 {
+		return "(" + parts[0] + ",)"
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+func formatShapes(shapes [][]int) string {
+	parts := make([]string, len(shapes))
+	for i, s := range shapes {
+		parts[i] = formatShape(s)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,77 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBroadcastShapes(t *testing.T) {
+	shape, err := BroadcastShapes([]int{2, 1}, []int{3}, []int{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, shape)
+
+	_, err = BroadcastShapes([]int{2, 3}, []int{4})
+	assert.EqualError(t, err, "operands could not be broadcast together with shapes (2,3) (4,)")
+}
+
+func TestNDArray_BroadcastTo(t *testing.T) {
+	row := NpArray{1, 2, 3}.ToNDArray()
+	b, err := row.BroadcastTo(2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, b.Strides())
+	assert.Equal(t, []float64{1, 2, 3, 1, 2, 3}, b.Flatten())
+
+	_, err = row.BroadcastTo(2, 4)
+	assert.Error(t, err)
+}
+
+func TestAdd_ScalarAndMatrix(t *testing.T) {
+	stack := NpStack{{1, 2, 3}, {4, 5, 6}}
+	ret, err := Add(stack, 1.0)
+	assert.NoError(t, err)
+	m, _ := ret.ToNpStack()
+	assert.Equal(t, NpStack{{2, 3, 4}, {5, 6, 7}}, m)
+
+	ret, err = Sub(10, stack)
+	assert.NoError(t, err)
+	m, _ = ret.ToNpStack()
+	assert.Equal(t, NpStack{{9, 8, 7}, {6, 5, 4}}, m)
+}
+
+func TestMul_RowVector(t *testing.T) {
+	stack := NpStack{{1, 2, 3}, {4, 5, 6}}
+	ret, err := Mul(stack, NpArray{1, 0, -1})
+	assert.NoError(t, err)
+	m, _ := ret.ToNpStack()
+	assert.Equal(t, NpStack{{1, 0, -3}, {4, 0, -6}}, m)
+}
+
+func TestDiv_ColumnVector(t *testing.T) {
+	stack := NpStack{{2, 4, 6}, {3, 6, 9}}
+	ret, err := Div(stack, NpStack{{2}, {3}})
+	assert.NoError(t, err)
+	m, _ := ret.ToNpStack()
+	assert.Equal(t, NpStack{{1, 2, 3}, {1, 2, 3}}, m)
+}
+
+func TestAdd_ColumnAndRowVector(t *testing.T) {
+	col, _ := NpArray{0, 10}.ToNDArray().ExpandDims(1)
+	ret, err := Add(col, NpArray{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 3, 11, 12, 13}, ret.Flatten())
+}
+
+func TestPow_Broadcast(t *testing.T) {
+	ret, err := Pow(NpArray{1, 2, 3}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 4, 9}, ret.Flatten())
+}
+
+func TestSub_ShapeMismatch(t *testing.T) {
+	_, err := Sub(NpStack{{1, 2, 3}, {4, 5, 6}}, NpArray{1, 2})
+	assert.EqualError(t, err, "operands could not be broadcast together with shapes (2,3) (2,)")
+
+	_, err = Add(NpArray{1}, "a")
+	assert.Error(t, err)
+}
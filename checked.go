@@ -0,0 +1,245 @@
+package np
+
+// Checked wraps an NpArray so that its operations return an error where the
+// NpArray methods panic on bad input: a *ShapeError, matching
+// ErrShapeMismatch, for operands of different lengths and an *IndexError,
+// matching ErrIndexOutOfRange, for indices outside the array. Use it on
+// data from outside the program, for example
+//
+//	d, err := np.Check(signal).Sub(baseline)
+type Checked struct {
+	Array NpArray
+}
+
+// Check wraps a for the checked operations.
+func Check(a NpArray) Checked {
+	return Checked{Array: a}
+}
+
+func (c Checked) Add(b NpArray) (NpArray, error) {
+	if err := sameLength("Add", len(c.Array), len(b)); err != nil {
+		return nil, err
+	}
+	return c.Array.Add(b), nil
+}
+
+func (c Checked) Sub(b NpArray) (NpArray, error) {
+	if err := sameLength("Sub", len(c.Array), len(b)); err != nil {
+		return nil, err
+	}
+	return c.Array.Sub(b), nil
+}
+
+func (c Checked) Mul(b NpArray) (NpArray, error) {
+	if err := sameLength("Mul", len(c.Array), len(b)); err != nil {
+		return nil, err
+	}
+	return c.Array.Mul(b), nil
+}
+
+func (c Checked) Div(b NpArray) (NpArray, error) {
+	if err := sameLength("Div", len(c.Array), len(b)); err != nil {
+		return nil, err
+	}
+	return c.Array.Div(b), nil
+}
+
+func (c Checked) Dot(b NpArray) (float64, error) {
+	return c.DotWith(b, SumPairwise)
+}
+
+func (c Checked) DotWith(b NpArray, s Summation) (float64, error) {
+	if err := sameLength("Dot", len(c.Array), len(b)); err != nil {
+		return 0, err
+	}
+	return c.Array.DotWith(b, s), nil
+}
+
+// SubSlice returns a[i] - b[i] for i in [start, end), which must lie within
+// both arrays. As with a Go or Python slice, start >= end gives an empty
+// array.
+func (c Checked) SubSlice(b NpArray, start, end int) (NpArray, error) {
+	if err := checkSlice("SubSlice", start, end, len(c.Array)); err != nil {
+		return nil, err
+	}
+	if err := checkSlice("SubSlice", start, end, len(b)); err != nil {
+		return nil, err
+	}
+	if start >= end {
+		return NpArray{}, nil
+	}
+	return c.Array.SubSlice(b, start, end), nil
+}
+
+// DivSlice returns a[i] / b for i in [start, end), which must lie within
+// the array.
+func (c Checked) DivSlice(b float64, start, end int) (NpArray, error) {
+	if err := checkSlice("DivSlice", start, end, len(c.Array)); err != nil {
+		return nil, err
+	}
+	if start >= end {
+		return NpArray{}, nil
+	}
+	return c.Array.DivSlice(b, start, end), nil
+}
+
+// Shuffle returns the array reordered by idx, which may not be longer than
+// the array and holds indices within it.
+func (c Checked) Shuffle(idx []int) (NpArray, error) {
+	if err := checkPermutation("Shuffle", idx, len(c.Array)); err != nil {
+		return nil, err
+	}
+	return c.Array.Shuffle(idx), nil
+}
+
+// LinearInterpolate resamples the array, given at scale, at new_scale. The
+// errors of Interp, such as a decreasing scale, are returned as they are.
+func (c Checked) LinearInterpolate(scale, new_scale NpArray) (NpArray, error) {
+	if err := sameLength("LinearInterpolate", len(scale), len(c.Array)); err != nil {
+		return nil, err
+	}
+	return Interp(new_scale, scale, c.Array, nil)
+}
+
+// CheckedStack wraps an NpStack as Checked wraps an NpArray. Rows of
+// different lengths, which NpStack does not prevent, give a *ShapeError
+// where the operation needs them to match.
+type CheckedStack struct {
+	Rows NpStack
+}
+
+// CheckStack wraps m for the checked operations.
+func CheckStack(m NpStack) CheckedStack {
+	return CheckedStack{Rows: m}
+}
+
+// Shape returns the number of rows and their length, {0, 0} for an empty
+// stack, or a *ShapeError when the rows differ in length.
+func (s CheckedStack) Shape() ([]int, error) {
+	if err := s.rectangular("Shape"); err != nil {
+		return nil, err
+	}
+	return s.Rows.Shape(), nil
+}
+
+// Column returns element i of every row.
+func (s CheckedStack) Column(i int) (NpArray, error) {
+	for _, a := range s.Rows {
+		if i < 0 || i >= len(a) {
+			return nil, &IndexError{Op: "Column", Index: i, Axis: 1, Size: len(a)}
+		}
+	}
+	return s.Rows.Column(i), nil
+}
+
+// Sub subtracts b[i] from row i.
+func (s CheckedStack) Sub(b NpArray) (NpStack, error) {
+	if err := sameLength("Sub", len(s.Rows), len(b)); err != nil {
+		return nil, err
+	}
+	return s.Rows.Sub(b), nil
+}
+
+// Div divides row i by b[i].
+func (s CheckedStack) Div(b NpArray) (NpStack, error) {
+	if err := sameLength("Div", len(s.Rows), len(b)); err != nil {
+		return nil, err
+	}
+	return s.Rows.Div(b), nil
+}
+
+func (s CheckedStack) Add(b NpStack) (NpStack, error) {
+	if len(s.Rows) != len(b) {
+		return nil, &ShapeError{Op: "Add", Shapes: [][]int{s.Rows.Shape(), b.Shape()}}
+	}
+	for i, a := range s.Rows {
+		if len(a) != len(b[i]) {
+			return nil, &ShapeError{Op: "Add", Shapes: [][]int{{len(s.Rows), len(a)}, {len(b), len(b[i])}}}
+		}
+	}
+	return s.Rows.Add(b), nil
+}
+
+func (s CheckedStack) SubSlice(b NpArray, start, end int) (NpStack, error) {
+	for _, a := range s.Rows {
+		if err := checkSlice("SubSlice", start, end, len(a)); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkSlice("SubSlice", start, end, len(b)); err != nil {
+		return nil, err
+	}
+	if start >= end {
+		return s.Rows.MapRows(func(int, NpArray) NpArray { return NpArray{} }), nil
+	}
+	return s.Rows.SubSlice(b, start, end), nil
+}
+
+func (s CheckedStack) DivSlice(b float64, start, end int) (NpStack, error) {
+	for _, a := range s.Rows {
+		if err := checkSlice("DivSlice", start, end, len(a)); err != nil {
+			return nil, err
+		}
+	}
+	if start >= end {
+		return s.Rows.MapRows(func(int, NpArray) NpArray { return NpArray{} }), nil
+	}
+	return s.Rows.DivSlice(b, start, end), nil
+}
+
+// Shuffle returns the rows reordered by idx.
+func (s CheckedStack) Shuffle(idx []int) (NpStack, error) {
+	if err := checkPermutation("Shuffle", idx, len(s.Rows)); err != nil {
+		return nil, err
+	}
+	return s.Rows.Shuffle(idx), nil
+}
+
+func (s CheckedStack) LinearInterpolate(scale, new_scale NpArray) (NpStack, error) {
+	ret := make(NpStack, len(s.Rows))
+	for i, a := range s.Rows {
+		r, err := Check(a).LinearInterpolate(scale, new_scale)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = r
+	}
+	return ret, nil
+}
+
+// rectangular returns a *ShapeError for the first row whose length differs
+// from that of the first row.
+func (s CheckedStack) rectangular(op string) error {
+	for _, a := range s.Rows {
+		if len(a) != len(s.Rows[0]) {
+			return &ShapeError{Op: op, Shapes: [][]int{{len(s.Rows[0])}, {len(a)}}}
+		}
+	}
+	return nil
+}
+
+// checkSlice returns an *IndexError unless [start, end) lies within an
+// array of length n. An empty range only needs start >= 0.
+func checkSlice(op string, start, end, n int) error {
+	switch {
+	case start < 0:
+		return &IndexError{Op: op, Index: start, Size: n}
+	case start < end && end > n:
+		return &IndexError{Op: op, Index: end - 1, Size: n}
+	}
+	return nil
+}
+
+// checkPermutation returns an error unless idx selects at most n elements,
+// each within [0, n).
+func checkPermutation(op string, idx []int, n int) error {
+	if len(idx) > n {
+		return &ShapeError{Op: op, Shapes: [][]int{{len(idx)}, {n}}}
+	}
+	for _, k := range idx {
+		if k < 0 || k >= n {
+			return &IndexError{Op: op, Index: k, Size: n}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package np
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChecked_Array(t *testing.T) {
+	a := NpArray{1, 2, 3}
+	ret, err := Check(a).Add(NpArray{1, 1, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{2, 3, 4}, ret)
+	dot, err := Check(a).Dot(a)
+	assert.NoError(t, err)
+	assert.Equal(t, 14.0, dot)
+
+	_, err = Check(a).Sub(NpArray{1, 2})
+	assert.EqualError(t, err, "Sub: operands have mismatched shapes (3,) (2,)")
+	assert.True(t, errors.Is(err, ErrShapeMismatch))
+	var se *ShapeError
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, [][]int{{3}, {2}}, se.Shapes)
+	for _, f := range []func(NpArray) (NpArray, error){Check(a).Add, Check(a).Mul, Check(a).Div} {
+		_, err = f(NpArray{})
+		assert.ErrorIs(t, err, ErrShapeMismatch)
+	}
+	_, err = Check(a).Dot(NpArray{1})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+
+	// the panicking methods panic with the same error
+	assert.PanicsWithError(t, "Add: operands have mismatched shapes (3,) (1,)", func() { a.Add(NpArray{1}) })
+}
+
+func TestChecked_Slice(t *testing.T) {
+	a := NpArray{1, 2, 3, 4}
+	ret, err := Check(a).SubSlice(NpArray{1, 1, 1, 1}, 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 2}, ret)
+	ret, err = Check(a).SubSlice(a, 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{}, ret)
+
+	_, err = Check(a).SubSlice(a, 2, 6)
+	assert.EqualError(t, err, "SubSlice: index 5 is out of bounds for axis 0 with size 4")
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	var ie *IndexError
+	assert.True(t, errors.As(err, &ie))
+	assert.Equal(t, IndexError{Op: "SubSlice", Index: 5, Axis: 0, Size: 4}, *ie)
+	_, err = Check(a).SubSlice(NpArray{1, 2}, 0, 3)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	_, err = Check(a).DivSlice(2, -1, 2)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	ret, err = Check(a).DivSlice(2, 2, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1.5, 2}, ret)
+
+	ret, err = Check(a).Shuffle([]int{3, 2, 1, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{4, 3, 2, 1}, ret)
+	_, err = Check(a).Shuffle([]int{0, 4})
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	_, err = Check(a).Shuffle([]int{0, 1, 2, 3, 0})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+}
+
+func TestChecked_LinearInterpolate(t *testing.T) {
+	a := NpArray{0, 2, 4}
+	ret, err := Check(a).LinearInterpolate(NpArray{0, 1, 2}, NpArray{0.5, 1.5})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 3}, ret)
+	_, err = Check(a).LinearInterpolate(NpArray{0, 1}, NpArray{0.5})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+	_, err = Check(a).LinearInterpolate(NpArray{2, 1, 0}, NpArray{0.5})
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrShapeMismatch))
+}
+
+func TestChecked_Stack(t *testing.T) {
+	assert.Equal(t, []int{0, 0}, NpStack{}.Shape())
+	shape, err := CheckStack(NpStack{}).Shape()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 0}, shape)
+
+	m := NpStack{{1, 2, 3}, {4, 5, 6}}
+	shape, err = CheckStack(m).Shape()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, shape)
+	_, err = CheckStack(NpStack{{1, 2, 3}, {4}}).Shape()
+	assert.EqualError(t, err, "Shape: rows have different lengths (3,) (1,)")
+
+	col, err := CheckStack(m).Column(1)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{2, 5}, col)
+	_, err = CheckStack(NpStack{{1, 2}, {3}}).Column(1)
+	assert.EqualError(t, err, "Column: index 1 is out of bounds for axis 1 with size 1")
+
+	ret, err := CheckStack(m).Sub(NpArray{1, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{0, 1, 2}, {0, 1, 2}}, ret)
+	_, err = CheckStack(m).Sub(NpArray{1})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+	_, err = CheckStack(m).Div(NpArray{1, 2, 3})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+	ret, err = CheckStack(m).Add(m)
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{2, 4, 6}, {8, 10, 12}}, ret)
+	_, err = CheckStack(m).Add(NpStack{{1, 2, 3}, {1, 2}})
+	assert.EqualError(t, err, "Add: operands have mismatched shapes (2,3) (2,2)")
+	_, err = CheckStack(m).Add(NpStack{{1, 2, 3}})
+	assert.EqualError(t, err, "Add: operands have mismatched shapes (2,3) (1,3)")
+
+	ret, err = CheckStack(m).SubSlice(NpArray{1, 1, 1}, 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{1, 2}, {4, 5}}, ret)
+	_, err = CheckStack(NpStack{{1, 2, 3}, {4}}).SubSlice(NpArray{1, 1, 1}, 0, 2)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	_, err = CheckStack(m).DivSlice(2, 0, 4)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	_, err = CheckStack(m).Shuffle([]int{2})
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	ret, err = CheckStack(m).Shuffle([]int{1, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{m[1], m[0]}, ret)
+
+	ret, err = CheckStack(m).LinearInterpolate(NpArray{0, 1, 2}, NpArray{1})
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{2}, {5}}, ret)
+	_, err = CheckStack(NpStack{{1, 2, 3}, {4}}).LinearInterpolate(NpArray{0, 1, 2}, NpArray{1})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+}
+
+func TestErrors_Typed(t *testing.T) {
+	_, err := BroadcastShapes([]int{2, 3}, []int{4})
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+	var se *ShapeError
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, [][]int{{2, 3}, {4}}, se.Shapes)
+
+	_, err = Take(NpArray{1, 2, 3}, []int{5}, 0)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	var ie *IndexError
+	assert.True(t, errors.As(err, &ie))
+	assert.Equal(t, 5, ie.Index)
+	assert.Equal(t, 3, ie.Size)
+}
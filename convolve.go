@@ -0,0 +1,176 @@
+package np
+
+import (
+	"fmt"
+
+	"github.com/mdcfrancis/gonp/internal/fftpack"
+)
+
+// ConvolveMode selects the output length of Convolve and Correlate, the mode
+// argument of numpy.convolve.
+type ConvolveMode int
+
+const (
+	// ConvolveFull returns every point of overlap, len(a)+len(v)-1 values.
+	ConvolveFull ConvolveMode = iota
+	// ConvolveSame returns max(len(a), len(v)) values centred on the full
+	// result.
+	ConvolveSame
+	// ConvolveValid returns only the points where the inputs overlap
+	// completely, max(len(a), len(v))-min(len(a), len(v))+1 values.
+	ConvolveValid
+)
+
+func (m ConvolveMode) String() string {
+	switch m {
+	case ConvolveFull:
+		return "full"
+	case ConvolveSame:
+		return "same"
+	case ConvolveValid:
+		return "valid"
+	}
+	return fmt.Sprintf("ConvolveMode(%d)", int(m))
+}
+
+// convolveFFTMin is the shorter input length above which the product is
+// computed with an FFT rather than directly.
+const convolveFFTMin = 64
+
+// Convolve returns the discrete linear convolution of a and v, like
+// numpy.convolve. Note numpy defaults to full mode for convolve.
+func Convolve(a, v NpArray, mode ConvolveMode) (NpArray, error) {
+	if len(a) == 0 {
+		return nil, fmt.Errorf("a cannot be empty")
+	}
+	if len(v) == 0 {
+		return nil, fmt.Errorf("v cannot be empty")
+	}
+	if len(v) > len(a) {
+		a, v = v, a
+	}
+	reversed := make(NpArray, len(v))
+	for i, x := range v {
+		reversed[len(v)-1-i] = x
+	}
+	return correlate(a, reversed, mode)
+}
+
+// Correlate returns the cross-correlation c[k] = sum_n a[n+k] * v[n], like
+// numpy.correlate. Note numpy defaults to valid mode for correlate.
+func Correlate(a, v NpArray, mode ConvolveMode) (NpArray, error) {
+	if len(a) == 0 {
+		return nil, fmt.Errorf("a cannot be empty")
+	}
+	if len(v) == 0 {
+		return nil, fmt.Errorf("v cannot be empty")
+	}
+	return correlate(a, v, mode)
+}
+
+func correlate(a, v NpArray, mode ConvolveMode) (NpArray, error) {
+	// numpy correlates the longer input against the shorter and reverses the
+	// result when it had to swap them
+	inverted := false
+	if len(a) < len(v) {
+		a, v = v, a
+		inverted = true
+	}
+	n := len(v)
+	var left, length int
+	switch mode {
+	case ConvolveFull:
+		left, length = n-1, len(a)+n-1
+	case ConvolveSame:
+		left, length = n/2, len(a)
+	case ConvolveValid:
+		left, length = 0, len(a)-n+1
+	default:
+		return nil, fmt.Errorf("unknown convolve mode %v", mode)
+	}
+	var ret NpArray
+	if n > convolveFFTMin {
+		ret = fftCorrelate(a, v)[n-1-left : n-1-left+length]
+	} else {
+		ret = directCorrelate(a, v, left, length)
+	}
+	if inverted {
+		for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+			ret[i], ret[j] = ret[j], ret[i]
+		}
+	}
+	return ret, nil
+}
+
+// directCorrelate returns length values of the correlation of a with the
+// shorter v starting at lag -left.
+func directCorrelate(a, v NpArray, left, length int) NpArray {
+	n := len(v)
+	ret := make(NpArray, length)
+	for i := range ret {
+		k := i - left
+		lo, hi := 0, n
+		if k < 0 {
+			lo = -k
+		}
+		if k+n > len(a) {
+			hi = len(a) - k
+		}
+		sum := 0.0
+		for j := lo; j < hi; j++ {
+			sum += a[j+k] * v[j]
+		}
+		ret[i] = sum
+	}
+	return ret
+}
+
+// fftCorrelate returns the full correlation of a and v, the convolution of a
+// with v reversed, through a zero padded FFT.
+func fftCorrelate(a, v NpArray) NpArray {
+	length := len(a) + len(v) - 1
+	size := fftpack.NextPow2(length)
+	fa := make([]complex128, size)
+	fv := make([]complex128, size)
+	for i, x := range a {
+		fa[i] = complex(x, 0)
+	}
+	for i, x := range v {
+		fv[len(v)-1-i] = complex(x, 0)
+	}
+	fftpack.Radix2(fa, false)
+	fftpack.Radix2(fv, false)
+	for i := range fa {
+		fa[i] *= fv[i]
+	}
+	fftpack.Radix2(fa, true)
+	ret := make(NpArray, length)
+	for i := range ret {
+		ret[i] = real(fa[i]) / float64(size)
+	}
+	return ret
+}
+
+// Convolve convolves every row of m with v.
+func (m NpStack) Convolve(v NpArray, mode ConvolveMode) (NpStack, error) {
+	ret := make(NpStack, len(m))
+	for i, row := range m {
+		var err error
+		if ret[i], err = Convolve(row, v, mode); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return ret, nil
+}
+
+// Correlate correlates every row of m with v.
+func (m NpStack) Correlate(v NpArray, mode ConvolveMode) (NpStack, error) {
+	ret := make(NpStack, len(m))
+	for i, row := range m {
+		var err error
+		if ret[i], err = Correlate(row, v, mode); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,98 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConvolve_NumpyExamples(t *testing.T) {
+	a, v := NpArray{1, 2, 3}, NpArray{0, 1, 0.5}
+	ret, err := Convolve(a, v, ConvolveFull)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 1, 2.5, 4, 1.5}, ret)
+	ret, err = Convolve(a, v, ConvolveSame)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 2.5, 4}, ret)
+	ret, err = Convolve(a, v, ConvolveValid)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{2.5}, ret)
+
+	// the shorter input may come first
+	ret, err = Convolve(NpArray{1, 2}, NpArray{1, 2, 3, 4}, ConvolveSame)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 4, 7, 10}, ret)
+}
+
+func TestCorrelate_NumpyExamples(t *testing.T) {
+	a, v := NpArray{1, 2, 3}, NpArray{0, 1, 0.5}
+	ret, err := Correlate(a, v, ConvolveValid)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{3.5}, ret)
+	ret, err = Correlate(a, v, ConvolveSame)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{2, 3.5, 3}, ret)
+	ret, err = Correlate(a, v, ConvolveFull)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0.5, 2, 3.5, 3, 0}, ret)
+	ret, err = Correlate(v, a, ConvolveFull)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 3, 3.5, 2, 0.5}, ret)
+
+	// numpy swaps a shorter a and reverses the result
+	ret, err = Correlate(NpArray{1, 2}, NpArray{1, 2, 3, 4}, ConvolveSame)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{11, 8, 5, 2}, ret)
+	ret, err = Correlate(NpArray{1, 2}, NpArray{1, 2, 3, 4}, ConvolveValid)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{11, 8, 5}, ret)
+}
+
+func TestConvolve_Errors(t *testing.T) {
+	_, err := Convolve(nil, NpArray{1}, ConvolveFull)
+	assert.Error(t, err)
+	_, err = Correlate(NpArray{1}, nil, ConvolveFull)
+	assert.Error(t, err)
+	_, err = Convolve(NpArray{1}, NpArray{1}, ConvolveMode(7))
+	assert.Error(t, err)
+	_, err = NpStack{{1, 2}, {}}.Convolve(NpArray{1}, ConvolveFull)
+	assert.Error(t, err)
+}
+
+func TestConvolve_FFTMatchesDirect(t *testing.T) {
+	r := NewRandomState(5)
+	for _, sizes := range [][2]int{{300, 65}, {200, 200}, {1000, 129}, {70, 1}} {
+		a, v := r.RandN(sizes[0]), r.RandN(sizes[1])
+		full := len(a) + len(v) - 1
+		direct := directCorrelate(a, v, len(v)-1, full)
+		assert.True(t, fftCorrelate(a, v).AlmostEqual(direct, 1e-12))
+	}
+}
+
+func TestConvolve_LargeKernel(t *testing.T) {
+	r := NewRandomState(6)
+	a, v := r.RandN(150), r.RandN(100)
+	// the textbook sum over the full support
+	full := make(NpArray, len(a)+len(v)-1)
+	for i, x := range a {
+		for j, y := range v {
+			full[i+j] += x * y
+		}
+	}
+	ret, err := Convolve(a, v, ConvolveFull)
+	assert.NoError(t, err)
+	assert.True(t, ret.AlmostEqual(full, 1e-12))
+	ret, err = Convolve(v, a, ConvolveSame)
+	assert.NoError(t, err)
+	assert.True(t, ret.AlmostEqual(full[49:199], 1e-12))
+	ret, err = Convolve(a, v, ConvolveValid)
+	assert.NoError(t, err)
+	assert.True(t, ret.AlmostEqual(full[99:150], 1e-12))
+
+	m := NpStack{a, a.MulFloat64(2)}
+	rows, err := m.Convolve(v, ConvolveFull)
+	assert.NoError(t, err)
+	assert.True(t, rows[1].AlmostEqual(full.MulFloat64(2), 1e-12))
+	rows, err = m.Correlate(v, ConvolveValid)
+	assert.NoError(t, err)
+	assert.Len(t, rows[0], 51)
+}
@@ -0,0 +1,324 @@
+package np
+
+import (
+	"fmt"
+	"math"
+)
+
+// Element is the set of types an Array can hold, the fixed size numpy dtypes
+// other than float16 and the flexible types.
+type Element interface {
+	bool | int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 | complex64 | complex128
+}
+
+// DType identifies the element type of an Array, like a numpy dtype.
+type DType int
+
+const (
+	Bool DType = iota
+	Int8
+	Int16
+	Int32
+	Int64
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+	Float32
+	Float64
+	Complex64
+	Complex128
+)
+
+var dtypeInfo = [...]struct {
+	name  string
+	kind  byte
+	width int
+}{
+	Bool:       {"bool", 'b', 1},
+	Int8:       {"int8", 'i', 1},
+	Int16:      {"int16", 'i', 2},
+	Int32:      {"int32", 'i', 4},
+	Int64:      {"int64", 'i', 8},
+	Uint8:      {"uint8", 'u', 1},
+	Uint16:     {"uint16", 'u', 2},
+	Uint32:     {"uint32", 'u', 4},
+	Uint64:     {"uint64", 'u', 8},
+	Float32:    {"float32", 'f', 4},
+	Float64:    {"float64", 'f', 8},
+	Complex64:  {"complex64", 'c', 8},
+	Complex128: {"complex128", 'c', 16},
+}
+
+func (d DType) String() string {
+	return dtypeInfo[d].name
+}
+
+// Kind is the numpy kind character: b, i, u, f or c.
+func (d DType) Kind() byte {
+	return dtypeInfo[d].kind
+}
+
+// ItemSize is the size of one element in bytes.
+func (d DType) ItemSize() int {
+	return dtypeInfo[d].width
+}
+
+// DTypeOf returns the DType of the element type T.
+func DTypeOf[T Element]() DType {
+	var zero T
+	switch any(zero).(type) {
+	case bool:
+		return Bool
+	case int8:
+		return Int8
+	case int16:
+		return Int16
+	case int32:
+		return Int32
+	case int64:
+		return Int64
+	case uint8:
+		return Uint8
+	case uint16:
+		return Uint16
+	case uint32:
+		return Uint32
+	case uint64:
+		return Uint64
+	case float32:
+		return Float32
+	case complex64:
+		return Complex64
+	case complex128:
+		return Complex128
+	}
+	return Float64
+}
+
+func (a Array[T]) DType() DType {
+	return DTypeOf[T]()
+}
+
+// Casting selects which conversions AsType allows, as numpy's casting
+// argument does.
+type Casting int
+
+const (
+	// CastNo allows no conversion at all.
+	CastNo Casting = iota
+	// CastEquiv allows byte order changes only, the same as CastNo here.
+	CastEquiv
+	// CastSafe allows conversions that preserve every value.
+	CastSafe
+	// CastSameKind allows safe conversions and conversions within a kind,
+	// such as float64 to float32.
+	CastSameKind
+	// CastUnsafe allows any conversion, numpy's default for astype.
+	CastUnsafe
+)
+
+func (c Casting) String() string {
+	return [...]string{"no", "equiv", "safe", "same_kind", "unsafe"}[c]
+}
+
+// kindOrder ranks kinds so that a cast to a later kind is never a downcast
+// of kind, numpy's ordering b < u < i < f < c.
+var kindOrder = map[byte]int{'b': 0, 'u': 1, 'i': 2, 'f': 3, 'c': 4}
+
+// CanCast reports whether from can be converted to to under the casting
+// rule, following numpy.can_cast.
+func CanCast(from, to DType, casting Casting) bool {
+	switch casting {
+	case CastNo, CastEquiv:
+		return from == to
+	case CastUnsafe:
+		return true
+	case CastSameKind:
+		if kindOrder[from.Kind()] <= kindOrder[to.Kind()] {
+			return true
+		}
+	}
+	return safeCast(from, to)
+}
+
+func safeCast(from, to DType) bool {
+	if from == to || from == Bool {
+		return true
+	}
+	fk, tk := from.Kind(), to.Kind()
+	fw, tw := from.ItemSize(), to.ItemSize()
+	switch fk {
+	case 'i':
+		switch tk {
+		case 'i':
+			return tw >= fw
+		case 'f':
+			// numpy treats 32 and 64 bit integers as safe in float64
+			return tw > fw || tw == 8
+		case 'c':
+			return tw > 2*fw || tw == 16
+		}
+	case 'u':
+		switch tk {
+		case 'u':
+			return tw >= fw
+		case 'i':
+			return tw > fw
+		case 'f':
+			return tw > fw || tw == 8
+		case 'c':
+			return tw > 2*fw || tw == 16
+		}
+	case 'f':
+		switch tk {
+		case 'f':
+			return tw >= fw
+		case 'c':
+			return tw >= 2*fw
+		}
+	case 'c':
+		return tk == 'c' && tw >= fw
+	}
+	return false
+}
+
+// AsType converts a to element type U, returning a new C-contiguous array,
+// for example AsType[uint8](a, CastUnsafe).
+func AsType[U, T Element](a Array[T], casting Casting) (Array[U], error) {
+	from, to := DTypeOf[T](), DTypeOf[U]()
+	if !CanCast(from, to, casting) {
+		return Array[U]{}, fmt.Errorf("cannot cast array data from dtype('%s') to dtype('%s') according to the rule '%s'", from, to, casting)
+	}
+	ret := Array[U]{data: make([]U, 0, a.Size()), shape: copyInts(a.shape), strides: cStrides(a.shape)}
+	a.each(func(pos int) {
+		ret.data = append(ret.data, castValue[U](a.data[pos]))
+	})
+	return ret, nil
+}
+
+// castValue converts one element the way numpy's unsafe cast does: complex
+// values lose their imaginary part, floats are truncated towards zero and
+// integers wrap.
+func castValue[U, T Element](v T) U {
+	var ret U
+	switch p := any(&ret).(type) {
+	case *bool:
+		*p = toComplex128(v) != 0
+	case *int8:
+		*p = int8(toInt64(v))
+	case *int16:
+		*p = int16(toInt64(v))
+	case *int32:
+		*p = int32(toInt64(v))
+	case *int64:
+		*p = toInt64(v)
+	case *uint8:
+		*p = uint8(toUint64(v))
+	case *uint16:
+		*p = uint16(toUint64(v))
+	case *uint32:
+		*p = uint32(toUint64(v))
+	case *uint64:
+		*p = toUint64(v)
+	case *float32:
+		*p = float32(toFloat64(v))
+	case *float64:
+		*p = toFloat64(v)
+	case *complex64:
+		*p = complex64(toComplex128(v))
+	case *complex128:
+		*p = toComplex128(v)
+	}
+	return ret
+}
+
+func toInt64[T Element](v T) int64 {
+	switch x := any(v).(type) {
+	case bool:
+		if x {
+			return 1
+		}
+		return 0
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case int64:
+		return x
+	case uint8:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint64:
+		return int64(x)
+	}
+	return int64(toFloat64(v))
+}
+
+func toUint64[T Element](v T) uint64 {
+	switch x := any(v).(type) {
+	case uint64:
+		return x
+	case float32, float64, complex64, complex128:
+		f := toFloat64(v)
+		if f >= 1<<63 {
+			return uint64(f)
+		}
+		return uint64(int64(f))
+	}
+	return uint64(toInt64(v))
+}
+
+func toFloat64[T Element](v T) float64 {
+	switch x := any(v).(type) {
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	case complex64:
+		return float64(real(x))
+	case complex128:
+		return real(x)
+	case uint64:
+		return float64(x)
+	}
+	return float64(toInt64(v))
+}
+
+func toComplex128[T Element](v T) complex128 {
+	switch x := any(v).(type) {
+	case complex128:
+		return x
+	case complex64:
+		return complex128(x)
+	}
+	return complex(toFloat64(v), 0)
+}
+
+// formatElement prints one element: floats use the NpArray format, booleans
+// and complex values are printed as numpy does.
+func formatElement[T Element](v T) string {
+	switch x := any(v).(type) {
+	case bool:
+		if x {
+			return "True"
+		}
+		return "False"
+	case float32, float64:
+		return fmt.Sprintf("%.8f", toFloat64(v))
+	case complex64, complex128:
+		c := toComplex128(v)
+		sign := "+"
+		if math.Signbit(imag(c)) {
+			sign = "-"
+		}
+		return fmt.Sprintf("%.8f%s%.8fj", real(c), sign, math.Abs(imag(c)))
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,82 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanCast_MatchesNumpy(t *testing.T) {
+	cases := []struct {
+		from, to DType
+		casting  Casting
+		expected bool
+	}{
+		{Int64, Float64, CastSafe, true},
+		{Int32, Float32, CastSafe, false},
+		{Int16, Float32, CastSafe, true},
+		{Uint8, Int8, CastSafe, false},
+		{Uint8, Int16, CastSafe, true},
+		{Uint64, Int64, CastSafe, false},
+		{Uint64, Float64, CastSafe, true},
+		{Float64, Float32, CastSafe, false},
+		{Float64, Float32, CastSameKind, true},
+		{Float64, Int64, CastSameKind, false},
+		{Int64, Uint8, CastSameKind, false},
+		{Uint8, Int8, CastSameKind, true},
+		{Bool, Uint8, CastSafe, true},
+		{Uint8, Bool, CastSameKind, false},
+		{Complex128, Float64, CastSameKind, false},
+		{Float32, Complex64, CastSafe, true},
+		{Int32, Complex64, CastSafe, false},
+		{Int64, Complex128, CastSafe, true},
+		{Float64, Int8, CastUnsafe, true},
+		{Int64, Int64, CastNo, true},
+		{Int32, Int64, CastNo, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, CanCast(c.from, c.to, c.casting), "%s -> %s %s", c.from, c.to, c.casting)
+	}
+}
+
+func TestAsType(t *testing.T) {
+	a, _ := NewNDArray([]float64{1.7, -1.7, 300, 0}, 2, 2)
+	i, err := AsType[int64](a, CastUnsafe)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, -1, 300, 0}, i.Flatten())
+	assert.Equal(t, []int{2, 2}, i.Shape())
+	assert.Equal(t, Int64, i.DType())
+
+	u, err := AsType[uint8](a, CastUnsafe)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{1, 255, 44, 0}, u.Flatten())
+
+	b, err := AsType[bool](a, CastUnsafe)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, false}, b.Flatten())
+
+	_, err = AsType[int64](a, CastSameKind)
+	assert.EqualError(t, err, "cannot cast array data from dtype('float64') to dtype('int64') according to the rule 'same_kind'")
+
+	c, _ := NewArray([]complex128{complex(1.5, 2)}, 1)
+	f, err := AsType[float64](c, CastUnsafe)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.5}, f.Flatten())
+
+	labels, _ := NewArray([]int64{3, 1, 2}, 3)
+	back, err := AsType[float64](labels, CastSafe)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 1, 2}, back.Flatten())
+}
+
+func TestArray_GenericViews(t *testing.T) {
+	mask, _ := NewArray([]bool{true, false, false, true}, 2, 2)
+	assert.Equal(t, []bool{true, false, false, true}, mask.T().Flatten())
+	assert.Equal(t, "[[True False ]\n [False True ]]", mask.String())
+
+	img := ZerosOf[uint8](2, 3)
+	img.Set(255, 1, 2)
+	row, err := img.Slice(Index(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{0, 0, 255}, row.Flatten())
+	assert.Equal(t, "[0 0 255 ]", row.String())
+}
@@ -0,0 +1,67 @@
+package np
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrShapeMismatch matches every ShapeError with errors.Is.
+	ErrShapeMismatch = errors.New("shape mismatch")
+	// ErrIndexOutOfRange matches every IndexError with errors.Is.
+	ErrIndexOutOfRange = errors.New("index out of range")
+)
+
+// ShapeError reports operands whose shapes do not fit together, with the
+// offending shapes. Without an Op it is a failure to broadcast and reads as
+// numpy's message; an Op names an operation that needs the shapes to match.
+type ShapeError struct {
+	// Op names the operation, if any.
+	Op     string
+	Shapes [][]int
+}
+
+func (e *ShapeError) Error() string {
+	shapes := formatShapes(e.Shapes)
+	switch e.Op {
+	case "":
+		return "operands could not be broadcast together with shapes " + shapes
+	case "Shape":
+		return "Shape: rows have different lengths " + shapes
+	}
+	return fmt.Sprintf("%s: operands have mismatched shapes %s", e.Op, shapes)
+}
+
+func (e *ShapeError) Is(target error) bool {
+	return target == ErrShapeMismatch
+}
+
+// IndexError reports an index outside an axis of an array.
+type IndexError struct {
+	// Op names the operation, if any.
+	Op    string
+	Index int
+	Axis  int
+	// Size is the length of the axis.
+	Size int
+}
+
+func (e *IndexError) Error() string {
+	msg := fmt.Sprintf("index %d is out of bounds for axis %d with size %d", e.Index, e.Axis, e.Size)
+	if e.Op != "" {
+		return e.Op + ": " + msg
+	}
+	return msg
+}
+
+func (e *IndexError) Is(target error) bool {
+	return target == ErrIndexOutOfRange
+}
+
+// sameLength returns a *ShapeError unless a and b have the same length.
+func sameLength(op string, a, b int) error {
+	if a != b {
+		return &ShapeError{Op: op, Shapes: [][]int{{a}, {b}}}
+	}
+	return nil
+}
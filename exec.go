@@ -0,0 +1,244 @@
+package np
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultChunkSize is the number of rows a worker takes at a time when an
+// Exec does not set one.
+const DefaultChunkSize = 64
+
+// Exec is an execution context for the row-wise operations of an NpStack.
+// Rows are handed out in chunks of consecutive rows to a pool of goroutines.
+// Every row is computed by a single goroutine into its own place in the
+// result, so results are identical whatever the number of workers or the
+// chunk size. A nil *Exec runs serially on the calling goroutine.
+type Exec struct {
+	// Workers is the number of goroutines, runtime.GOMAXPROCS(0) when zero
+	// or less.
+	Workers int
+	// ChunkSize is the number of consecutive rows a worker takes at a time,
+	// DefaultChunkSize when zero or less.
+	ChunkSize int
+}
+
+// NewExec returns an execution context with the given number of workers
+// and chunk size; zero means the default for either.
+func NewExec(workers, chunkSize int) *Exec {
+	return &Exec{Workers: workers, ChunkSize: chunkSize}
+}
+
+func (e *Exec) workers() int {
+	if e == nil {
+		return 1
+	}
+	if e.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return e.Workers
+}
+
+func (e *Exec) chunkSize() int {
+	if e == nil || e.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return e.ChunkSize
+}
+
+// serial reports whether each runs n rows on the calling goroutine.
+func (e *Exec) serial(n int) bool {
+	chunk := e.chunkSize()
+	return e.workers() <= 1 || n <= chunk
+}
+
+// rowPanic is a panic raised by f for row i.
+type rowPanic struct {
+	row   int
+	value interface{}
+}
+
+// each calls f for every i in [0, n). A panic in f is raised again on the
+// calling goroutine once all workers have stopped, the one from the lowest
+// row when there are several. Chunks are taken in order, so every chunk
+// below a failing one has already started when workers stop taking more.
+func (e *Exec) each(n int, f func(i int)) {
+	if e.serial(n) {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	chunk := e.chunkSize()
+	chunks := (n + chunk - 1) / chunk
+	workers := min(e.workers(), chunks)
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failure  *rowPanic
+		failed   atomic.Bool
+		runChunk = func(c int) {
+			i := c * chunk
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					if failure == nil || i < failure.row {
+						failure = &rowPanic{row: i, value: r}
+					}
+					mu.Unlock()
+					failed.Store(true)
+				}
+			}()
+			end := min(i+chunk, n)
+			for ; i < end; i++ {
+				f(i)
+			}
+		}
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !failed.Load() {
+				c := int(next.Add(1) - 1)
+				if c >= chunks {
+					return
+				}
+				runChunk(c)
+			}
+		}()
+	}
+	wg.Wait()
+	if failure != nil {
+		panic(failure.value)
+	}
+}
+
+// MapRows returns the stack of f applied to every row of m.
+func (e *Exec) MapRows(m NpStack, f func(i int, row NpArray) NpArray) NpStack {
+	ret := make(NpStack, len(m))
+	e.each(len(m), func(i int) {
+		ret[i] = f(i, m[i])
+	})
+	return ret
+}
+
+// ReduceRows returns f of every row of m.
+func (e *Exec) ReduceRows(m NpStack, f func(i int, row NpArray) float64) NpArray {
+	ret := make(NpArray, len(m))
+	e.each(len(m), func(i int) {
+		ret[i] = f(i, m[i])
+	})
+	return ret
+}
+
+// Stack binds m to the execution context, giving it the row-wise methods of
+// NpStack run by e.
+func (e *Exec) Stack(m NpStack) ExecStack {
+	return ExecStack{Rows: m, Exec: e}
+}
+
+// ExecStack is an NpStack whose row-wise methods run in an execution
+// context. The methods match those of NpStack and return plain NpStack and
+// NpArray values.
+type ExecStack struct {
+	Rows NpStack
+	Exec *Exec
+}
+
+// With binds m to the execution context e, for example
+// m.With(np.NewExec(8, 0)).Cond(f). A nil e runs serially.
+func (m NpStack) With(e *Exec) ExecStack {
+	return ExecStack{Rows: m, Exec: e}
+}
+
+// MapRows returns the stack of f applied to every row of m, serially.
+func (m NpStack) MapRows(f func(i int, row NpArray) NpArray) NpStack {
+	return m.With(nil).MapRows(f)
+}
+
+// MapRows returns the stack of f applied to every row.
+func (s ExecStack) MapRows(f func(i int, row NpArray) NpArray) NpStack {
+	return s.Exec.MapRows(s.Rows, f)
+}
+
+// ReduceRows returns f of every row.
+func (s ExecStack) ReduceRows(f func(i int, row NpArray) float64) NpArray {
+	return s.Exec.ReduceRows(s.Rows, f)
+}
+
+func (s ExecStack) Mean() NpArray {
+	return s.ReduceRows(func(_ int, a NpArray) float64 { return a.Mean() })
+}
+
+func (s ExecStack) ScalarMean() float64 {
+	return s.Mean().Mean()
+}
+
+func (s ExecStack) StandardDeviation() NpArray {
+	return s.ReduceRows(func(_ int, a NpArray) float64 { return a.StandardDeviation() })
+}
+
+// ScalarStandardDeviation combines the row means and standard deviations
+// into that of the whole stack, as NpStack.ScalarStandardDeviation does.
+func (s ExecStack) ScalarStandardDeviation() float64 {
+	return combinedStandardDeviation(s.StandardDeviation(), s.Mean())
+}
+
+func (s ExecStack) Max() NpArray {
+	return s.ReduceRows(func(_ int, a NpArray) float64 { return a.Max() })
+}
+
+func (s ExecStack) Min() NpArray {
+	return s.ReduceRows(func(_ int, a NpArray) float64 { return a.Min() })
+}
+
+func (s ExecStack) Sum() NpArray {
+	return s.ReduceRows(func(_ int, a NpArray) float64 { return a.Sum() })
+}
+
+// Sub subtracts the scalar b[i] from every element of row i, unlike numpy's
+// broadcasting m - b, which np.Sub provides.
+func (s ExecStack) Sub(b NpArray) NpStack {
+	return s.MapRows(func(i int, a NpArray) NpArray { return a.SubFloat64(b[i]) })
+}
+
+// Div divides every element of row i by the scalar b[i], unlike numpy's
+// broadcasting m / b, which np.Div provides.
+func (s ExecStack) Div(b NpArray) NpStack {
+	return s.MapRows(func(i int, a NpArray) NpArray { return a.DivFloat64(b[i]) })
+}
+
+func (s ExecStack) Add(b NpStack) NpStack {
+	return s.MapRows(func(i int, a NpArray) NpArray { return a.Add(b[i]) })
+}
+
+func (s ExecStack) SubSlice(b NpArray, start, end int) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.SubSlice(b, start, end) })
+}
+
+func (s ExecStack) DivSlice(b float64, start, end int) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.DivSlice(b, start, end) })
+}
+
+func (s ExecStack) SubFloat64(b float64) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.SubFloat64(b) })
+}
+
+func (s ExecStack) DivFloat64(b float64) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.DivFloat64(b) })
+}
+
+func (s ExecStack) MulFloat64(b float64) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.MulFloat64(b) })
+}
+
+func (s ExecStack) Cond(f func(float64) float64) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.Cond(f) })
+}
+
+func (s ExecStack) LinearInterpolate(scale, new_scale NpArray) NpStack {
+	return s.MapRows(func(_ int, a NpArray) NpArray { return a.LinearInterpolate(scale, new_scale) })
+}
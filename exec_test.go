@@ -0,0 +1,62 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func execStack(rows, cols int) NpStack {
+	rs := NewRandomState(3)
+	m := make(NpStack, rows)
+	for i := range m {
+		m[i] = rs.RandN(cols)
+	}
+	return m
+}
+
+func TestExec_Deterministic(t *testing.T) {
+	m := execStack(1000, 17)
+	scale := LinSpace(0, 1, 17)
+	newScale := LinSpace(0, 1, 40)
+	want := m.LinearInterpolate(scale, newScale)
+	wantMean := m.Mean()
+	for _, e := range []*Exec{nil, NewExec(1, 0), NewExec(0, 0), NewExec(3, 1), NewExec(8, 7), NewExec(64, 1000)} {
+		s := m.With(e)
+		assert.Equal(t, want, s.LinearInterpolate(scale, newScale))
+		assert.Equal(t, wantMean, s.Mean())
+		assert.Equal(t, m.StandardDeviation(), s.StandardDeviation())
+		assert.Equal(t, m.ScalarStandardDeviation(), s.ScalarStandardDeviation())
+		assert.Equal(t, m.Sub(wantMean), s.Sub(wantMean))
+		assert.Equal(t, m.DivFloat64(3), s.DivFloat64(3))
+		assert.Equal(t, m.Cond(math.Abs), s.Cond(math.Abs))
+		assert.Equal(t, m.Sum(), s.Sum())
+	}
+}
+
+func TestExec_MapRows(t *testing.T) {
+	m := NpStack{{1, 2}, {3, 4}, {5, 6}}
+	f := func(i int, row NpArray) NpArray { return row.MulFloat64(float64(i)) }
+	want := NpStack{{0, 0}, {3, 4}, {10, 12}}
+	assert.Equal(t, want, m.MapRows(f))
+	assert.Equal(t, want, NewExec(2, 1).MapRows(m, f))
+	assert.Equal(t, want, NewExec(4, 2).Stack(m).MapRows(f))
+	assert.Equal(t, NpArray{1, 3, 5}, m.With(NewExec(3, 1)).ReduceRows(func(_ int, row NpArray) float64 { return row[0] }))
+	assert.Equal(t, NpStack{}, NewExec(4, 1).MapRows(NpStack{}, f))
+	// the input is left alone
+	assert.Equal(t, NpStack{{1, 2}, {3, 4}, {5, 6}}, m)
+}
+
+func TestExec_Panic(t *testing.T) {
+	m := execStack(100, 3)
+	f := func(i int, row NpArray) NpArray {
+		if i%10 == 7 {
+			panic(i)
+		}
+		return row
+	}
+	// the panic of the lowest row is raised on the caller
+	assert.PanicsWithValue(t, 7, func() { NewExec(4, 1).MapRows(m, f) })
+	assert.PanicsWithValue(t, 7, func() { m.MapRows(f) })
+	assert.Panics(t, func() { m.With(NewExec(4, 2)).Sub(NpArray{1}) })
+}
@@ -0,0 +1,250 @@
+// Package fft computes discrete Fourier transforms of np arrays, following
+// numpy.fft. Any length is supported: powers of two use a radix-2 transform,
+// lengths with small prime factors a mixed-radix one and the rest
+// Bluestein's algorithm.
+package fft
+
+import (
+	"fmt"
+	"math"
+
+	np "github.com/mdcfrancis/gonp"
+	"github.com/mdcfrancis/gonp/internal/fftpack"
+)
+
+// Norm selects the scaling of a transform pair, the norm argument of
+// numpy.fft. The zero value is numpy's default.
+type Norm int
+
+const (
+	// Backward leaves the forward transform unscaled and scales the inverse
+	// by 1/n.
+	Backward Norm = iota
+	// Ortho scales both directions by 1/sqrt(n).
+	Ortho
+	// Forward scales the forward transform by 1/n and leaves the inverse
+	// unscaled.
+	Forward
+)
+
+func (m Norm) String() string {
+	switch m {
+	case Backward:
+		return "backward"
+	case Ortho:
+		return "ortho"
+	case Forward:
+		return "forward"
+	}
+	return fmt.Sprintf("Norm(%d)", int(m))
+}
+
+// scale returns the factor applied to a transform of length n.
+func (m Norm) scale(n int, inverse bool) (float64, error) {
+	switch {
+	case m == Ortho:
+		return 1 / math.Sqrt(float64(n)), nil
+	case m == Backward && inverse, m == Forward && !inverse:
+		return 1 / float64(n), nil
+	case m == Backward, m == Forward:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("invalid norm value %v; should be backward, ortho or forward", m)
+}
+
+// points resolves the n argument, zero meaning the input length.
+func points(n, length int) (int, error) {
+	if n == 0 {
+		n = length
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("invalid number of FFT data points (%d) specified", n)
+	}
+	return n, nil
+}
+
+func transform(x []complex128, n int, norm Norm, inverse bool) ([]complex128, error) {
+	n, err := points(n, len(x))
+	if err != nil {
+		return nil, err
+	}
+	scale, err := norm.scale(n, inverse)
+	if err != nil {
+		return nil, err
+	}
+	// crop or zero pad to n points
+	ret := make([]complex128, n)
+	copy(ret, x)
+	fftpack.Transform(ret, inverse)
+	if scale != 1 {
+		for i := range ret {
+			ret[i] *= complex(scale, 0)
+		}
+	}
+	return ret, nil
+}
+
+// FFT returns the n point discrete Fourier transform of x, like
+// numpy.fft.fft. x is cropped or zero padded to n points, n of zero uses
+// len(x).
+func FFT(x []complex128, n int, norm Norm) ([]complex128, error) {
+	return transform(x, n, norm, false)
+}
+
+// IFFT returns the n point inverse transform of x, like numpy.fft.ifft.
+func IFFT(x []complex128, n int, norm Norm) ([]complex128, error) {
+	return transform(x, n, norm, true)
+}
+
+// RFFT returns the n/2+1 non-negative frequency terms of the transform of
+// real input, like numpy.fft.rfft.
+func RFFT(x np.NpArray, n int, norm Norm) ([]complex128, error) {
+	n, err := points(n, len(x))
+	if err != nil {
+		return nil, err
+	}
+	c := make([]complex128, n)
+	for i := 0; i < n && i < len(x); i++ {
+		c[i] = complex(x[i], 0)
+	}
+	ret, err := transform(c, n, norm, false)
+	if err != nil {
+		return nil, err
+	}
+	return ret[:n/2+1], nil
+}
+
+// IRFFT inverts RFFT, returning n real points, like numpy.fft.irfft. n of
+// zero uses 2*(len(x)-1). The imaginary parts of the zero and, for even n,
+// Nyquist frequency terms are ignored.
+func IRFFT(x []complex128, n int, norm Norm) (np.NpArray, error) {
+	if n == 0 {
+		n = 2 * (len(x) - 1)
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of data points (%d) specified", n)
+	}
+	// rebuild the Hermitian spectrum from the first n/2+1 terms
+	c := make([]complex128, n)
+	for k := 0; k <= n/2 && k < len(x); k++ {
+		c[k] = x[k]
+		if k > 0 && n-k != k {
+			c[n-k] = complex(real(x[k]), -imag(x[k]))
+		}
+	}
+	c[0] = complex(real(c[0]), 0)
+	if n%2 == 0 {
+		c[n/2] = complex(real(c[n/2]), 0)
+	}
+	full, err := transform(c, n, norm, true)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(np.NpArray, n)
+	for i, v := range full {
+		ret[i] = real(v)
+	}
+	return ret, nil
+}
+
+// FFTFreq returns the sample frequencies of an n point transform with
+// sample spacing d, like numpy.fft.fftfreq.
+func FFTFreq(n int, d float64) np.NpArray {
+	val := 1.0 / (float64(n) * d)
+	ret := make(np.NpArray, n)
+	half := (n-1)/2 + 1
+	for i := range ret {
+		k := i
+		if i >= half {
+			k = i - n
+		}
+		ret[i] = float64(k) * val
+	}
+	return ret
+}
+
+// RFFTFreq returns the n/2+1 sample frequencies of RFFT, like
+// numpy.fft.rfftfreq.
+func RFFTFreq(n int, d float64) np.NpArray {
+	val := 1.0 / (float64(n) * d)
+	ret := make(np.NpArray, n/2+1)
+	for i := range ret {
+		ret[i] = float64(i) * val
+	}
+	return ret
+}
+
+// FFTShift moves the zero frequency term to the centre, like
+// numpy.fft.fftshift.
+func FFTShift[S ~[]E, E any](x S) S {
+	return roll(x, len(x)/2)
+}
+
+// IFFTShift undoes FFTShift, like numpy.fft.ifftshift.
+func IFFTShift[S ~[]E, E any](x S) S {
+	return roll(x, -(len(x) / 2))
+}
+
+func roll[S ~[]E, E any](x S, shift int) S {
+	n := len(x)
+	ret := make(S, n)
+	for i, v := range x {
+		ret[((i+shift)%n+n)%n] = v
+	}
+	return ret
+}
+
+// RFFTRows applies RFFT to every row of m.
+func RFFTRows(m np.NpStack, n int, norm Norm) ([][]complex128, error) {
+	ret := make([][]complex128, len(m))
+	for i, row := range m {
+		var err error
+		if ret[i], err = RFFT(row, n, norm); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return ret, nil
+}
+
+// IRFFTRows applies IRFFT to every row of x.
+func IRFFTRows(x [][]complex128, n int, norm Norm) (np.NpStack, error) {
+	ret := make(np.NpStack, len(x))
+	for i, row := range x {
+		var err error
+		if ret[i], err = IRFFT(row, n, norm); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return ret, nil
+}
+
+// FFTRows applies FFT to every row of x.
+func FFTRows(x [][]complex128, n int, norm Norm) ([][]complex128, error) {
+	return rows(x, n, norm, false)
+}
+
+// IFFTRows applies IFFT to every row of x.
+func IFFTRows(x [][]complex128, n int, norm Norm) ([][]complex128, error) {
+	return rows(x, n, norm, true)
+}
+
+func rows(x [][]complex128, n int, norm Norm, inverse bool) ([][]complex128, error) {
+	ret := make([][]complex128, len(x))
+	for i, row := range x {
+		var err error
+		if ret[i], err = transform(row, n, norm, inverse); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return ret, nil
+}
+
+// Abs returns the magnitude of each term, the amplitude spectrum of a
+// transform.
+func Abs(x []complex128) np.NpArray {
+	ret := make(np.NpArray, len(x))
+	for i, v := range x {
+		ret[i] = math.Hypot(real(v), imag(v))
+	}
+	return ret
+}
@@ -0,0 +1,173 @@
+package fft
+
+import (
+	np "github.com/mdcfrancis/gonp"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func assertComplex(t *testing.T, want, got []complex128, tol float64) {
+	t.Helper()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		assert.InDelta(t, 0, cmplx.Abs(want[i]-got[i]), tol, "index %d: want %v got %v", i, want[i], got[i])
+	}
+}
+
+func TestFFT_NumpyExamples(t *testing.T) {
+	x := make([]complex128, 8)
+	for i := range x {
+		x[i] = cmplx.Exp(complex(0, 2*math.Pi*float64(i)/8))
+	}
+	ret, err := FFT(x, 0, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{0, 8, 0, 0, 0, 0, 0, 0}, ret, 1e-14)
+
+	ret, err = FFT([]complex128{0, 1, 0, 0}, 0, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{1, -1i, -1, 1i}, ret, 0)
+
+	ret, err = IFFT([]complex128{0, 4, 0, 0}, 0, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{1, 1i, -1, -1i}, ret, 0)
+
+	ret, err = RFFT(np.NpArray{0, 1, 0, 0}, 0, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{1, -1i, -1}, ret, 0)
+
+	real, err := IRFFT([]complex128{1, -1i, -1}, 0, Backward)
+	assert.NoError(t, err)
+	assert.True(t, real.AlmostEqual(np.NpArray{0, 1, 0, 0}, 1e-15))
+}
+
+func TestFFT_MatchesDFT(t *testing.T) {
+	r := np.NewRandomState(1)
+	for _, n := range []int{1, 2, 7, 16, 30, 49, 64, 101, 210, 257} {
+		re, im := r.RandN(n), r.RandN(n)
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(re[i], im[i])
+		}
+		want := make([]complex128, n)
+		for k := range want {
+			for j, v := range x {
+				want[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k%n)/float64(n)))
+			}
+		}
+		got, err := FFT(x, 0, Backward)
+		assert.NoError(t, err)
+		assertComplex(t, want, got, 1e-11*float64(n))
+
+		back, err := IFFT(got, 0, Backward)
+		assert.NoError(t, err)
+		assertComplex(t, x, back, 1e-13*float64(n))
+	}
+}
+
+func TestFFT_Norm(t *testing.T) {
+	x := []complex128{1, 2, 3, 4, 5}
+	backward, _ := FFT(x, 0, Backward)
+	ortho, err := FFT(x, 0, Ortho)
+	assert.NoError(t, err)
+	forward, err := FFT(x, 0, Forward)
+	assert.NoError(t, err)
+	for i := range x {
+		assert.InDelta(t, 0, cmplx.Abs(backward[i]/complex(math.Sqrt(5), 0)-ortho[i]), 1e-14)
+		assert.InDelta(t, 0, cmplx.Abs(backward[i]/5-forward[i]), 1e-14)
+	}
+	for _, norm := range []Norm{Backward, Ortho, Forward} {
+		f, _ := FFT(x, 0, norm)
+		back, err := IFFT(f, 0, norm)
+		assert.NoError(t, err)
+		assertComplex(t, x, back, 1e-14)
+	}
+	_, err = FFT(x, 0, Norm(4))
+	assert.Error(t, err)
+}
+
+func TestFFT_Points(t *testing.T) {
+	// n crops or zero pads the input
+	ret, err := FFT([]complex128{1, 2, 3, 4}, 2, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{3, -1}, ret, 0)
+	ret, err = FFT([]complex128{1}, 3, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{1, 1, 1}, ret, 1e-15)
+
+	_, err = FFT(nil, 0, Backward)
+	assert.Error(t, err)
+	_, err = FFT([]complex128{1}, -1, Backward)
+	assert.Error(t, err)
+	_, err = IRFFT([]complex128{1}, 0, Backward)
+	assert.Error(t, err)
+}
+
+func TestRFFT_RoundTrip(t *testing.T) {
+	r := np.NewRandomState(2)
+	for _, n := range []int{8, 9, 15, 100, 101} {
+		x := r.RandN(n)
+		c, err := RFFT(x, 0, Backward)
+		assert.NoError(t, err)
+		assert.Len(t, c, n/2+1)
+		full, _ := FFT(complexOf(x), 0, Backward)
+		assertComplex(t, full[:n/2+1], c, 1e-12)
+
+		back, err := IRFFT(c, n, Backward)
+		assert.NoError(t, err)
+		assert.True(t, back.AlmostEqual(x, 1e-12))
+	}
+
+	// the imaginary part of the Nyquist term is dropped
+	ret, err := IRFFT([]complex128{4, 0, 2i}, 4, Backward)
+	assert.NoError(t, err)
+	assert.Equal(t, np.NpArray{1, 1, 1, 1}, ret)
+}
+
+func complexOf(x np.NpArray) []complex128 {
+	ret := make([]complex128, len(x))
+	for i, v := range x {
+		ret[i] = complex(v, 0)
+	}
+	return ret
+}
+
+func TestFreqAndShift(t *testing.T) {
+	assert.True(t, FFTFreq(8, 0.1).AlmostEqual(np.NpArray{0, 1.25, 2.5, 3.75, -5, -3.75, -2.5, -1.25}, 1e-15))
+	assert.True(t, RFFTFreq(10, 0.01).AlmostEqual(np.NpArray{0, 10, 20, 30, 40, 50}, 1e-12))
+
+	freq := FFTFreq(10, 0.1)
+	assert.True(t, FFTShift(freq).AlmostEqual(np.NpArray{-5, -4, -3, -2, -1, 0, 1, 2, 3, 4}, 1e-15))
+	assert.Equal(t, freq, IFFTShift(FFTShift(freq)))
+	odd := np.NpArray{0, 1, 2, -2, -1}
+	assert.Equal(t, np.NpArray{-2, -1, 0, 1, 2}, FFTShift(odd))
+	assert.Equal(t, odd, IFFTShift(FFTShift(odd)))
+	assert.Equal(t, []complex128{2, 0, 1}, FFTShift([]complex128{0, 1, 2}))
+}
+
+func TestRows(t *testing.T) {
+	m := np.NpStack{{1, 0, 0, 0}, {0, 1, 0, 0}, {1, 1, 1, 1}}
+	spec, err := RFFTRows(m, 0, Backward)
+	assert.NoError(t, err)
+	assertComplex(t, []complex128{1, 1, 1}, spec[0], 0)
+	assertComplex(t, []complex128{4, 0, 0}, spec[2], 0)
+	assert.Equal(t, np.NpArray{1, 1, 1}, Abs(spec[1]))
+
+	back, err := IRFFTRows(spec, 4, Backward)
+	assert.NoError(t, err)
+	for i := range m {
+		assert.True(t, back[i].AlmostEqual(m[i], 1e-15))
+	}
+
+	full, err := FFTRows([][]complex128{complexOf(m[1])}, 0, Ortho)
+	assert.NoError(t, err)
+	inv, err := IFFTRows(full, 0, Ortho)
+	assert.NoError(t, err)
+	assertComplex(t, complexOf(m[1]), inv[0], 1e-15)
+
+	_, err = RFFTRows(np.NpStack{{1}, {}}, 0, Backward)
+	assert.Error(t, err)
+}
@@ -0,0 +1,318 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Generator reproduces the streams of numpy.random.Generator, the API behind
+// np.random.default_rng, for a given BitGenerator. Invalid parameters panic,
+// as they raise in numpy.
+type Generator struct {
+	bg BitGenerator
+}
+
+func NewGenerator(bg BitGenerator) *Generator {
+	return &Generator{bg: bg}
+}
+
+// DefaultRNG is np.random.default_rng(seed), a PCG64 seeded through a
+// SeedSequence.
+func DefaultRNG(seed uint64) *Generator {
+	return NewGenerator(NewPCG64(NewSeedSequence(seed)))
+}
+
+func (g *Generator) BitGenerator() BitGenerator {
+	return g.bg
+}
+
+func (g *Generator) float64() float64 {
+	return float64(g.bg.Uint64()>>11) * (1.0 / 9007199254740992.0)
+}
+
+// Random returns n samples in [0, 1), like random(n).
+func (g *Generator) Random(n int) NpArray {
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = g.float64()
+	}
+	return ret
+}
+
+// StandardNormal returns n samples drawn with numpy's 256 level ziggurat,
+// like standard_normal(n).
+func (g *Generator) StandardNormal(n int) NpArray {
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = g.normFloat64()
+	}
+	return ret
+}
+
+func (g *Generator) Normal(loc, scale float64, n int) NpArray {
+	if scale < 0 {
+		panic(fmt.Errorf("scale < 0"))
+	}
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = loc + scale*g.normFloat64()
+	}
+	return ret
+}
+
+// Integers returns n integers in [low, high), like integers(low, high, n).
+func (g *Generator) Integers(low, high, n int) []int {
+	if low >= high {
+		panic(fmt.Errorf("low >= high"))
+	}
+	rng := uint64(high) - uint64(low) - 1
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = low + int(g.bounded(rng))
+	}
+	return ret
+}
+
+// bounded draws from [0, rng] with Lemire's multiply and reject method,
+// numpy's random_bounded_uint64 without masking.
+func (g *Generator) bounded(rng uint64) uint64 {
+	switch {
+	case rng == 0:
+		return 0
+	case rng == math.MaxUint32:
+		return uint64(g.bg.Uint32())
+	case rng < math.MaxUint32:
+		excl := uint32(rng) + 1
+		m := uint64(g.bg.Uint32()) * uint64(excl)
+		if uint32(m) < excl {
+			threshold := (math.MaxUint32 - uint32(rng)) % excl
+			for uint32(m) < threshold {
+				m = uint64(g.bg.Uint32()) * uint64(excl)
+			}
+		}
+		return m >> 32
+	case rng == math.MaxUint64:
+		return g.bg.Uint64()
+	}
+	excl := rng + 1
+	hi, lo := bits.Mul64(g.bg.Uint64(), excl)
+	if lo < excl {
+		threshold := (math.MaxUint64 - rng) % excl
+		for lo < threshold {
+			hi, lo = bits.Mul64(g.bg.Uint64(), excl)
+		}
+	}
+	return hi
+}
+
+// interval draws from [0, max] by rejection on a bit mask, numpy's
+// random_interval, which Generator still uses for shuffling.
+func (g *Generator) interval(max uint64) uint64 {
+	if max == 0 {
+		return 0
+	}
+	mask := genMask(max)
+	if max <= math.MaxUint32 {
+		for {
+			if v := uint64(g.bg.Uint32()) & mask; v <= max {
+				return v
+			}
+		}
+	}
+	for {
+		if v := g.bg.Uint64() & mask; v <= max {
+			return v
+		}
+	}
+}
+
+// genMask returns the smallest all ones bit mask covering max.
+func genMask(max uint64) uint64 {
+	mask := max
+	mask |= mask >> 1
+	mask |= mask >> 2
+	mask |= mask >> 4
+	mask |= mask >> 8
+	mask |= mask >> 16
+	mask |= mask >> 32
+	return mask
+}
+
+// Shuffle shuffles a in place, like shuffle(a).
+func (g *Generator) Shuffle(a NpArray) {
+	for i := len(a) - 1; i > 0; i-- {
+		j := g.interval(uint64(i))
+		a[i], a[j] = a[j], a[i]
+	}
+}
+
+// Permutation returns a shuffled range of n integers, like permutation(n).
+func (g *Generator) Permutation(n int) []int {
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := g.interval(uint64(i))
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret
+}
+
+// Choice draws size indices from range(a), like choice(a, size, replace, p).
+// p may be nil for a uniform choice. Samples without replacement are
+// shuffled, as numpy does by default.
+func (g *Generator) Choice(a, size int, replace bool, p NpArray) []int {
+	if a <= 0 {
+		panic(fmt.Errorf("a must be a positive integer unless no samples are taken"))
+	}
+	if p != nil {
+		checkProbabilities(a, p)
+	}
+	switch {
+	case replace && p == nil:
+		return g.Integers(0, a, size)
+	case replace:
+		return searchCDF(cumulative(p), g.Random(size))
+	case size > a:
+		panic(fmt.Errorf("cannot take a larger sample than population when replace is false"))
+	case p != nil:
+		return choiceWeighted(a, size, p, g.Random)
+	}
+	if a > 10000 && size > a/50 {
+		// shuffle only the tail that is returned
+		idx := make([]int, a)
+		for i := range idx {
+			idx[i] = i
+		}
+		first := a - size
+		if first < 1 {
+			first = 1
+		}
+		g.shuffleInts(idx, first)
+		return append([]int(nil), idx[a-size:]...)
+	}
+	// Floyd's algorithm with an open addressed hash set
+	idx := make([]int, size)
+	mask := genMask(uint64(1.2 * float64(size)))
+	set := make([]uint64, mask+1)
+	const empty = math.MaxUint64
+	for i := range set {
+		set[i] = empty
+	}
+	for j := a - size; j < a; j++ {
+		val := g.bounded(uint64(j))
+		loc := val & mask
+		for set[loc] != empty && set[loc] != val {
+			loc = (loc + 1) & mask
+		}
+		if set[loc] == empty {
+			set[loc] = val
+			idx[j-a+size] = int(val)
+			continue
+		}
+		loc = uint64(j) & mask
+		for set[loc] != empty {
+			loc = (loc + 1) & mask
+		}
+		set[loc] = uint64(j)
+		idx[j-a+size] = j
+	}
+	g.shuffleInts(idx, 1)
+	return idx
+}
+
+// shuffleInts shuffles idx[first:] against all of idx with Lemire bounded
+// draws, numpy's _shuffle_int.
+func (g *Generator) shuffleInts(idx []int, first int) {
+	for i := len(idx) - 1; i >= first; i-- {
+		j := g.bounded(uint64(i))
+		idx[i], idx[j] = idx[j], idx[i]
+	}
+}
+
+// checkProbabilities validates p as numpy's choice does.
+func checkProbabilities(a int, p NpArray) {
+	if len(p) != a {
+		panic(fmt.Errorf("a and p must have same size"))
+	}
+	sum, comp := 0.0, 0.0
+	for _, v := range p {
+		if v < 0 || math.IsNaN(v) {
+			panic(fmt.Errorf("probabilities are not non-negative"))
+		}
+		// Kahan summation, as numpy checks the total
+		y := v - comp
+		t := sum + y
+		comp = (t - sum) - y
+		sum = t
+	}
+	if math.Abs(sum-1) > math.Sqrt(2.220446049250313e-16) {
+		panic(fmt.Errorf("probabilities do not sum to 1"))
+	}
+}
+
+// choiceWeighted draws size distinct indices with probabilities p by
+// repeatedly sampling and zeroing the weights already found.
+func choiceWeighted(a, size int, p NpArray, random func(n int) NpArray) []int {
+	nonzero := 0
+	for _, v := range p {
+		if v > 0 {
+			nonzero++
+		}
+	}
+	if nonzero < size {
+		panic(fmt.Errorf("fewer non-zero entries in p than size"))
+	}
+	found := make([]int, 0, size)
+	p = append(NpArray(nil), p...)
+	for len(found) < size {
+		x := random(size - len(found))
+		for _, idx := range found {
+			p[idx] = 0
+		}
+		// keep the first occurrence of each new index, in draw order
+		seen := map[int]bool{}
+		for _, idx := range searchCDF(cumulative(p), x) {
+			if !seen[idx] {
+				seen[idx] = true
+				found = append(found, idx)
+			}
+		}
+	}
+	return found
+}
+
+func (g *Generator) normFloat64() float64 {
+	for {
+		r := g.bg.Uint64()
+		idx := r & 0xff
+		r >>= 8
+		sign := r & 0x1
+		rabs := (r >> 1) & 0x000fffffffffffff
+		x := float64(rabs) * zigWi[idx]
+		if sign != 0 {
+			x = -x
+		}
+		if rabs < zigKi[idx] {
+			return x
+		}
+		if idx == 0 {
+			for {
+				xx := -zigNorInvR * log1pLibm(-g.float64())
+				yy := -log1pLibm(-g.float64())
+				if yy+yy > xx*xx {
+					if (rabs>>8)&0x1 != 0 {
+						return -(zigNorR + xx)
+					}
+					return zigNorR + xx
+				}
+			}
+		}
+		// numpy's baseline build does not fuse these products
+		if float64((zigFi[idx-1]-zigFi[idx])*g.float64())+zigFi[idx] < expLibm(float64(-0.5*x)*x) {
+			return x
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestDefaultRNG_Random(t *testing.T) {
+	g := DefaultRNG(42)
+	expected := NpArray{0.7739560485559633, 0.4388784397520523, 0.8585979199113825, 0.6973680290593639, 0.09417734788764953}
+	assert.Equal(t, expected, g.Random(5))
+}
+
+func TestDefaultRNG_Integers(t *testing.T) {
+	assert.Equal(t, []int{0, 7, 6, 4, 4, 8, 0, 6, 2, 0}, DefaultRNG(42).Integers(0, 10, 10))
+	for _, v := range DefaultRNG(1).Integers(-5, 1<<40, 100) {
+		assert.True(t, v >= -5 && v < 1<<40)
+	}
+}
+
+func TestDefaultRNG_StandardNormal(t *testing.T) {
+	// numpy documents [0.30471708, -1.03998411, 0.7504512, 0.94056472, ...]
+	expected := NpArray{0.30471707975443135, -1.0399841062404955, 0.7504511958064574, 0.9405647163912139, -1.9510351886538364,
+		-1.302179506862318, 0.12784040316728537, -0.3162425923435822, -0.016801157504288806, -0.85304392757358}
+	assert.Equal(t, expected, DefaultRNG(42).StandardNormal(10))
+
+	res := DefaultRNG(7).Normal(3, 2, 20000)
+	assert.InDelta(t, 3, res.Mean(), 0.05)
+	assert.InDelta(t, 2, res.StandardDeviation(), 0.05)
+}
+
+func TestDefaultRNG_Permutation(t *testing.T) {
+	assert.Equal(t, []int{5, 6, 0, 7, 3, 2, 4, 9, 1, 8}, DefaultRNG(42).Permutation(10))
+	a := NpArray{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	DefaultRNG(42).Shuffle(a)
+	assert.Equal(t, NpArray{5, 6, 0, 7, 3, 2, 4, 9, 1, 8}, a)
+}
+
+func TestGenerator_Choice(t *testing.T) {
+	g := DefaultRNG(42)
+	assert.Equal(t, []int{0, 7, 6, 4, 4}, g.Choice(10, 5, true, nil))
+
+	for _, c := range []struct{ a, size int }{{10, 10}, {100, 7}, {20000, 1000}} {
+		picked := g.Choice(c.a, c.size, false, nil)
+		assert.Len(t, picked, c.size)
+		seen := map[int]bool{}
+		for _, v := range picked {
+			assert.True(t, v >= 0 && v < c.a)
+			assert.False(t, seen[v])
+			seen[v] = true
+		}
+	}
+
+	p := NpArray{0.1, 0, 0.6, 0.3}
+	assert.Equal(t, []int{3, 2, 0}, DefaultRNG(42).Choice(4, 3, false, p))
+	assert.Panics(t, func() { g.Choice(4, 3, true, NpArray{0.1, 0.2}) })
+	assert.Panics(t, func() { g.Choice(4, 5, false, nil) })
+}
+
+func TestPhilox_KnownAnswer(t *testing.T) {
+	// Random123 known answer for a zero counter and key, the counter wraps
+	// to zero on the first draw.
+	g := &Philox{ctr: [4]uint64{math.MaxUint64, math.MaxUint64, math.MaxUint64, math.MaxUint64}, outPos: 4}
+	expected := []uint64{0x16554d9eca36314c, 0xdb20fe9d672d0fdc, 0xd7e772cee186176b, 0x7e68b68aec7ba23b}
+	for _, e := range expected {
+		assert.Equal(t, e, g.Uint64())
+	}
+}
+
+func TestBitGenerators_Uint32Halves(t *testing.T) {
+	sources := []func() BitGenerator{
+		func() BitGenerator { return NewPCG64(NewSeedSequence(3)) },
+		func() BitGenerator { return NewPhilox(NewSeedSequence(3)) },
+		func() BitGenerator { return NewSFC64(NewSeedSequence(3)) },
+	}
+	for _, source := range sources {
+		a, b := source(), source()
+		v := a.Uint64()
+		assert.Equal(t, uint32(v), b.Uint32())
+		assert.Equal(t, uint32(v>>32), b.Uint32())
+		mean := NewGenerator(source()).Random(20000).Mean()
+		assert.InDelta(t, 0.5, mean, 0.01)
+	}
+}
+
+func TestSeedSequence_Entropy(t *testing.T) {
+	// a 64 bit seed is split into its 32 bit words
+	assert.Equal(t, NewSeedSequence(1, 2).GenerateState32(4), NewSeedSequence(1|2<<32).GenerateState32(4))
+	assert.NotEqual(t, NewSeedSequence(1).GenerateState32(4), NewSeedSequence(2).GenerateState32(4))
+	words := NewSeedSequence(5).GenerateState32(4)
+	wide := NewSeedSequence(5).GenerateState64(2)
+	assert.Equal(t, uint64(words[0])|uint64(words[1])<<32, wide[0])
+}
@@ -0,0 +1,339 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BinEstimator names one of numpy's estimators of the histogram bin width,
+// the string forms of the bins argument of numpy.histogram.
+type BinEstimator string
+
+const (
+	// BinsAuto takes the smaller of the Freedman Diaconis and Sturges
+	// widths, falling back to Sturges when the IQR is zero.
+	BinsAuto BinEstimator = "auto"
+	// BinsFD is the Freedman Diaconis estimator, 2 IQR n^(-1/3).
+	BinsFD BinEstimator = "fd"
+	// BinsSturges fits log2(n) + 1 bins to the range.
+	BinsSturges BinEstimator = "sturges"
+	// BinsDoane extends Sturges for skewed data.
+	BinsDoane BinEstimator = "doane"
+	// BinsScott is Scott's normal reference rule.
+	BinsScott BinEstimator = "scott"
+	// BinsRice fits 2 n^(1/3) bins to the range.
+	BinsRice BinEstimator = "rice"
+	// BinsSqrt fits sqrt(n) bins to the range.
+	BinsSqrt BinEstimator = "sqrt"
+)
+
+// HistogramOptions are the optional arguments of Histogram. Bins, Edges and
+// Estimator are alternatives; with none of them set there are ten bins.
+type HistogramOptions struct {
+	// Bins is the number of equal width bins.
+	Bins int
+	// Edges gives the bin edges, monotonically increasing.
+	Edges NpArray
+	// Estimator chooses the number of equal width bins from the data.
+	Estimator BinEstimator
+	// Range is the lower and upper edge of the bins, the extent of the data
+	// when nil. Values outside it are ignored.
+	Range *[2]float64
+	// Weights weighs each value of a instead of counting it as one.
+	Weights NpArray
+	// Density normalises the result so that it integrates to one over the
+	// range.
+	Density bool
+}
+
+// binWidth returns the width chosen by the estimator for the values x.
+func binWidth(e BinEstimator, x NpArray) float64 {
+	n := float64(len(x))
+	ptp := x.Max() - x.Min()
+	switch e {
+	case BinsSqrt:
+		return ptp / math.Sqrt(n)
+	case BinsSturges:
+		return ptp / (math.Log2(n) + 1)
+	case BinsRice:
+		return ptp / (2 * math.Pow(n, 1.0/3))
+	case BinsScott:
+		std := math.Sqrt(reduction{all: true}.variance(x, 0))
+		return math.Pow(24*math.Sqrt(math.Pi)/n, 1.0/3) * std
+	case BinsDoane:
+		if len(x) <= 2 {
+			return 0
+		}
+		sg1 := math.Sqrt(6.0 * (n - 2) / ((n + 1.0) * (n + 3)))
+		sigma := math.Sqrt(reduction{all: true}.variance(x, 0))
+		if !(sigma > 0) {
+			return 0
+		}
+		mean := pairwiseSum(x) / n
+		cubes := make(NpArray, len(x))
+		for i, v := range x {
+			t := (v - mean) / sigma
+			cubes[i] = t * t * t
+		}
+		g1 := pairwiseSum(cubes) / n
+		return ptp / (1.0 + math.Log2(n) + math.Log2(1.0+math.Abs(g1)/sg1))
+	case BinsFD:
+		sorted := x.Copy()
+		sort.Float64s(sorted)
+		iqr := quantileSorted(sorted, 0.75, QuantileLinear) - quantileSorted(sorted, 0.25, QuantileLinear)
+		return 2.0 * iqr * math.Pow(n, -1.0/3)
+	case BinsAuto:
+		fd := binWidth(BinsFD, x)
+		sturges := binWidth(BinsSturges, x)
+		if fd != 0 {
+			return math.Min(fd, sturges)
+		}
+		return sturges
+	}
+	return 0
+}
+
+// outerEdges returns the range of the bins, widened by a half either side
+// when it is empty.
+func outerEdges(a NpArray, r *[2]float64) (float64, float64, error) {
+	var first, last float64
+	switch {
+	case r != nil:
+		first, last = r[0], r[1]
+		if first > last {
+			return 0, 0, fmt.Errorf("max must be larger than min in range parameter")
+		}
+		if math.IsInf(first, 0) || math.IsNaN(first) || math.IsInf(last, 0) || math.IsNaN(last) {
+			return 0, 0, fmt.Errorf("supplied range of [%v, %v] is not finite", first, last)
+		}
+	case len(a) == 0:
+		first, last = 0, 1
+	default:
+		first, last = a[0], a[0]
+		for _, v := range a {
+			if math.IsNaN(v) {
+				first, last = v, v
+				break
+			}
+			first, last = math.Min(first, v), math.Max(last, v)
+		}
+		if math.IsInf(first, 0) || math.IsNaN(first) || math.IsInf(last, 0) || math.IsNaN(last) {
+			return 0, 0, fmt.Errorf("autodetected range of [%v, %v] is not finite", first, last)
+		}
+	}
+	if first == last {
+		first, last = first-0.5, last+0.5
+	}
+	return first, last, nil
+}
+
+// binEdges follows numpy's _get_bin_edges. For equal width bins it also
+// returns their number, zero otherwise.
+func binEdges(a NpArray, opts *HistogramOptions) (NpArray, int, error) {
+	if opts == nil {
+		opts = &HistogramOptions{}
+	}
+	if opts.Weights != nil && len(opts.Weights) != len(a) {
+		return nil, 0, fmt.Errorf("weights should have the same shape as a")
+	}
+	switch {
+	case opts.Edges != nil:
+		if len(opts.Edges) == 0 {
+			return nil, 0, fmt.Errorf("bins must have at least one edge")
+		}
+		for i := 1; i < len(opts.Edges); i++ {
+			if opts.Edges[i-1] > opts.Edges[i] {
+				return nil, 0, fmt.Errorf("bins must increase monotonically, when an array")
+			}
+		}
+		return opts.Edges.Copy(), 0, nil
+	case opts.Estimator != "":
+		switch opts.Estimator {
+		case BinsAuto, BinsFD, BinsSturges, BinsDoane, BinsScott, BinsRice, BinsSqrt:
+		default:
+			return nil, 0, fmt.Errorf("%q is not a valid estimator for bins", string(opts.Estimator))
+		}
+		if opts.Weights != nil {
+			return nil, 0, fmt.Errorf("automated estimation of the number of bins is not supported for weighted data")
+		}
+		first, last, err := outerEdges(a, opts.Range)
+		if err != nil {
+			return nil, 0, err
+		}
+		kept := a
+		if opts.Range != nil {
+			kept = make(NpArray, 0, len(a))
+			for _, v := range a {
+				if v >= first && v <= last {
+					kept = append(kept, v)
+				}
+			}
+		}
+		n := 1
+		if len(kept) > 0 {
+			if width := binWidth(opts.Estimator, kept); width != 0 {
+				n = int(math.Ceil((last - first) / width))
+			}
+		}
+		return LinSpace(first, last, n+1), n, nil
+	}
+	n := opts.Bins
+	if n == 0 {
+		n = 10
+	}
+	if n < 1 {
+		return nil, 0, fmt.Errorf("bins must be positive, when an integer")
+	}
+	first, last, err := outerEdges(a, opts.Range)
+	if err != nil {
+		return nil, 0, err
+	}
+	edges := LinSpace(first, last, n+1)
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i-1] < edges[i]) {
+			return nil, 0, fmt.Errorf("too many bins for data range, cannot create %d finite-sized bins", n)
+		}
+	}
+	return edges, n, nil
+}
+
+// HistogramBinEdges returns the edges of the bins Histogram would use, like
+// numpy.histogram_bin_edges.
+func HistogramBinEdges(a NpArray, opts *HistogramOptions) (NpArray, error) {
+	edges, _, err := binEdges(a, opts)
+	return edges, err
+}
+
+// Histogram counts the values of a in each bin, like numpy.histogram. Every
+// bin but the last is half open, [edges[i], edges[i+1]), and the last one
+// includes its right edge. It returns the counts (or weight sums, or
+// densities) and the len(hist)+1 bin edges.
+func Histogram(a NpArray, opts *HistogramOptions) (hist, edges NpArray, err error) {
+	edges, n, err := binEdges(a, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var weights NpArray
+	density := false
+	if opts != nil {
+		weights, density = opts.Weights, opts.Density
+	}
+	weight := func(i int) float64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[i]
+	}
+	if n > 0 {
+		// equal width bins are found directly, then nudged to agree with
+		// the edges within rounding
+		hist = make(NpArray, n)
+		first, last := edges[0], edges[n]
+		for i, v := range a {
+			if !(v >= first && v <= last) {
+				continue
+			}
+			k := int((v - first) / (last - first) * float64(n))
+			if k == n {
+				k--
+			}
+			if v < edges[k] {
+				k--
+			}
+			if k != n-1 && v >= edges[k+1] {
+				k++
+			}
+			hist[k] += weight(i)
+		}
+	} else {
+		// otherwise from the cumulative counts of the sorted values
+		order := make([]int, len(a))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return a[order[i]] < a[order[j]] })
+		sorted := make(NpArray, len(a))
+		cum := make(NpArray, len(a)+1)
+		for i, k := range order {
+			sorted[i] = a[k]
+			cum[i+1] = cum[i] + weight(k)
+		}
+		hist = make(NpArray, len(edges)-1)
+		for i := range hist {
+			lo := searchSide(sorted, edges[i], false)
+			hi := searchSide(sorted, edges[i+1], i == len(hist)-1)
+			hist[i] = cum[hi] - cum[lo]
+		}
+	}
+	if density {
+		total := pairwiseSum(hist)
+		for i := range hist {
+			hist[i] = hist[i] / (edges[i+1] - edges[i]) / total
+		}
+	}
+	return hist, edges, nil
+}
+
+// Digitize returns the index of the bin each value of x falls in, like
+// numpy.digitize. bins must be monotonic; with right the bins include their
+// right edge rather than their left.
+func Digitize(x, bins NpArray, right bool) ([]int, error) {
+	increasing, decreasing := true, true
+	for i := 1; i < len(bins); i++ {
+		if bins[i] < bins[i-1] {
+			increasing = false
+		}
+		if bins[i] > bins[i-1] {
+			decreasing = false
+		}
+	}
+	if !increasing && !decreasing {
+		return nil, fmt.Errorf("bins must be monotonically increasing or decreasing")
+	}
+	ret := make([]int, len(x))
+	if increasing {
+		for i, v := range x {
+			ret[i] = searchSide(bins, v, !right)
+		}
+		return ret, nil
+	}
+	reversed := bins.Copy()
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	for i, v := range x {
+		ret[i] = len(bins) - searchSide(reversed, v, !right)
+	}
+	return ret, nil
+}
+
+// Bincount counts the occurrences of each non-negative value of x, or sums
+// their weights, like numpy.bincount. The result has at least minlength
+// elements.
+func Bincount(x []int, weights NpArray, minlength int) (NpArray, error) {
+	if minlength < 0 {
+		return nil, fmt.Errorf("minlength must be non-negative")
+	}
+	if weights != nil && len(weights) != len(x) {
+		return nil, fmt.Errorf("the weights and list don't have the same length")
+	}
+	n := minlength
+	for _, v := range x {
+		if v < 0 {
+			return nil, fmt.Errorf("list argument must have no negative elements")
+		}
+		if v+1 > n {
+			n = v + 1
+		}
+	}
+	ret := make(NpArray, n)
+	for i, v := range x {
+		if weights == nil {
+			ret[v]++
+		} else {
+			ret[v] += weights[i]
+		}
+	}
+	return ret, nil
+}
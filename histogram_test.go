@@ -0,0 +1,125 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	// the numpy.histogram examples
+	hist, edges, err := Histogram(NpArray{1, 2, 1}, &HistogramOptions{Edges: NpArray{0, 1, 2, 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 2, 1}, hist)
+	assert.Equal(t, NpArray{0, 1, 2, 3}, edges)
+	hist, _, err = Histogram(NpArray{0, 1, 2, 3}, &HistogramOptions{Edges: NpArray{0, 1, 2, 3, 4}, Density: true})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0.25, 0.25, 0.25, 0.25}, hist)
+	hist, _, err = Histogram(NpArray{1, 2, 1, 1, 0, 1}, &HistogramOptions{Edges: NpArray{0, 1, 2, 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 4, 1}, hist)
+
+	// ten equal bins by default, the last one closed
+	hist, edges, err = Histogram(NpArray{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 1, 1, 1, 1, 1, 1, 1, 1, 2}, hist)
+	assert.Equal(t, 11, len(edges))
+	assert.Equal(t, 10.0, edges[10])
+
+	// values outside the range are dropped and weights summed
+	hist, edges, err = Histogram(NpArray{-1, 0.5, 1.5, 1.5, 3}, &HistogramOptions{
+		Bins: 2, Range: &[2]float64{0, 2}, Weights: NpArray{9, 1, 0.5, 0.25, 9}})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 0.75}, hist)
+	assert.Equal(t, NpArray{0, 1, 2}, edges)
+
+	// a single value widens to a unit range
+	_, edges, err = Histogram(NpArray{5}, &HistogramOptions{Bins: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{4.5, 5, 5.5}, edges)
+
+	_, _, err = Histogram(NpArray{1, math.NaN()}, nil)
+	assert.Error(t, err)
+	_, _, err = Histogram(NpArray{1, 2}, &HistogramOptions{Edges: NpArray{2, 1}})
+	assert.Error(t, err)
+	_, _, err = Histogram(NpArray{1, 2}, &HistogramOptions{Range: &[2]float64{2, 1}})
+	assert.Error(t, err)
+	_, _, err = Histogram(NpArray{1, 2}, &HistogramOptions{Estimator: BinsAuto, Weights: NpArray{1, 1}})
+	assert.Error(t, err)
+}
+
+func TestHistogramBinEdges(t *testing.T) {
+	// the numpy.histogram_bin_edges examples
+	arr := NpArray{0, 0, 0, 1, 2, 3, 3, 4, 5}
+	edges, err := HistogramBinEdges(arr, &HistogramOptions{Estimator: BinsAuto, Range: &[2]float64{0, 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 0.25, 0.5, 0.75, 1}, edges)
+	edges, err = HistogramBinEdges(arr, &HistogramOptions{Bins: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 2.5, 5}, edges)
+	edges, err = HistogramBinEdges(arr, &HistogramOptions{Estimator: BinsAuto})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 1, 2, 3, 4, 5}, edges)
+	edges, err = HistogramBinEdges(arr, &HistogramOptions{Estimator: BinsFD})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 2.5, 5}, edges)
+	edges, err = HistogramBinEdges(arr, &HistogramOptions{Estimator: BinsSturges})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 1, 2, 3, 4, 5}, edges)
+
+	// a zero IQR gives a single fd bin
+	edges, err = HistogramBinEdges(NpArray{1, 1, 1, 1, 2}, &HistogramOptions{Estimator: BinsFD})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 2}, edges)
+
+	_, err = HistogramBinEdges(arr, &HistogramOptions{Estimator: "stone"})
+	assert.Error(t, err)
+}
+
+func TestDigitize(t *testing.T) {
+	// the numpy.digitize examples
+	x := NpArray{0.2, 6.4, 3.0, 1.6}
+	idx, err := Digitize(x, NpArray{0, 1, 2.5, 4, 10}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 4, 3, 2}, idx)
+	idx, err = Digitize(x, NpArray{10, 4, 2.5, 1, 0}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 1, 2, 3}, idx)
+
+	x = NpArray{1.2, 10, 12.4, 15.5, 20}
+	bins := NpArray{0, 5, 10, 15, 20}
+	idx, err = Digitize(x, bins, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 4}, idx)
+	idx, err = Digitize(x, bins, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 3, 4, 5}, idx)
+
+	_, err = Digitize(x, NpArray{0, 2, 1}, false)
+	assert.Error(t, err)
+}
+
+func TestBincount(t *testing.T) {
+	// the numpy.bincount examples
+	ret, err := Bincount([]int{0, 1, 2, 3, 4}, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 1, 1, 1, 1}, ret)
+	ret, err = Bincount([]int{0, 1, 1, 3, 2, 1, 7}, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 3, 1, 1, 0, 0, 0, 1}, ret)
+	ret, err = Bincount([]int{0, 1, 1, 2, 2, 2}, NpArray{0.3, 0.5, 0.2, 0.7, 1, -0.6}, 0)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.3, 0.7, 1.1}, ret, 1e-15)
+
+	ret, err = Bincount([]int{1}, nil, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{0, 1, 0, 0}, ret)
+	ret, err = Bincount(nil, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{}, ret)
+
+	_, err = Bincount([]int{1, -1}, nil, 0)
+	assert.Error(t, err)
+	_, err = Bincount([]int{1}, NpArray{1, 2}, 0)
+	assert.Error(t, err)
+}
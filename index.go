@@ -0,0 +1,439 @@
+package np
+
+import (
+	"fmt"
+)
+
+// BoolArray is an n-dimensional mask, the result of comparisons and the
+// condition of Where, Extract and PutMask.
+type BoolArray = Array[bool]
+
+// Equal returns the mask a == b with numpy broadcasting.
+func Equal(a, b interface{}) (BoolArray, error) {
+	return compareOp(a, b, func(x, y float64) bool { return x == y })
+}
+
+// NotEqual returns the mask a != b with numpy broadcasting.
+func NotEqual(a, b interface{}) (BoolArray, error) {
+	return compareOp(a, b, func(x, y float64) bool { return x != y })
+}
+
+// Less returns the mask a < b with numpy broadcasting.
+func Less(a, b interface{}) (BoolArray, error) {
+	return compareOp(a, b, func(x, y float64) bool { return x < y })
+}
+
+// LessEqual returns the mask a <= b with numpy broadcasting.
+func LessEqual(a, b interface{}) (BoolArray, error) {
+	return compareOp(a, b, func(x, y float64) bool { return x <= y })
+}
+
+// Greater returns the mask a > b with numpy broadcasting.
+func Greater(a, b interface{}) (BoolArray, error) {
+	return compareOp(a, b, func(x, y float64) bool { return x > y })
+}
+
+// GreaterEqual returns the mask a >= b with numpy broadcasting.
+func GreaterEqual(a, b interface{}) (BoolArray, error) {
+	return compareOp(a, b, func(x, y float64) bool { return x >= y })
+}
+
+func compareOp(a, b interface{}, op func(x, y float64) bool) (BoolArray, error) {
+	x, err := AsNDArray(a)
+	if err != nil {
+		return BoolArray{}, err
+	}
+	y, err := AsNDArray(b)
+	if err != nil {
+		return BoolArray{}, err
+	}
+	return combine(x, y, op)
+}
+
+// combine applies op to the elements of a and b broadcast together.
+func combine[A, B Element](a Array[A], b Array[B], op func(x A, y B) bool) (BoolArray, error) {
+	shape, err := BroadcastShapes(a.shape, b.shape)
+	if err != nil {
+		return BoolArray{}, err
+	}
+	a, _ = a.BroadcastTo(shape...)
+	b, _ = b.BroadcastTo(shape...)
+	ret := ZerosOf[bool](shape...)
+	i := 0
+	eachPair(a, b, func(pa, pb int) {
+		ret.data[i] = op(a.data[pa], b.data[pb])
+		i++
+	})
+	return ret, nil
+}
+
+// LogicalAnd returns the mask a && b with numpy broadcasting.
+func LogicalAnd(a, b BoolArray) (BoolArray, error) {
+	return combine(a, b, func(x, y bool) bool { return x && y })
+}
+
+// LogicalOr returns the mask a || b with numpy broadcasting.
+func LogicalOr(a, b BoolArray) (BoolArray, error) {
+	return combine(a, b, func(x, y bool) bool { return x || y })
+}
+
+// LogicalNot returns the negated mask.
+func LogicalNot(a BoolArray) BoolArray {
+	data := a.Flatten()
+	for i, v := range data {
+		data[i] = !v
+	}
+	return BoolArray{data: data, shape: copyInts(a.shape), strides: cStrides(a.shape)}
+}
+
+// Where picks from x where cond is true and from y elsewhere, with all
+// three broadcast together, like numpy.where.
+func Where(cond BoolArray, x, y interface{}) (NDArray, error) {
+	xa, err := AsNDArray(x)
+	if err != nil {
+		return NDArray{}, err
+	}
+	ya, err := AsNDArray(y)
+	if err != nil {
+		return NDArray{}, err
+	}
+	shape, err := BroadcastShapes(cond.shape, xa.shape, ya.shape)
+	if err != nil {
+		return NDArray{}, err
+	}
+	cond, _ = cond.BroadcastTo(shape...)
+	xa, _ = xa.BroadcastTo(shape...)
+	ya, _ = ya.BroadcastTo(shape...)
+	data := xa.Flatten()
+	ys := ya.Flatten()
+	for i, c := range cond.Flatten() {
+		if !c {
+			data[i] = ys[i]
+		}
+	}
+	return NewNDArray(data, shape...)
+}
+
+// Nonzero returns, for each axis, the indices of the elements of a that
+// are not zero, like numpy.nonzero. NaN counts as non-zero.
+func Nonzero[T Element](a Array[T]) [][]int {
+	ret := make([][]int, len(a.shape))
+	for i := range ret {
+		ret[i] = []int{}
+	}
+	var zero T
+	data := a.Flatten()
+	pos := 0
+	eachIndex(a.shape, func(idx []int) {
+		if data[pos] != zero {
+			for i, v := range idx {
+				ret[i] = append(ret[i], v)
+			}
+		}
+		pos++
+	})
+	return ret
+}
+
+// flatAxis resolves axis for a, flattening a for AxisNone.
+func flatAxis(a interface{}, axis int) (NDArray, int, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, 0, err
+	}
+	if axis == AxisNone {
+		nd, _ = NewNDArray(nd.Flatten(), nd.Size())
+		axis = 0
+	}
+	if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+		return NDArray{}, 0, err
+	}
+	return nd, axis, nil
+}
+
+// Take gathers the entries at indices along axis, or from the flattened
+// array for AxisNone, like numpy.take. Negative indices count from the end.
+func Take(a interface{}, indices []int, axis int) (NDArray, error) {
+	nd, axis, err := flatAxis(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	n := nd.shape[axis]
+	resolved := make([]int, len(indices))
+	for i, k := range indices {
+		if k < 0 {
+			k += n
+		}
+		if k < 0 || k >= n {
+			return NDArray{}, &IndexError{Index: indices[i], Axis: axis, Size: n}
+		}
+		resolved[i] = k
+	}
+	shape := copyInts(nd.shape)
+	shape[axis] = len(indices)
+	ret := NDZeros(shape...)
+	pos := 0
+	src := make([]int, len(shape))
+	eachIndex(shape, func(idx []int) {
+		copy(src, idx)
+		src[axis] = resolved[idx[axis]]
+		ret.data[pos] = nd.At(src...)
+		pos++
+	})
+	return ret, nil
+}
+
+// Compress keeps the entries along axis where cond is true, or of the
+// flattened array for AxisNone, like numpy.compress. A cond shorter than
+// the axis drops the remaining entries.
+func Compress(cond []bool, a interface{}, axis int) (NDArray, error) {
+	nd, axis, err := flatAxis(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	var indices []int
+	for i, c := range cond {
+		if !c {
+			continue
+		}
+		if i >= nd.shape[axis] {
+			return NDArray{}, &IndexError{Index: i, Axis: axis, Size: nd.shape[axis]}
+		}
+		indices = append(indices, i)
+	}
+	return Take(nd, indices, axis)
+}
+
+// Extract returns the elements of a where cond is true in C order, like
+// numpy.extract or the boolean index a[cond].
+func Extract(cond BoolArray, a interface{}) (NpArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return nil, err
+	}
+	if cond.Size() != nd.Size() {
+		return nil, fmt.Errorf("boolean index of size %d does not match array of size %d", cond.Size(), nd.Size())
+	}
+	ret := NpArray{}
+	data := nd.Flatten()
+	for i, c := range cond.Flatten() {
+		if c {
+			ret = append(ret, data[i])
+		}
+	}
+	return ret, nil
+}
+
+// unravel converts a C order position into an index of shape.
+func unravel(pos int, shape []int) []int {
+	idx := make([]int, len(shape))
+	for i := len(shape) - 1; i >= 0; i-- {
+		idx[i] = pos % shape[i]
+		pos /= shape[i]
+	}
+	return idx
+}
+
+// Put writes values into a at the given positions of its flattened form,
+// repeating values as needed, like numpy.put. a is modified in place.
+func Put(a NDArray, indices []int, values NpArray) error {
+	if len(values) == 0 {
+		return nil
+	}
+	n := a.Size()
+	for i, k := range indices {
+		if k < 0 {
+			k += n
+		}
+		if k < 0 || k >= n {
+			return &IndexError{Index: indices[i], Size: n}
+		}
+		a.Set(values[i%len(values)], unravel(k, a.shape)...)
+	}
+	return nil
+}
+
+// PutMask writes values into a where mask is true, taking values[i] for
+// the i-th element in C order and repeating values as needed, like
+// numpy.putmask. a is modified in place; for a[mask] = v pass NpArray{v}.
+func PutMask(a NDArray, mask BoolArray, values NpArray) error {
+	if mask.Size() != a.Size() {
+		return fmt.Errorf("putmask: mask and data must be the same size")
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	pos := 0
+	eachPair(a, mask, func(pa, pm int) {
+		if mask.data[pm] {
+			a.data[pa] = values[pos%len(values)]
+		}
+		pos++
+	})
+	return nil
+}
+
+// alongAxisShape checks that indices has the dimensions of a and returns
+// the shape of the selection, that of a with the length of indices along
+// axis.
+func alongAxisShape(a NDArray, indices Array[int64], axis int) ([]int, int, error) {
+	axis, err := normalizeAxis(axis, a.Ndim())
+	if err != nil {
+		return nil, 0, err
+	}
+	if indices.Ndim() != a.Ndim() {
+		return nil, 0, fmt.Errorf("indices and arr must have the same number of dimensions")
+	}
+	shape := copyInts(a.shape)
+	shape[axis] = indices.shape[axis]
+	for i := range shape {
+		if i != axis && indices.shape[i] != shape[i] && indices.shape[i] != 1 {
+			return nil, 0, fmt.Errorf("shape mismatch: indices %s do not match array %s", formatShape(indices.shape), formatShape(a.shape))
+		}
+	}
+	return shape, axis, nil
+}
+
+// TakeAlongAxis gathers a at the indices along axis, matching the other
+// axes, like numpy.take_along_axis. It pairs with ArgSort and
+// ArgPartition.
+func TakeAlongAxis(a interface{}, indices Array[int64], axis int) (NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	shape, axis, err := alongAxisShape(nd, indices, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	indices, _ = indices.BroadcastTo(shape...)
+	ret := NDZeros(shape...)
+	pos := 0
+	src := make([]int, len(shape))
+	n := nd.shape[axis]
+	eachIndex(shape, func(idx []int) {
+		if err != nil {
+			return
+		}
+		k := int(indices.At(idx...))
+		if k < -n || k >= n {
+			err = &IndexError{Index: k, Axis: axis, Size: n}
+			return
+		}
+		copy(src, idx)
+		src[axis] = k
+		ret.data[pos] = nd.At(src...)
+		pos++
+	})
+	if err != nil {
+		return NDArray{}, err
+	}
+	return ret, nil
+}
+
+// PutAlongAxis writes values into a at the indices along axis, matching
+// the other axes, like numpy.put_along_axis. values broadcasts to the
+// shape of the selection and a is modified in place.
+func PutAlongAxis(a NDArray, indices Array[int64], values interface{}, axis int) error {
+	shape, axis, err := alongAxisShape(a, indices, axis)
+	if err != nil {
+		return err
+	}
+	v, err := AsNDArray(values)
+	if err != nil {
+		return err
+	}
+	if v, err = v.BroadcastTo(shape...); err != nil {
+		return err
+	}
+	indices, _ = indices.BroadcastTo(shape...)
+	dst := make([]int, len(shape))
+	n := a.shape[axis]
+	eachIndex(shape, func(idx []int) {
+		if err != nil {
+			return
+		}
+		k := int(indices.At(idx...))
+		if k < -n || k >= n {
+			err = &IndexError{Index: k, Axis: axis, Size: n}
+			return
+		}
+		copy(dst, idx)
+		dst[axis] = k
+		a.Set(v.At(idx...), dst...)
+	})
+	return err
+}
+
+// Clip limits the values of a to [lo, hi], like numpy.clip. NaN is kept
+// and when lo > hi every value becomes hi.
+func Clip(a interface{}, lo, hi float64) (NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	data := nd.Flatten()
+	for i, v := range data {
+		data[i] = clip(v, lo, hi)
+	}
+	return NewNDArray(data, nd.shape...)
+}
+
+func clip(v, lo, hi float64) float64 {
+	if v < lo {
+		v = lo
+	}
+	if v > hi {
+		v = hi
+	}
+	return v
+}
+
+// Clip returns a copy of the array limited to [lo, hi].
+func (a NpArray) Clip(lo, hi float64) NpArray {
+	return ClipInto(make(NpArray, len(a)), a, lo, hi)
+}
+
+// Compress keeps the values where cond is true.
+func (a NpArray) Compress(cond []bool) (NpArray, error) {
+	ret, err := Compress(cond, a, 0)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Flatten(), nil
+}
+
+// Compress keeps the rows where cond is true, for example the rows of x
+// whose label matches:
+//
+//	mask, _ := np.Equal(y, label)
+//	rows, _ := x.Compress(mask.Flatten())
+func (m NpStack) Compress(cond []bool) (NpStack, error) {
+	var rows []int
+	for i, c := range cond {
+		if !c {
+			continue
+		}
+		if i >= len(m) {
+			return nil, &IndexError{Index: i, Size: len(m)}
+		}
+		rows = append(rows, i)
+	}
+	return m.Take(rows)
+}
+
+// Take returns the rows at indices, negative indices counting from the
+// end. The rows are shared with m.
+func (m NpStack) Take(indices []int) (NpStack, error) {
+	ret := make(NpStack, len(indices))
+	for i, k := range indices {
+		if k < 0 {
+			k += len(m)
+		}
+		if k < 0 || k >= len(m) {
+			return nil, &IndexError{Index: indices[i], Size: len(m)}
+		}
+		ret[i] = m[k]
+	}
+	return ret, nil
+}
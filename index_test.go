@@ -0,0 +1,181 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestCompareAndLogical(t *testing.T) {
+	m := NpStack{{1, 2, 3}, {4, 5, 6}}
+	mask, err := Greater(m, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, mask.Shape())
+	assert.Equal(t, []bool{false, false, true, true, true, true}, mask.Flatten())
+	mask, err = Equal(m, NpArray{1, 5, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false, false, true, false}, mask.Flatten())
+
+	lo, _ := GreaterEqual(m, 2)
+	hi, _ := LessEqual(m, 4)
+	both, err := LogicalAnd(lo, hi)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true, true, true, false, false}, both.Flatten())
+	either, err := LogicalOr(LogicalNot(lo), LogicalNot(hi))
+	assert.NoError(t, err)
+	assert.Equal(t, LogicalNot(both).Flatten(), either.Flatten())
+
+	// NaN compares unequal to everything
+	mask, err = NotEqual(NpArray{math.NaN(), 1}, NpArray{math.NaN(), 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, mask.Flatten())
+	mask, err = Less(NpArray{math.NaN()}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false}, mask.Flatten())
+
+	_, err = Less(m, NpArray{1, 2})
+	assert.Error(t, err)
+}
+
+func TestWhereNonzero(t *testing.T) {
+	// the numpy.where examples
+	a := NpArray{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	cond, _ := Less(a, 5)
+	scaled, _ := Mul(10.0, a)
+	ret, err := Where(cond, a, scaled)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2, 3, 4, 50, 60, 70, 80, 90}, ret.Flatten())
+
+	m := NpStack{{0, 1, 2}, {0, 2, 4}, {0, 3, 6}}
+	cond, _ = Less(m, 4)
+	ret, err = Where(cond, m, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2, 0, 2, -1, 0, 3, -1}, ret.Flatten())
+
+	// the condition broadcasts against x and y
+	col, _ := NewArray([]bool{true, false}, 2, 1)
+	ret, err = Where(col, NpArray{1, 2, 3}, NpArray{4, 5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, ret.Flatten())
+
+	nd, _ := NewNDArray([]float64{3, 0, 0, 0, 4, 0, 5, 6, 0}, 3, 3)
+	assert.Equal(t, [][]int{{0, 1, 2, 2}, {0, 1, 0, 1}}, Nonzero(nd))
+	mask, _ := Greater(nd, 3)
+	assert.Equal(t, [][]int{{1, 2, 2}, {1, 0, 1}}, Nonzero(mask))
+	assert.Equal(t, [][]int{{0, 2}}, Nonzero(NpArray{math.NaN(), 0, -1}.ToNDArray()))
+}
+
+func TestTakeCompressExtract(t *testing.T) {
+	// the numpy.take and numpy.compress examples
+	ret, err := Take(NpArray{4, 3, 5, 7, 6, 8}, []int{0, 1, 4}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4, 3, 6}, ret.Flatten())
+	m := NpStack{{1, 2}, {3, 4}, {5, 6}}
+	ret, err = Take(m, []int{-1, 0}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{5, 6, 1, 2}, ret.Flatten())
+	ret, err = Take(m, []int{1, 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2}, ret.Shape())
+	assert.Equal(t, []float64{2, 2, 4, 4, 6, 6}, ret.Flatten())
+	ret, err = Take(m, []int{5}, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6}, ret.Flatten())
+	_, err = Take(m, []int{3}, 0)
+	assert.Error(t, err)
+
+	ret, err = Compress([]bool{false, true}, m, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 4}, ret.Flatten())
+	ret, err = Compress([]bool{false, true, true}, m, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 4, 5, 6}, ret.Flatten())
+	ret, err = Compress([]bool{false, true}, m, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 4, 6}, ret.Flatten())
+	ret, err = Compress([]bool{false, true}, m, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2}, ret.Flatten())
+	_, err = Compress([]bool{false, false, false, true}, m, 0)
+	assert.Error(t, err)
+
+	cond, _ := Greater(m, 3)
+	values, err := Extract(cond, m)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{4, 5, 6}, values)
+
+	// x[y == label] on a stack of rows
+	y := NpArray{0, 1, 0}
+	mask, _ := Equal(y, 0)
+	rows, err := m.Compress(mask.Flatten())
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{1, 2}, {5, 6}}, rows)
+	rows, err = m.Take([]int{2, -3})
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{5, 6}, {1, 2}}, rows)
+	picked, err := NpArray{1, 2, 3}.Compress([]bool{true, false, true})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 3}, picked)
+}
+
+func TestPutAndPutMask(t *testing.T) {
+	// the numpy.put and numpy.putmask examples
+	a, _ := NewNDArray([]float64{0, 1, 2, 3, 4}, 5)
+	assert.NoError(t, Put(a, []int{0, 2}, NpArray{-44, -55}))
+	assert.Equal(t, []float64{-44, 1, -55, 3, 4}, a.Flatten())
+	assert.Error(t, Put(a, []int{22}, NpArray{-5}))
+
+	x, _ := NewNDArray([]float64{0, 1, 2, 3, 4, 5}, 2, 3)
+	mask, _ := Greater(x, 2)
+	assert.NoError(t, PutMask(x, mask, NpArray{-33, -44}))
+	assert.Equal(t, []float64{0, 1, 2, -44, -33, -44}, x.Flatten())
+
+	// masked assignment through a view writes to the parent
+	y, _ := NewNDArray([]float64{1, 2, 3, 4}, 2, 2)
+	col, _ := y.Slice(All(), Index(1))
+	mask, _ = Greater(col, 2)
+	assert.NoError(t, PutMask(col, mask, NpArray{0}))
+	assert.Equal(t, []float64{1, 2, 3, 0}, y.Flatten())
+	assert.Error(t, PutMask(y, mask, NpArray{0}))
+}
+
+func TestTakePutAlongAxis(t *testing.T) {
+	// the numpy.take_along_axis examples
+	a := NpStack{{10, 30, 20}, {60, 40, 50}}
+	idx, _ := ArgSort(a, 1)
+	ret, err := TakeAlongAxis(a, idx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{10, 20, 30, 40, 50, 60}, ret.Flatten())
+
+	maxIdx, _ := ArgMaxAxis(a, 1, true)
+	ret, err = TakeAlongAxis(a, maxIdx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, ret.Shape())
+	assert.Equal(t, []float64{30, 60}, ret.Flatten())
+
+	nd, _ := a.ToNDArray()
+	assert.NoError(t, PutAlongAxis(nd, maxIdx, 99, 1))
+	assert.Equal(t, []float64{10, 99, 20, 99, 40, 50}, nd.Flatten())
+
+	bad, _ := NewArray([]int64{3}, 1, 1)
+	_, err = TakeAlongAxis(a, bad, 1)
+	assert.Error(t, err)
+	flat, _ := NewArray([]int64{0}, 1)
+	_, err = TakeAlongAxis(a, flat, 1)
+	assert.Error(t, err)
+}
+
+func TestClip(t *testing.T) {
+	// the numpy.clip examples
+	a := NpArray{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	ret, err := Clip(a, 1, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 2, 3, 4, 5, 6, 7, 8, 8}, ret.Flatten())
+	ret, err = Clip(a, 8, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, ret.Flatten())
+	clipped := NpArray{math.NaN(), -2, 2}.Clip(-1, math.Inf(1))
+	assert.True(t, math.IsNaN(clipped[0]))
+	assert.Equal(t, NpArray{-1, 2}, clipped[1:])
+}
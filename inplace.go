@@ -0,0 +1,356 @@
+package np
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mdcfrancis/gonp/internal/vec"
+)
+
+// The Into functions write their result into dst and return it, like the
+// out argument of numpy's ufuncs, and the InPlace methods overwrite their
+// receiver and return it for chaining. dst must have the length of the
+// result and may be one of the inputs, except for ShuffleInto. A chain like
+// m.SubFloat64InPlace(mu).DivFloat64InPlace(sd) then allocates no arrays,
+// and with a Pool for the temporaries a pipeline runs in flat memory.
+
+func checkOut(dst NpArray, n int) {
+	if len(dst) != n {
+		panic(fmt.Errorf("output has length %d, expected %d", len(dst), n))
+	}
+}
+
+func checkRows(dst NpStack, n int) {
+	if len(dst) != n {
+		panic(fmt.Errorf("output has %d rows, expected %d", len(dst), n))
+	}
+}
+
+// AddInto sets dst[i] = a[i] + b[i].
+func AddInto(dst, a, b NpArray) NpArray {
+	vec.Add(dst, a, b)
+	return dst
+}
+
+// SubInto sets dst[i] = a[i] - b[i].
+func SubInto(dst, a, b NpArray) NpArray {
+	vec.Sub(dst, a, b)
+	return dst
+}
+
+// MulInto sets dst[i] = a[i] * b[i].
+func MulInto(dst, a, b NpArray) NpArray {
+	vec.Mul(dst, a, b)
+	return dst
+}
+
+// DivInto sets dst[i] = a[i] / b[i].
+func DivInto(dst, a, b NpArray) NpArray {
+	vec.Div(dst, a, b)
+	return dst
+}
+
+// AddFloat64Into sets dst[i] = a[i] + b.
+func AddFloat64Into(dst, a NpArray, b float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = v + b
+	}
+	return dst
+}
+
+// SubFloat64Into sets dst[i] = a[i] - b.
+func SubFloat64Into(dst, a NpArray, b float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = v - b
+	}
+	return dst
+}
+
+// MulFloat64Into sets dst[i] = a[i] * b.
+func MulFloat64Into(dst, a NpArray, b float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = v * b
+	}
+	return dst
+}
+
+// DivFloat64Into sets dst[i] = a[i] / b.
+func DivFloat64Into(dst, a NpArray, b float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = v / b
+	}
+	return dst
+}
+
+// PowFloat64Into sets dst[i] = a[i] ** power.
+func PowFloat64Into(dst, a NpArray, power float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = math.Pow(v, power)
+	}
+	return dst
+}
+
+// CondInto sets dst[i] = f(a[i]).
+func CondInto(dst, a NpArray, f func(float64) float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = f(v)
+	}
+	return dst
+}
+
+// ClipInto sets dst[i] to a[i] limited to [lo, hi].
+func ClipInto(dst, a NpArray, lo, hi float64) NpArray {
+	checkOut(dst, len(a))
+	for i, v := range a {
+		dst[i] = clip(v, lo, hi)
+	}
+	return dst
+}
+
+// ShuffleInto sets dst[i] = a[idx[i]] and zeroes the rest of dst, as
+// Shuffle does. dst must not share memory with a; see ShuffleInPlace.
+func ShuffleInto(dst, a NpArray, idx []int) NpArray {
+	checkOut(dst, len(a))
+	for i, k := range idx {
+		dst[i] = a[k]
+	}
+	clear(dst[len(idx):])
+	return dst
+}
+
+// SubSliceInto sets dst[i-start] = a[i] - b[i] for i in [start, end).
+func SubSliceInto(dst, a, b NpArray, start, end int) NpArray {
+	checkOut(dst, end-start)
+	for i := start; i < end; i++ {
+		dst[i-start] = a[i] - b[i]
+	}
+	return dst
+}
+
+// DivSliceInto sets dst[i-start] = a[i] / b for i in [start, end).
+func DivSliceInto(dst, a NpArray, b float64, start, end int) NpArray {
+	checkOut(dst, end-start)
+	for i := start; i < end; i++ {
+		dst[i-start] = a[i] / b
+	}
+	return dst
+}
+
+// LinearInterpolateInto resamples a, given at scale, at new_scale into dst,
+// which has the length of new_scale.
+func LinearInterpolateInto(dst, a, scale, new_scale NpArray) NpArray {
+	if err := InterpInto(dst, new_scale, scale, a, nil); err != nil {
+		panic(err)
+	}
+	return dst
+}
+
+func (a NpArray) AddInPlace(b NpArray) NpArray { return AddInto(a, a, b) }
+
+func (a NpArray) SubInPlace(b NpArray) NpArray { return SubInto(a, a, b) }
+
+func (a NpArray) MulInPlace(b NpArray) NpArray { return MulInto(a, a, b) }
+
+func (a NpArray) DivInPlace(b NpArray) NpArray { return DivInto(a, a, b) }
+
+func (a NpArray) AddFloat64InPlace(b float64) NpArray { return AddFloat64Into(a, a, b) }
+
+func (a NpArray) SubFloat64InPlace(b float64) NpArray { return SubFloat64Into(a, a, b) }
+
+func (a NpArray) MulFloat64InPlace(b float64) NpArray { return MulFloat64Into(a, a, b) }
+
+func (a NpArray) DivFloat64InPlace(b float64) NpArray { return DivFloat64Into(a, a, b) }
+
+func (a NpArray) PowFloat64InPlace(power float64) NpArray { return PowFloat64Into(a, a, power) }
+
+func (a NpArray) CondInPlace(f func(float64) float64) NpArray { return CondInto(a, a, f) }
+
+func (a NpArray) ClipInPlace(lo, hi float64) NpArray { return ClipInto(a, a, lo, hi) }
+
+// ShuffleInPlace reorders a as Shuffle does, through a pooled buffer.
+func (a NpArray) ShuffleInPlace(idx []int) NpArray {
+	tmp := scratch.Get(len(a))
+	ShuffleInto(tmp, a, idx)
+	copy(a, tmp)
+	scratch.Put(tmp)
+	return a
+}
+
+// MapRowsInto calls f with each row of m and the row of dst to write its
+// result to, returning dst. dst must have as many rows as m, each of the
+// length f writes.
+func (e *Exec) MapRowsInto(dst, m NpStack, f func(i int, dst, row NpArray)) NpStack {
+	checkRows(dst, len(m))
+	e.each(len(m), func(i int) {
+		f(i, dst[i], m[i])
+	})
+	return dst
+}
+
+// MapRowsInto calls f with each row and the row of dst to write its result
+// to, returning dst.
+func (s ExecStack) MapRowsInto(dst NpStack, f func(i int, dst, row NpArray)) NpStack {
+	return s.Exec.MapRowsInto(dst, s.Rows, f)
+}
+
+// SubInto subtracts b[i] from row i into dst.
+func (s ExecStack) SubInto(dst NpStack, b NpArray) NpStack {
+	return s.MapRowsInto(dst, func(i int, d, a NpArray) { SubFloat64Into(d, a, b[i]) })
+}
+
+// DivInto divides row i by b[i] into dst.
+func (s ExecStack) DivInto(dst NpStack, b NpArray) NpStack {
+	return s.MapRowsInto(dst, func(i int, d, a NpArray) { DivFloat64Into(d, a, b[i]) })
+}
+
+func (s ExecStack) AddInto(dst, b NpStack) NpStack {
+	return s.MapRowsInto(dst, func(i int, d, a NpArray) { AddInto(d, a, b[i]) })
+}
+
+func (s ExecStack) SubFloat64Into(dst NpStack, b float64) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { SubFloat64Into(d, a, b) })
+}
+
+func (s ExecStack) DivFloat64Into(dst NpStack, b float64) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { DivFloat64Into(d, a, b) })
+}
+
+func (s ExecStack) MulFloat64Into(dst NpStack, b float64) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { MulFloat64Into(d, a, b) })
+}
+
+func (s ExecStack) CondInto(dst NpStack, f func(float64) float64) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { CondInto(d, a, f) })
+}
+
+func (s ExecStack) SubSliceInto(dst NpStack, b NpArray, start, end int) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { SubSliceInto(d, a, b, start, end) })
+}
+
+func (s ExecStack) DivSliceInto(dst NpStack, b float64, start, end int) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { DivSliceInto(d, a, b, start, end) })
+}
+
+func (s ExecStack) LinearInterpolateInto(dst NpStack, scale, new_scale NpArray) NpStack {
+	return s.MapRowsInto(dst, func(_ int, d, a NpArray) { LinearInterpolateInto(d, a, scale, new_scale) })
+}
+
+// ShuffleInto copies row idx[i] into dst[i] and zeroes the rows of dst
+// past len(idx), the Into form of Shuffle. dst must not share memory with
+// the stack; see ShuffleInPlace.
+func (s ExecStack) ShuffleInto(dst NpStack, idx []int) NpStack {
+	if !s.Exec.serial(len(dst)) {
+		return s.MapRowsInto(dst, func(i int, d, _ NpArray) { shuffleRow(d, s.Rows, idx, i) })
+	}
+	// a plain loop, as the closure would be the only allocation
+	checkRows(dst, len(s.Rows))
+	for i, d := range dst {
+		shuffleRow(d, s.Rows, idx, i)
+	}
+	return dst
+}
+
+// shuffleRow writes row i of m.Shuffle(idx) to dst.
+func shuffleRow(dst NpArray, m NpStack, idx []int, i int) {
+	if i >= len(idx) {
+		clear(dst)
+		return
+	}
+	checkOut(dst, len(m[idx[i]]))
+	copy(dst, m[idx[i]])
+}
+
+// ShuffleInPlace reorders the rows as Shuffle does, moving their headers
+// through a pooled buffer rather than copying their values. Rows past
+// len(idx) become nil and a row picked twice is shared.
+func (s ExecStack) ShuffleInPlace(idx []int) NpStack {
+	h, ok := headerScratch.Get().(*NpStack)
+	if !ok {
+		h = new(NpStack)
+	}
+	tmp := append((*h)[:0], s.Rows...)
+	for i, k := range idx {
+		s.Rows[i] = tmp[k]
+	}
+	clear(s.Rows[len(idx):])
+	clear(tmp)
+	*h = tmp[:0]
+	headerScratch.Put(h)
+	return s.Rows
+}
+
+func (s ExecStack) SubInPlace(b NpArray) NpStack { return s.SubInto(s.Rows, b) }
+
+func (s ExecStack) DivInPlace(b NpArray) NpStack { return s.DivInto(s.Rows, b) }
+
+func (s ExecStack) AddInPlace(b NpStack) NpStack { return s.AddInto(s.Rows, b) }
+
+func (s ExecStack) SubFloat64InPlace(b float64) NpStack { return s.SubFloat64Into(s.Rows, b) }
+
+func (s ExecStack) DivFloat64InPlace(b float64) NpStack { return s.DivFloat64Into(s.Rows, b) }
+
+func (s ExecStack) MulFloat64InPlace(b float64) NpStack { return s.MulFloat64Into(s.Rows, b) }
+
+func (s ExecStack) CondInPlace(f func(float64) float64) NpStack { return s.CondInto(s.Rows, f) }
+
+// SubInto subtracts b[i] from row i into dst.
+func (m NpStack) SubInto(dst NpStack, b NpArray) NpStack { return m.With(nil).SubInto(dst, b) }
+
+// DivInto divides row i by b[i] into dst.
+func (m NpStack) DivInto(dst NpStack, b NpArray) NpStack { return m.With(nil).DivInto(dst, b) }
+
+func (m NpStack) AddInto(dst, b NpStack) NpStack { return m.With(nil).AddInto(dst, b) }
+
+func (m NpStack) SubFloat64Into(dst NpStack, b float64) NpStack {
+	return m.With(nil).SubFloat64Into(dst, b)
+}
+
+func (m NpStack) DivFloat64Into(dst NpStack, b float64) NpStack {
+	return m.With(nil).DivFloat64Into(dst, b)
+}
+
+func (m NpStack) MulFloat64Into(dst NpStack, b float64) NpStack {
+	return m.With(nil).MulFloat64Into(dst, b)
+}
+
+func (m NpStack) CondInto(dst NpStack, f func(float64) float64) NpStack {
+	return m.With(nil).CondInto(dst, f)
+}
+
+func (m NpStack) SubSliceInto(dst NpStack, b NpArray, start, end int) NpStack {
+	return m.With(nil).SubSliceInto(dst, b, start, end)
+}
+
+func (m NpStack) DivSliceInto(dst NpStack, b float64, start, end int) NpStack {
+	return m.With(nil).DivSliceInto(dst, b, start, end)
+}
+
+func (m NpStack) LinearInterpolateInto(dst NpStack, scale, new_scale NpArray) NpStack {
+	return m.With(nil).LinearInterpolateInto(dst, scale, new_scale)
+}
+
+func (m NpStack) ShuffleInto(dst NpStack, idx []int) NpStack {
+	return m.With(nil).ShuffleInto(dst, idx)
+}
+
+func (m NpStack) ShuffleInPlace(idx []int) NpStack { return m.With(nil).ShuffleInPlace(idx) }
+
+func (m NpStack) SubInPlace(b NpArray) NpStack { return m.With(nil).SubInPlace(b) }
+
+func (m NpStack) DivInPlace(b NpArray) NpStack { return m.With(nil).DivInPlace(b) }
+
+func (m NpStack) AddInPlace(b NpStack) NpStack { return m.With(nil).AddInPlace(b) }
+
+func (m NpStack) SubFloat64InPlace(b float64) NpStack { return m.With(nil).SubFloat64InPlace(b) }
+
+func (m NpStack) DivFloat64InPlace(b float64) NpStack { return m.With(nil).DivFloat64InPlace(b) }
+
+func (m NpStack) MulFloat64InPlace(b float64) NpStack { return m.With(nil).MulFloat64InPlace(b) }
+
+func (m NpStack) CondInPlace(f func(float64) float64) NpStack { return m.With(nil).CondInPlace(f) }
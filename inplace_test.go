@@ -0,0 +1,123 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestInto(t *testing.T) {
+	a := NpArray{1, 4, 9, 16}
+	b := NpArray{1, 2, 3, 4}
+	dst := make(NpArray, 4)
+	assert.Equal(t, a.Add(b), AddInto(dst, a, b))
+	assert.Equal(t, a.Add(b), dst)
+	assert.Equal(t, a.Sub(b), SubInto(dst, a, b))
+	assert.Equal(t, a.Mul(b), MulInto(dst, a, b))
+	assert.Equal(t, a.Div(b), DivInto(dst, a, b))
+	assert.Equal(t, a.AddFloat64(2), AddFloat64Into(dst, a, 2))
+	assert.Equal(t, a.SubFloat64(2), SubFloat64Into(dst, a, 2))
+	assert.Equal(t, a.MulFloat64(2), MulFloat64Into(dst, a, 2))
+	assert.Equal(t, a.DivFloat64(2), DivFloat64Into(dst, a, 2))
+	assert.Equal(t, a.PowFloat64(0.5), PowFloat64Into(dst, a, 0.5))
+	assert.Equal(t, a.Cond(math.Sqrt), CondInto(dst, a, math.Sqrt))
+	assert.Equal(t, a.Clip(2, 10), ClipInto(dst, a, 2, 10))
+	assert.Equal(t, a.Shuffle([]int{3, 0}), ShuffleInto(dst, a, []int{3, 0}))
+	assert.Equal(t, NpArray{16, 1, 0, 0}, dst)
+	assert.Equal(t, NpArray{2, 6}, SubSliceInto(make(NpArray, 2), a, b, 1, 3))
+	assert.Equal(t, NpArray{2, 4.5}, DivSliceInto(make(NpArray, 2), a, 2, 1, 3))
+	scale, newScale := LinSpace(0, 1, 4), LinSpace(0, 1, 7)
+	assert.Equal(t, a.LinearInterpolate(scale, newScale), LinearInterpolateInto(make(NpArray, 7), a, scale, newScale))
+	// the inputs are untouched
+	assert.Equal(t, NpArray{1, 4, 9, 16}, a)
+
+	assert.Panics(t, func() { AddFloat64Into(make(NpArray, 3), a, 1) })
+	assert.Panics(t, func() { AddInto(make(NpArray, 3), a, b) })
+	assert.Panics(t, func() { LinearInterpolateInto(make(NpArray, 3), a, scale, newScale) })
+}
+
+func TestInPlace(t *testing.T) {
+	a := NpArray{1, 4, 9, 16}
+	same := a.AddInPlace(NpArray{1, 1, 1, 1})
+	assert.Equal(t, NpArray{2, 5, 10, 17}, a)
+	same[0] = 3
+	assert.Equal(t, 3.0, a[0])
+	a.SubFloat64InPlace(1).MulFloat64InPlace(2).DivFloat64InPlace(4).AddFloat64InPlace(1)
+	assert.Equal(t, NpArray{2, 3, 5.5, 9}, a)
+	a.SubInPlace(NpArray{1, 1, 1, 1}).MulInPlace(NpArray{2, 2, 2, 2}).DivInPlace(NpArray{1, 2, 1, 2})
+	assert.Equal(t, NpArray{2, 2, 9, 8}, a)
+	a.PowFloat64InPlace(2).CondInPlace(math.Sqrt).ClipInPlace(3, 8)
+	assert.Equal(t, NpArray{3, 3, 8, 8}, a)
+	b := NpArray{1, 2, 3, 4}
+	assert.Equal(t, NpArray{4, 3, 2, 1}, b.ShuffleInPlace([]int{3, 2, 1, 0}))
+	assert.Equal(t, NpArray{4, 3, 2, 1}, b)
+}
+
+func TestStackInPlace(t *testing.T) {
+	m := NpStack{{1, 2, 3}, {4, 5, 6}}
+	mean := m.Mean()
+	want := m.Sub(mean).DivFloat64(2)
+	got := m.SubInPlace(mean).DivFloat64InPlace(2)
+	assert.Equal(t, want, got)
+	assert.Equal(t, want, m)
+
+	m = NpStack{{1, 2, 3}, {4, 5, 6}}
+	dst := NpStack{make(NpArray, 3), make(NpArray, 3)}
+	assert.Equal(t, m.SubFloat64(1), m.SubFloat64Into(dst, 1))
+	assert.Equal(t, m.MulFloat64(2), m.MulFloat64Into(dst, 2))
+	assert.Equal(t, m.DivFloat64(2), m.DivFloat64Into(dst, 2))
+	assert.Equal(t, m.Div(NpArray{1, 2}), m.DivInto(dst, NpArray{1, 2}))
+	assert.Equal(t, m.Sub(NpArray{1, 2}), m.SubInto(dst, NpArray{1, 2}))
+	assert.Equal(t, m.Add(m), m.AddInto(dst, m))
+	assert.Equal(t, m.Cond(math.Sqrt), m.CondInto(dst, math.Sqrt))
+	narrow := NpStack{make(NpArray, 2), make(NpArray, 2)}
+	assert.Equal(t, m.SubSlice(NpArray{1, 1, 1}, 1, 3), m.SubSliceInto(narrow, NpArray{1, 1, 1}, 1, 3))
+	assert.Equal(t, m.DivSlice(2, 0, 2), m.DivSliceInto(narrow, 2, 0, 2))
+	scale, newScale := LinSpace(0, 1, 3), LinSpace(0, 1, 5)
+	wide := NpStack{make(NpArray, 5), make(NpArray, 5)}
+	assert.Equal(t, m.LinearInterpolate(scale, newScale), m.LinearInterpolateInto(wide, scale, newScale))
+	assert.Equal(t, NpStack{{1, 2, 3}, {4, 5, 6}}, m)
+	assert.Panics(t, func() { m.SubFloat64Into(NpStack{make(NpArray, 3)}, 1) })
+	assert.Equal(t, NpStack{{4, 5, 6}, {0, 0, 0}}, m.ShuffleInto(dst, []int{1}))
+	assert.Equal(t, m.Shuffle([]int{1, 0}), m.ShuffleInto(dst, []int{1, 0}))
+	assert.Panics(t, func() { m.ShuffleInto(narrow, []int{0}) })
+	rows := NpStack{m[0], m[1]}
+	assert.Equal(t, m.Shuffle([]int{1, 0}), rows.ShuffleInPlace([]int{1, 0}))
+	assert.Equal(t, NpStack{m[1], m[0]}, rows)
+	assert.Equal(t, NpStack{m[0], nil}, rows.ShuffleInPlace([]int{1}))
+
+	// in parallel, with the same result
+	big := execStack(500, 9)
+	want = big.SubFloat64(0.5).MulFloat64(3)
+	got = big.With(NewExec(4, 16)).SubFloat64InPlace(0.5)
+	got = got.With(NewExec(4, 16)).MulFloat64InPlace(3)
+	assert.Equal(t, want, got)
+	perm := NewRandomState(1).Permutation(len(big))
+	assert.Equal(t, big.Shuffle(perm), big.With(NewExec(4, 16)).ShuffleInto(execStack(500, 9), perm))
+	m.MulFloat64InPlace(2).AddInPlace(NpStack{{1, 1, 1}, {1, 1, 1}}).CondInPlace(math.Sqrt)
+	assert.Equal(t, NpStack{{math.Sqrt(3), math.Sqrt(5), math.Sqrt(7)}, {3, math.Sqrt(11), math.Sqrt(13)}}, m)
+}
+
+func TestInPlace_Allocs(t *testing.T) {
+	a := make(NpArray, 1000)
+	b := make(NpArray, 1000)
+	allocs := testing.AllocsPerRun(20, func() {
+		a.SubFloat64InPlace(1).DivFloat64InPlace(2).AddInPlace(b)
+		MulInto(b, a, a)
+		a.ShuffleInPlace([]int{1, 0})
+	})
+	assert.Equal(t, 0.0, allocs)
+
+	m := execStack(100, 9)
+	dst := execStack(100, 9)
+	idx := make([]int, len(m))
+	for i := range idx {
+		idx[i] = len(m) - 1 - i
+	}
+	allocs = testing.AllocsPerRun(20, func() {
+		m.ShuffleInPlace(idx)
+		m.ShuffleInto(dst, idx)
+		m.With(nil).ShuffleInPlace(idx)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
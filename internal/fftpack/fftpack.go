@@ -0,0 +1,219 @@
+// Package fftpack holds the discrete Fourier transform kernels shared by the
+// public packages.
+package fftpack
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// NextPow2 returns the smallest power of two that is at least n.
+func NextPow2(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(n-1))
+}
+
+// Radix2 transforms x in place with an iterative radix-2 decimation in time
+// FFT. The forward transform uses exp(-2πi jk/n) and the inverse is not
+// scaled. len(x) must be a power of two.
+func Radix2(x []complex128, inverse bool) {
+	n := len(x)
+	if n&(n-1) != 0 {
+		panic(fmt.Errorf("radix-2 transform of length %d", n))
+	}
+	if n <= 1 {
+		return
+	}
+	shift := 64 - bits.Len(uint(n-1))
+	for i := range x {
+		if j := int(bits.Reverse64(uint64(i)) >> shift); i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	// twiddles are computed directly rather than by repeated multiplication
+	// to keep the error independent of n
+	w := twiddles(n, inverse)
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		step := n / size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				t := w[k*step] * x[start+k+half]
+				x[start+k+half] = x[start+k] - t
+				x[start+k] += t
+			}
+		}
+	}
+}
+
+// twiddles returns exp(∓2πi k/n) for k < n/2.
+func twiddles(n int, inverse bool) []complex128 {
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	w := make([]complex128, n/2)
+	for k := range w {
+		c, s := cosSin2Pi(k, n)
+		w[k] = complex(c, sign*s)
+	}
+	return w
+}
+
+// cosSin2Pi returns the cosine and sine of 2πk/n. The angle is reduced to
+// the first octant so that multiples of π/4 come out exact and the error
+// does not grow with k.
+func cosSin2Pi(k, n int) (float64, float64) {
+	k %= n
+	if k < 0 {
+		k += n
+	}
+	// 2πk/n = π/2 (quadrant + r/n)
+	quadrant, r := 4*k/n, 4*k%n
+	var c, s float64
+	if 2*r <= n {
+		s, c = math.Sincos(math.Pi / 2 * float64(r) / float64(n))
+	} else {
+		c, s = math.Sincos(math.Pi / 2 * float64(n-r) / float64(n))
+	}
+	switch quadrant {
+	case 1:
+		c, s = -s, c
+	case 2:
+		c, s = -c, -s
+	case 3:
+		c, s = s, -c
+	}
+	return c, s
+}
+
+// maxRadix is the largest prime factor handled by the mixed-radix transform,
+// lengths with larger factors use Bluestein's algorithm.
+const maxRadix = 13
+
+// Transform transforms x in place for any length, picking radix-2, mixed
+// radix or Bluestein's chirp-z algorithm. Like Radix2 the inverse is not
+// scaled.
+func Transform(x []complex128, inverse bool) {
+	n := len(x)
+	switch {
+	case n <= 1:
+	case n&(n-1) == 0:
+		Radix2(x, inverse)
+	case largestFactor(n) <= maxRadix:
+		w := make([]complex128, n)
+		for k := range w {
+			w[k] = twiddle(k, n, inverse)
+		}
+		out := make([]complex128, n)
+		mixedRadix(out, x, 1, n, w)
+		copy(x, out)
+	default:
+		bluestein(x, inverse)
+	}
+}
+
+// twiddle returns exp(∓2πi k/n).
+func twiddle(k, n int, inverse bool) complex128 {
+	c, s := cosSin2Pi(k, n)
+	if inverse {
+		return complex(c, s)
+	}
+	return complex(c, -s)
+}
+
+// largestFactor returns the largest prime factor of n.
+func largestFactor(n int) int {
+	largest := 1
+	for p := 2; p*p <= n; p++ {
+		for n%p == 0 {
+			largest = p
+			n /= p
+		}
+	}
+	if n > 1 {
+		largest = n
+	}
+	return largest
+}
+
+// smallestFactor returns the smallest prime factor of n, preferring 4 over 2.
+func smallestFactor(n int) int {
+	if n%4 == 0 {
+		return 4
+	}
+	for p := 2; p*p <= n; p++ {
+		if n%p == 0 {
+			return p
+		}
+	}
+	return n
+}
+
+// mixedRadix writes the transform of the n values in[0], in[stride], ... to
+// out by recursive decimation in time. w holds the twiddles of the top level
+// length, so the twiddles of this level are every stride-th one.
+func mixedRadix(out, in []complex128, stride, n int, w []complex128) {
+	if n == 1 {
+		out[0] = in[0]
+		return
+	}
+	p := smallestFactor(n)
+	m := n / p
+	// transform each of the p interleaved subsequences into a block of out
+	for r := 0; r < p; r++ {
+		mixedRadix(out[r*m:(r+1)*m], in[r*stride:], stride*p, m, w)
+	}
+	top := len(w)
+	sum := make([]complex128, p)
+	for k := 0; k < m; k++ {
+		for q := 0; q < p; q++ {
+			var acc complex128
+			for r := 0; r < p; r++ {
+				// W_n^(r(k+qm))
+				acc += out[r*m+k] * w[(r*(k+q*m)%n)*(top/n)]
+			}
+			sum[q] = acc
+		}
+		for q := 0; q < p; q++ {
+			out[q*m+k] = sum[q]
+		}
+	}
+}
+
+// bluestein transforms x as a convolution with a chirp, computed by
+// power-of-two FFTs.
+func bluestein(x []complex128, inverse bool) {
+	n := len(x)
+	size := NextPow2(2*n - 1)
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		// exp(∓πi k²/n), reducing k² mod 2n keeps the angle accurate
+		kk := (k * k) % (2 * n)
+		chirp[k] = twiddle(kk, 2*n, inverse)
+	}
+	a := make([]complex128, size)
+	b := make([]complex128, size)
+	for k, v := range x {
+		a[k] = v * chirp[k]
+	}
+	b[0] = cmplx.Conj(chirp[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(chirp[k])
+		b[size-k] = b[k]
+	}
+	Radix2(a, false)
+	Radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	Radix2(a, true)
+	scale := 1 / float64(size)
+	for k := range x {
+		x[k] = chirp[k] * a[k] * complex(scale, 0)
+	}
+}
@@ -0,0 +1,103 @@
+package fftpack
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func dft(x []complex128, sign float64) []complex128 {
+	n := len(x)
+	ret := make([]complex128, n)
+	for k := range ret {
+		for j, v := range x {
+			s, c := math.Sincos(2 * math.Pi * float64(j*k%n) / float64(n))
+			ret[k] += v * complex(c, sign*s)
+		}
+	}
+	return ret
+}
+
+func TestRadix2_MatchesDFT(t *testing.T) {
+	for _, n := range []int{1, 2, 4, 8, 64, 256} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(math.Sin(float64(i)*1.3), math.Cos(float64(i*i)))
+		}
+		for _, inverse := range []bool{false, true} {
+			sign := -1.0
+			if inverse {
+				sign = 1
+			}
+			want := dft(x, sign)
+			got := append([]complex128(nil), x...)
+			Radix2(got, inverse)
+			for i := range got {
+				assert.InDelta(t, 0, cmplx.Abs(got[i]-want[i]), 1e-12*float64(n), "n=%d i=%d", n, i)
+			}
+		}
+	}
+	assert.Panics(t, func() { Radix2(make([]complex128, 6), false) })
+}
+
+func TestNextPow2(t *testing.T) {
+	assert.Equal(t, 1, NextPow2(0))
+	assert.Equal(t, 1, NextPow2(1))
+	assert.Equal(t, 2, NextPow2(2))
+	assert.Equal(t, 8, NextPow2(5))
+	assert.Equal(t, 1024, NextPow2(1024))
+	assert.Equal(t, 2048, NextPow2(1025))
+}
+
+func TestTransform_MatchesDFT(t *testing.T) {
+	// powers of two, smooth lengths for the mixed radix and primes for
+	// Bluestein
+	for _, n := range []int{0, 1, 3, 5, 6, 12, 45, 60, 96, 100, 17, 97, 202, 1009} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(math.Sin(float64(i)*0.7)+1, math.Cos(float64(i*i)*0.1))
+		}
+		for _, inverse := range []bool{false, true} {
+			sign := -1.0
+			if inverse {
+				sign = 1
+			}
+			want := dft(x, sign)
+			got := append([]complex128(nil), x...)
+			Transform(got, inverse)
+			for i := range got {
+				assert.InDelta(t, 0, cmplx.Abs(got[i]-want[i]), 1e-11*float64(n), "n=%d i=%d", n, i)
+			}
+		}
+	}
+}
+
+func TestFactors(t *testing.T) {
+	assert.Equal(t, 5, largestFactor(100))
+	assert.Equal(t, 97, largestFactor(97))
+	assert.Equal(t, 4, smallestFactor(12))
+	assert.Equal(t, 3, smallestFactor(45))
+	assert.Equal(t, 97, smallestFactor(97))
+}
+
+func TestCosSin2Pi(t *testing.T) {
+	for _, n := range []int{1, 3, 4, 8, 12, 1000} {
+		for k := 0; k < n; k++ {
+			c, s := cosSin2Pi(k, n)
+			c1, s1 := cosSin2Pi(k-n, n)
+			assert.Equal(t, c, c1)
+			assert.Equal(t, s, s1)
+			ws, wc := math.Sincos(2 * math.Pi * float64(k) / float64(n))
+			// the direct angle is rounded more coarsely near 2π
+			assert.InDelta(t, wc, c, 2e-15)
+			assert.InDelta(t, ws, s, 2e-15)
+		}
+	}
+	c, s := cosSin2Pi(1, 4)
+	assert.Equal(t, 0.0, c)
+	assert.Equal(t, 1.0, s)
+	c, s = cosSin2Pi(6, 4)
+	assert.Equal(t, -1.0, c)
+	assert.Equal(t, 0.0, s)
+}
@@ -0,0 +1,41 @@
+package vec
+
+func goAdd(dst, a, b []float64) {
+	for i := range dst {
+		dst[i] = a[i] + b[i]
+	}
+}
+
+func goSub(dst, a, b []float64) {
+	for i := range dst {
+		dst[i] = a[i] - b[i]
+	}
+}
+
+func goMul(dst, a, b []float64) {
+	for i := range dst {
+		dst[i] = a[i] * b[i]
+	}
+}
+
+func goDiv(dst, a, b []float64) {
+	for i := range dst {
+		dst[i] = a[i] / b[i]
+	}
+}
+
+func goAcc(r *[8]float64, a []float64) {
+	for i := 0; i+8 <= len(a); i += 8 {
+		for j := range r {
+			r[j] += a[i+j]
+		}
+	}
+}
+
+func goAccDot(r *[8]float64, a, b []float64) {
+	for i := 0; i+8 <= len(a); i += 8 {
+		for j := range r {
+			r[j] += float64(a[i+j] * b[i+j]) // rounded, not fused
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package vec holds the float64 kernels behind NpArray arithmetic and the
+// pairwise sums of the reductions. On amd64 they use AVX-512 or AVX2 and on
+// arm64 NEON, chosen when the package loads; elsewhere, or when built with
+// the purego tag, they are plain Go. Every implementation gives the same
+// bits: the elementwise kernels are single IEEE operations per element and
+// the sums keep numpy's eight accumulators and order of addition.
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// kernels is one implementation of the vector kernels. add, sub, mul and
+// div only take lengths that are a multiple of block, acc and accDot
+// multiples of eight; the callers finish the rest in Go.
+type kernels struct {
+	name               string
+	block              int
+	add, sub, mul, div func(dst, a, b []float64)
+	// acc adds a[i+j] to r[j] for each block of eight values of a.
+	acc func(r *[8]float64, a []float64)
+	// accDot adds a[i+j]*b[i+j] to r[j] for each block of eight, rounding
+	// the product before the sum.
+	accDot func(r *[8]float64, a, b []float64)
+}
+
+var generic = kernels{
+	name:   "generic",
+	block:  1,
+	add:    goAdd,
+	sub:    goSub,
+	mul:    goMul,
+	div:    goDiv,
+	acc:    goAcc,
+	accDot: goAccDot,
+}
+
+// impl is the fastest implementation the CPU supports.
+var impl = generic
+
+// Impl names the implementation in use: "avx512", "avx2", "neon" or
+// "generic".
+func Impl() string {
+	return impl.name
+}
+
+func checkLen(dst, a, b []float64) {
+	if len(a) != len(dst) || len(b) != len(dst) {
+		panic(fmt.Errorf("Arrays must be same length %d %d %d", len(dst), len(a), len(b)))
+	}
+}
+
+func elementwise(k kernels, f, tail func(dst, a, b []float64), dst, a, b []float64) {
+	checkLen(dst, a, b)
+	m := len(dst) - len(dst)%k.block
+	if m > 0 {
+		f(dst[:m], a[:m], b[:m])
+	}
+	tail(dst[m:], a[m:], b[m:])
+}
+
+// Add sets dst[i] = a[i] + b[i]. dst may be a or b.
+func Add(dst, a, b []float64) { elementwise(impl, impl.add, goAdd, dst, a, b) }
+
+// Sub sets dst[i] = a[i] - b[i]. dst may be a or b.
+func Sub(dst, a, b []float64) { elementwise(impl, impl.sub, goSub, dst, a, b) }
+
+// Mul sets dst[i] = a[i] * b[i]. dst may be a or b.
+func Mul(dst, a, b []float64) { elementwise(impl, impl.mul, goMul, dst, a, b) }
+
+// Div sets dst[i] = a[i] / b[i]. dst may be a or b.
+func Div(dst, a, b []float64) { elementwise(impl, impl.div, goDiv, dst, a, b) }
+
+// Sum adds a as numpy's pairwise_sum does, with eight accumulators in blocks
+// of up to 128 elements, which keeps the rounding error to O(log n).
+func Sum(a []float64) float64 {
+	return pairwise(impl, a)
+}
+
+func pairwise(k kernels, a []float64) float64 {
+	n := len(a)
+	switch {
+	case n < 8:
+		sum := 0.0
+		for _, v := range a {
+			sum += v
+		}
+		return sum
+	case n <= 128:
+		var r [8]float64
+		copy(r[:], a[:8])
+		m := n - n%8
+		k.acc(&r, a[8:m])
+		sum := ((r[0] + r[1]) + (r[2] + r[3])) + ((r[4] + r[5]) + (r[6] + r[7]))
+		for _, v := range a[m:] {
+			sum += v
+		}
+		return sum
+	}
+	n2 := n / 2
+	n2 -= n2 % 8
+	return pairwise(k, a[:n2]) + pairwise(k, a[n2:])
+}
+
+// Dot returns the sum of a[i]*b[i], adding the products as Sum does. Each
+// product is rounded before it is added, never fused.
+func Dot(a, b []float64) float64 {
+	if len(a) != len(b) {
+		panic(fmt.Errorf("Arrays must be same length %d %d", len(a), len(b)))
+	}
+	return pairwiseDot(impl, a, b)
+}
+
+func pairwiseDot(k kernels, a, b []float64) float64 {
+	n := len(a)
+	switch {
+	case n < 8:
+		sum := 0.0
+		for i, v := range a {
+			sum += float64(v * b[i])
+		}
+		return sum
+	case n <= 128:
+		var r [8]float64
+		for j := range r {
+			r[j] = a[j] * b[j]
+		}
+		m := n - n%8
+		k.accDot(&r, a[8:m], b[8:m])
+		sum := ((r[0] + r[1]) + (r[2] + r[3])) + ((r[4] + r[5]) + (r[6] + r[7]))
+		for i := m; i < n; i++ {
+			sum += float64(a[i] * b[i])
+		}
+		return sum
+	}
+	n2 := n / 2
+	n2 -= n2 % 8
+	return pairwiseDot(k, a[:n2], b[:n2]) + pairwiseDot(k, a[n2:], b[n2:])
+}
+
+// KahanSum adds a with Kahan compensated summation, in Neumaier's form that
+// also holds when a term is larger than the running sum. The error does not
+// grow with n.
+func KahanSum(a []float64) float64 {
+	sum, c := 0.0, 0.0
+	for _, v := range a {
+		sum, c = kahanAdd(sum, c, v)
+	}
+	return sum + c
+}
+
+// KahanDot returns the sum of a[i]*b[i] added as KahanSum does.
+func KahanDot(a, b []float64) float64 {
+	if len(a) != len(b) {
+		panic(fmt.Errorf("Arrays must be same length %d %d", len(a), len(b)))
+	}
+	sum, c := 0.0, 0.0
+	for i, v := range a {
+		sum, c = kahanAdd(sum, c, float64(v*b[i]))
+	}
+	return sum + c
+}
+
+// kahanAdd adds v to sum, carrying the lost low order bits in c.
+func kahanAdd(sum, c, v float64) (float64, float64) {
+	t := sum + v
+	if math.Abs(sum) >= math.Abs(v) {
+		c += (sum - t) + v
+	} else {
+		c += (v - t) + sum
+	}
+	return t, c
+}
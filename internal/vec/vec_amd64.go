@@ -0,0 +1,74 @@
+//go:build !purego
+
+package vec
+
+// implemented in vec_amd64.s
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+func xgetbv() (eax, edx uint32)
+
+func addAVX2(dst, a, b []float64)
+func subAVX2(dst, a, b []float64)
+func mulAVX2(dst, a, b []float64)
+func divAVX2(dst, a, b []float64)
+func accAVX2(r *[8]float64, a []float64)
+func accDotAVX2(r *[8]float64, a, b []float64)
+
+func addAVX512(dst, a, b []float64)
+func subAVX512(dst, a, b []float64)
+func mulAVX512(dst, a, b []float64)
+func divAVX512(dst, a, b []float64)
+func accAVX512(r *[8]float64, a []float64)
+func accDotAVX512(r *[8]float64, a, b []float64)
+
+var avx2 = kernels{
+	name:   "avx2",
+	block:  8,
+	add:    addAVX2,
+	sub:    subAVX2,
+	mul:    mulAVX2,
+	div:    divAVX2,
+	acc:    accAVX2,
+	accDot: accDotAVX2,
+}
+
+var avx512 = kernels{
+	name:   "avx512",
+	block:  16,
+	add:    addAVX512,
+	sub:    subAVX512,
+	mul:    mulAVX512,
+	div:    divAVX512,
+	acc:    accAVX512,
+	accDot: accDotAVX512,
+}
+
+// supported lists the implementations this CPU and OS can run, slowest
+// first.
+func supported() []kernels {
+	ret := []kernels{generic}
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return ret
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return ret
+	}
+	// the OS must save the YMM state, and for AVX-512 the opmask and ZMM
+	// state too
+	xcr0, _ := xgetbv()
+	_, ebx7, _, _ := cpuid(7, 0)
+	if xcr0&0x6 == 0x6 && ebx7&(1<<5) != 0 {
+		ret = append(ret, avx2)
+	}
+	if xcr0&0xe6 == 0xe6 && ebx7&(1<<16) != 0 {
+		ret = append(ret, avx512)
+	}
+	return ret
+}
+
+func init() {
+	all := supported()
+	impl = all[len(all)-1]
+}
@@ -0,0 +1,176 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// ELEMENTWISE2 applies OP to eight values a time in two YMM registers,
+// dst = a OP b. len(dst) is a multiple of eight.
+#define ELEMENTWISE2(name, OP) \
+TEXT name(SB), NOSPLIT, $0-72 \
+	MOVQ dst_base+0(FP), DI \
+	MOVQ dst_len+8(FP), CX \
+	MOVQ a_base+24(FP), SI \
+	MOVQ b_base+48(FP), DX \
+	XORQ AX, AX \
+loop: \
+	CMPQ AX, CX \
+	JAE  done \
+	VMOVUPD (SI)(AX*8), Y0 \
+	VMOVUPD 32(SI)(AX*8), Y1 \
+	OP      (DX)(AX*8), Y0, Y0 \
+	OP      32(DX)(AX*8), Y1, Y1 \
+	VMOVUPD Y0, (DI)(AX*8) \
+	VMOVUPD Y1, 32(DI)(AX*8) \
+	ADDQ    $8, AX \
+	JMP     loop \
+done: \
+	VZEROUPPER \
+	RET
+
+// ELEMENTWISE512 does the same sixteen values at a time in two ZMM
+// registers. len(dst) is a multiple of sixteen.
+#define ELEMENTWISE512(name, OP) \
+TEXT name(SB), NOSPLIT, $0-72 \
+	MOVQ dst_base+0(FP), DI \
+	MOVQ dst_len+8(FP), CX \
+	MOVQ a_base+24(FP), SI \
+	MOVQ b_base+48(FP), DX \
+	XORQ AX, AX \
+loop: \
+	CMPQ AX, CX \
+	JAE  done \
+	VMOVUPD (SI)(AX*8), Z0 \
+	VMOVUPD 64(SI)(AX*8), Z1 \
+	OP      (DX)(AX*8), Z0, Z0 \
+	OP      64(DX)(AX*8), Z1, Z1 \
+	VMOVUPD Z0, (DI)(AX*8) \
+	VMOVUPD Z1, 64(DI)(AX*8) \
+	ADDQ    $16, AX \
+	JMP     loop \
+done: \
+	VZEROUPPER \
+	RET
+
+ELEMENTWISE2(·addAVX2, VADDPD)
+ELEMENTWISE2(·subAVX2, VSUBPD)
+ELEMENTWISE2(·mulAVX2, VMULPD)
+ELEMENTWISE2(·divAVX2, VDIVPD)
+ELEMENTWISE512(·addAVX512, VADDPD)
+ELEMENTWISE512(·subAVX512, VSUBPD)
+ELEMENTWISE512(·mulAVX512, VMULPD)
+ELEMENTWISE512(·divAVX512, VDIVPD)
+
+// func accAVX2(r *[8]float64, a []float64)
+// r[0:4] lives in Y0 and r[4:8] in Y1.
+TEXT ·accAVX2(SB), NOSPLIT, $0-32
+	MOVQ    r+0(FP), DI
+	MOVQ    a_base+8(FP), SI
+	MOVQ    a_len+16(FP), CX
+	VMOVUPD (DI), Y0
+	VMOVUPD 32(DI), Y1
+	XORQ    AX, AX
+
+accloop:
+	CMPQ   AX, CX
+	JAE    accdone
+	VADDPD (SI)(AX*8), Y0, Y0
+	VADDPD 32(SI)(AX*8), Y1, Y1
+	ADDQ   $8, AX
+	JMP    accloop
+
+accdone:
+	VMOVUPD Y0, (DI)
+	VMOVUPD Y1, 32(DI)
+	VZEROUPPER
+	RET
+
+// func accDotAVX2(r *[8]float64, a, b []float64)
+// The products are rounded by VMULPD before VADDPD adds them; no FMA.
+TEXT ·accDotAVX2(SB), NOSPLIT, $0-56
+	MOVQ    r+0(FP), DI
+	MOVQ    a_base+8(FP), SI
+	MOVQ    a_len+16(FP), CX
+	MOVQ    b_base+32(FP), DX
+	VMOVUPD (DI), Y0
+	VMOVUPD 32(DI), Y1
+	XORQ    AX, AX
+
+dotloop:
+	CMPQ    AX, CX
+	JAE     dotdone
+	VMOVUPD (SI)(AX*8), Y2
+	VMOVUPD 32(SI)(AX*8), Y3
+	VMULPD  (DX)(AX*8), Y2, Y2
+	VMULPD  32(DX)(AX*8), Y3, Y3
+	VADDPD  Y2, Y0, Y0
+	VADDPD  Y3, Y1, Y1
+	ADDQ    $8, AX
+	JMP     dotloop
+
+dotdone:
+	VMOVUPD Y0, (DI)
+	VMOVUPD Y1, 32(DI)
+	VZEROUPPER
+	RET
+
+// func accAVX512(r *[8]float64, a []float64)
+// r lives in Z0.
+TEXT ·accAVX512(SB), NOSPLIT, $0-32
+	MOVQ    r+0(FP), DI
+	MOVQ    a_base+8(FP), SI
+	MOVQ    a_len+16(FP), CX
+	VMOVUPD (DI), Z0
+	XORQ    AX, AX
+
+acc512loop:
+	CMPQ   AX, CX
+	JAE    acc512done
+	VADDPD (SI)(AX*8), Z0, Z0
+	ADDQ   $8, AX
+	JMP    acc512loop
+
+acc512done:
+	VMOVUPD Z0, (DI)
+	VZEROUPPER
+	RET
+
+// func accDotAVX512(r *[8]float64, a, b []float64)
+TEXT ·accDotAVX512(SB), NOSPLIT, $0-56
+	MOVQ    r+0(FP), DI
+	MOVQ    a_base+8(FP), SI
+	MOVQ    a_len+16(FP), CX
+	MOVQ    b_base+32(FP), DX
+	VMOVUPD (DI), Z0
+	XORQ    AX, AX
+
+dot512loop:
+	CMPQ    AX, CX
+	JAE     dot512done
+	VMOVUPD (SI)(AX*8), Z2
+	VMULPD  (DX)(AX*8), Z2, Z2
+	VADDPD  Z2, Z0, Z0
+	ADDQ    $8, AX
+	JMP     dot512loop
+
+dot512done:
+	VMOVUPD Z0, (DI)
+	VZEROUPPER
+	RET
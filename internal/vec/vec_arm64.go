@@ -0,0 +1,32 @@
+//go:build !purego
+
+package vec
+
+// implemented in vec_arm64.s
+func addNEON(dst, a, b []float64)
+func subNEON(dst, a, b []float64)
+func mulNEON(dst, a, b []float64)
+func divNEON(dst, a, b []float64)
+func accNEON(r *[8]float64, a []float64)
+func accDotNEON(r *[8]float64, a, b []float64)
+
+var neon = kernels{
+	name:   "neon",
+	block:  8,
+	add:    addNEON,
+	sub:    subNEON,
+	mul:    mulNEON,
+	div:    divNEON,
+	acc:    accNEON,
+	accDot: accDotNEON,
+}
+
+// supported lists the implementations this CPU can run, slowest first.
+// Every arm64 CPU Go runs on has NEON.
+func supported() []kernels {
+	return []kernels{generic, neon}
+}
+
+func init() {
+	impl = neon
+}
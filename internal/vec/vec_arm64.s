@@ -0,0 +1,90 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// The vector arithmetic is spelled as WORDs so that older assemblers
+// without VFADD and friends accept it. In every kernel the operands are in
+// V0-V3 and V4-V7 and the instructions are
+//
+//	VFADD V4.D2, V0.D2, V0.D2  4e64d400 (and V5/V1, V6/V2, V7/V3)
+//	VFSUB V4.D2, V0.D2, V0.D2  4ee4d400
+//	VFMUL V4.D2, V0.D2, V0.D2  6e64dc00
+//	VFDIV V4.D2, V0.D2, V0.D2  6e64fc00
+//	VFADD V0.D2, V16.D2, V16.D2  4e60d610 (and V1/V17, V2/V18, V3/V19)
+
+// ELEMENTWISE sets dst = a OP b eight values at a time, where W0-W3 encode
+// OP on the register pairs. len(dst) is a multiple of eight.
+#define ELEMENTWISE(name, W0, W1, W2, W3) \
+TEXT name(SB), NOSPLIT, $0-72 \
+	MOVD dst_base+0(FP), R0 \
+	MOVD dst_len+8(FP), R3 \
+	MOVD a_base+24(FP), R1 \
+	MOVD b_base+48(FP), R2 \
+loop: \
+	CBZ    R3, done \
+	VLD1.P 64(R1), [V0.D2, V1.D2, V2.D2, V3.D2] \
+	VLD1.P 64(R2), [V4.D2, V5.D2, V6.D2, V7.D2] \
+	WORD   W0 \
+	WORD   W1 \
+	WORD   W2 \
+	WORD   W3 \
+	VST1.P [V0.D2, V1.D2, V2.D2, V3.D2], 64(R0) \
+	SUB    $8, R3 \
+	B      loop \
+done: \
+	RET
+
+ELEMENTWISE(·addNEON, $0x4e64d400, $0x4e65d421, $0x4e66d442, $0x4e67d463)
+ELEMENTWISE(·subNEON, $0x4ee4d400, $0x4ee5d421, $0x4ee6d442, $0x4ee7d463)
+ELEMENTWISE(·mulNEON, $0x6e64dc00, $0x6e65dc21, $0x6e66dc42, $0x6e67dc63)
+ELEMENTWISE(·divNEON, $0x6e64fc00, $0x6e65fc21, $0x6e66fc42, $0x6e67fc63)
+
+// func accNEON(r *[8]float64, a []float64)
+// r lives in V16-V19.
+TEXT ·accNEON(SB), NOSPLIT, $0-32
+	MOVD r+0(FP), R0
+	MOVD a_base+8(FP), R1
+	MOVD a_len+16(FP), R3
+	VLD1 (R0), [V16.D2, V17.D2, V18.D2, V19.D2]
+
+accloop:
+	CBZ    R3, accdone
+	VLD1.P 64(R1), [V0.D2, V1.D2, V2.D2, V3.D2]
+	WORD   $0x4e60d610
+	WORD   $0x4e61d631
+	WORD   $0x4e62d652
+	WORD   $0x4e63d673
+	SUB    $8, R3
+	B      accloop
+
+accdone:
+	VST1 [V16.D2, V17.D2, V18.D2, V19.D2], (R0)
+	RET
+
+// func accDotNEON(r *[8]float64, a, b []float64)
+// The products are rounded by FMUL before FADD adds them; no FMLA.
+TEXT ·accDotNEON(SB), NOSPLIT, $0-56
+	MOVD r+0(FP), R0
+	MOVD a_base+8(FP), R1
+	MOVD a_len+16(FP), R3
+	MOVD b_base+32(FP), R2
+	VLD1 (R0), [V16.D2, V17.D2, V18.D2, V19.D2]
+
+dotloop:
+	CBZ    R3, dotdone
+	VLD1.P 64(R1), [V0.D2, V1.D2, V2.D2, V3.D2]
+	VLD1.P 64(R2), [V4.D2, V5.D2, V6.D2, V7.D2]
+	WORD   $0x6e64dc00
+	WORD   $0x6e65dc21
+	WORD   $0x6e66dc42
+	WORD   $0x6e67dc63
+	WORD   $0x4e60d610
+	WORD   $0x4e61d631
+	WORD   $0x4e62d652
+	WORD   $0x4e63d673
+	SUB    $8, R3
+	B      dotloop
+
+dotdone:
+	VST1 [V16.D2, V17.D2, V18.D2, V19.D2], (R0)
+	RET
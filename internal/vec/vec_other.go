@@ -0,0 +1,9 @@
+//go:build purego || !(amd64 || arm64)
+
+package vec
+
+// supported lists the implementations available, only the generic one
+// here.
+func supported() []kernels {
+	return []kernels{generic}
+}
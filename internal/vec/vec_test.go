@@ -0,0 +1,117 @@
+package vec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// values returns n awkward values in a fixed order, with a NaN, infinities,
+// signed zeros and subnormals mixed in when special is set.
+func values(n int, seed uint64, special bool) []float64 {
+	ret := make([]float64, n)
+	for i := range ret {
+		seed = seed*6364136223846793005 + 1442695040888963407
+		ret[i] = (float64(seed>>11)/(1<<53) - 0.5) * math.Pow(10, float64(int(seed%7))-3)
+	}
+	if special && n > 12 {
+		ret[3] = math.NaN()
+		ret[5] = math.Inf(1)
+		ret[7] = math.Copysign(0, -1)
+		ret[9] = 5e-324
+		ret[11] = math.Inf(-1)
+	}
+	return ret
+}
+
+func sameBits(t *testing.T, want, got []float64, msg string) {
+	t.Helper()
+	if !assert.Len(t, got, len(want), msg) {
+		return
+	}
+	for i := range want {
+		if math.Float64bits(want[i]) != math.Float64bits(got[i]) && !(math.IsNaN(want[i]) && math.IsNaN(got[i])) {
+			t.Errorf("%s: index %d want %v got %v", msg, i, want[i], got[i])
+			return
+		}
+	}
+}
+
+func TestElementwise(t *testing.T) {
+	for _, k := range supported() {
+		for _, n := range []int{0, 1, 7, 8, 15, 16, 17, 31, 33, 100, 1001} {
+			a, b := values(n, 1, true), values(n, 2, false)
+			for _, op := range []struct {
+				name    string
+				f, want func(dst, a, b []float64)
+			}{
+				{"add", k.add, goAdd}, {"sub", k.sub, goSub}, {"mul", k.mul, goMul}, {"div", k.div, goDiv},
+			} {
+				want := make([]float64, n)
+				op.want(want, a, b)
+				got := make([]float64, n)
+				elementwise(k, op.f, op.want, got, a, b)
+				sameBits(t, want, got, k.name+" "+op.name)
+				// in place
+				inPlace := append([]float64(nil), a...)
+				elementwise(k, op.f, op.want, inPlace, inPlace, b)
+				sameBits(t, want, inPlace, k.name+" "+op.name+" in place")
+			}
+		}
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	for _, k := range supported() {
+		for _, n := range []int{0, 1, 7, 8, 9, 16, 127, 128, 129, 1000, 4099} {
+			a, b := values(n, 3, false), values(n, 4, false)
+			want, wantDot := pairwise(generic, a), pairwiseDot(generic, a, b)
+			assert.Equal(t, math.Float64bits(want), math.Float64bits(pairwise(k, a)), "%s sum n=%d", k.name, n)
+			assert.Equal(t, math.Float64bits(wantDot), math.Float64bits(pairwiseDot(k, a, b)), "%s dot n=%d", k.name, n)
+		}
+		special := values(50, 5, true)
+		assert.True(t, math.IsNaN(pairwise(k, special)), k.name)
+	}
+}
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, 0.0, Sum(nil))
+	assert.Equal(t, 6.0, Sum([]float64{1, 2, 3}))
+	// the blocks of eight are combined pairwise as numpy does
+	a := make([]float64, 17)
+	for i := range a {
+		a[i] = 0.1 * float64(i+1)
+	}
+	r := [8]float64{}
+	copy(r[:], a[:8])
+	for j := range r {
+		r[j] += a[8+j]
+	}
+	want := ((r[0] + r[1]) + (r[2] + r[3])) + ((r[4] + r[5]) + (r[6] + r[7])) + a[16]
+	assert.Equal(t, want, Sum(a))
+	assert.Equal(t, 32.0, Dot([]float64{1, 2, 3}, []float64{4, 5, 6}))
+	assert.Panics(t, func() { Dot([]float64{1}, []float64{1, 2}) })
+	assert.Panics(t, func() { Add(make([]float64, 2), []float64{1}, []float64{1, 2}) })
+	assert.Contains(t, []string{"avx512", "avx2", "neon", "generic"}, Impl())
+}
+
+func TestKahan(t *testing.T) {
+	a := make([]float64, 10001)
+	a[0] = 1
+	for i := 1; i < len(a); i++ {
+		a[i] = 1e-16
+	}
+	// naively every tiny term is lost
+	naive := 0.0
+	for _, v := range a {
+		naive += v
+	}
+	assert.Equal(t, 1.0, naive)
+	assert.InDelta(t, 1+1e-12, KahanSum(a), 1e-16)
+	assert.Equal(t, 2.0, KahanSum([]float64{1, 1e100, 1, -1e100}))
+	ones := make([]float64, len(a))
+	for i := range ones {
+		ones[i] = 1
+	}
+	assert.InDelta(t, 1+1e-12, KahanDot(a, ones), 1e-16)
+}
@@ -0,0 +1,219 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// InterpOptions holds the optional arguments of numpy.interp. Left and Right
+// are returned for queries below xp[0] and above xp[len(xp)-1], defaulting
+// to fp[0] and fp[len(fp)-1]. A non-zero Period treats xp as periodic and
+// ignores Left and Right. With a Period, samples that wrap to the same point
+// stay in the order given; numpy sorts them with an unstable argsort, so
+// between such duplicates its result can differ.
+type InterpOptions struct {
+	Left, Right *float64
+	Period      float64
+}
+
+// Interp returns the piecewise linear interpolant of the points (xp, fp)
+// evaluated at x, matching numpy.interp bit for bit. xp must be increasing
+// unless a Period is given. Each query is found by binary search seeded with
+// the previous result, so sorted queries cost O(1) each.
+func Interp(x, xp, fp NpArray, opts *InterpOptions) (NpArray, error) {
+	ret := make(NpArray, len(x))
+	if err := InterpInto(ret, x, xp, fp, opts); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// InterpInto is Interp writing the result into dst, which must have the
+// length of x and may be x itself.
+func InterpInto(dst, x, xp, fp NpArray, opts *InterpOptions) error {
+	if len(dst) != len(x) {
+		return fmt.Errorf("output has length %d, expected %d", len(dst), len(x))
+	}
+	if opts == nil {
+		opts = &InterpOptions{}
+	}
+	if len(xp) != len(fp) {
+		return fmt.Errorf("fp and xp are not of the same length")
+	}
+	if len(xp) == 0 {
+		return fmt.Errorf("array of sample points is empty")
+	}
+	if opts.Period != 0 {
+		x, xp, fp = periodicInterp(x, xp, fp, math.Abs(opts.Period))
+	} else {
+		for i := 1; i < len(xp); i++ {
+			if !(xp[i] >= xp[i-1]) {
+				return fmt.Errorf("xp must be increasing, xp[%d] = %v follows %v", i, xp[i], xp[i-1])
+			}
+		}
+	}
+	left, right := fp[0], fp[len(fp)-1]
+	if opts.Period == 0 && opts.Left != nil {
+		left = *opts.Left
+	}
+	if opts.Period == 0 && opts.Right != nil {
+		right = *opts.Right
+	}
+
+	ret := dst
+	n := len(xp)
+	if n == 1 {
+		for i, v := range x {
+			switch {
+			case v < xp[0]:
+				ret[i] = left
+			case v > xp[0]:
+				ret[i] = right
+			default:
+				ret[i] = fp[0]
+			}
+		}
+		return nil
+	}
+	var slopes NpArray
+	if n <= len(x) {
+		slopes = make(NpArray, n-1)
+		for i := range slopes {
+			slopes[i] = (fp[i+1] - fp[i]) / (xp[i+1] - xp[i])
+		}
+	}
+	j := 0
+	for i, v := range x {
+		if math.IsNaN(v) {
+			ret[i] = v
+			continue
+		}
+		j = searchWithGuess(v, xp, j)
+		switch {
+		case j == -1:
+			ret[i] = left
+		case j == n:
+			ret[i] = right
+		case j == n-1:
+			ret[i] = fp[j]
+		case xp[j] == v:
+			// avoid a non-finite slope at exact sample points
+			ret[i] = fp[j]
+		default:
+			var slope float64
+			if slopes != nil {
+				slope = slopes[j]
+			} else {
+				slope = (fp[j+1] - fp[j]) / (xp[j+1] - xp[j])
+			}
+			ret[i] = slope*(v-xp[j]) + fp[j]
+			// if we get nan in one direction, try the other
+			if math.IsNaN(ret[i]) {
+				ret[i] = slope*(v-xp[j+1]) + fp[j+1]
+				if math.IsNaN(ret[i]) && fp[j] == fp[j+1] {
+					ret[i] = fp[j]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// likelyInCache bounds the local search around the guess, as in numpy.
+const likelyInCache = 8
+
+// searchWithGuess returns the index j with arr[j] <= key < arr[j+1], -1 below
+// the range and len(arr) above it. It checks the entries around guess before
+// bisecting, numpy's binary_search_with_guess.
+func searchWithGuess(key float64, arr NpArray, guess int) int {
+	n := len(arr)
+	imin, imax := 0, n
+	if key > arr[n-1] {
+		return n
+	} else if key < arr[0] {
+		return -1
+	}
+	if n <= 4 {
+		i := 0
+		for i < n && key >= arr[i] {
+			i++
+		}
+		return i - 1
+	}
+	if guess > n-3 {
+		guess = n - 3
+	}
+	if guess < 1 {
+		guess = 1
+	}
+	if key < arr[guess] {
+		if key >= arr[guess-1] {
+			return guess - 1
+		}
+		imax = guess - 1
+		if guess > likelyInCache && key >= arr[guess-likelyInCache] {
+			imin = guess - likelyInCache
+		}
+	} else {
+		if key < arr[guess+1] {
+			return guess
+		}
+		if key < arr[guess+2] {
+			return guess + 1
+		}
+		imin = guess + 2
+		if guess < n-likelyInCache-1 && key < arr[guess+likelyInCache] {
+			imax = guess + likelyInCache
+		}
+	}
+	for imin < imax {
+		imid := imin + (imax-imin)>>1
+		if key >= arr[imid] {
+			imin = imid + 1
+		} else {
+			imax = imid
+		}
+	}
+	return imin - 1
+}
+
+// periodicInterp wraps the queries and samples into [0, period) and pads the
+// samples with one wrapped point at each end, as numpy.interp does, though
+// with a stable sort.
+func periodicInterp(x, xp, fp NpArray, period float64) (NpArray, NpArray, NpArray) {
+	wrapped := make(NpArray, len(x))
+	for i, v := range x {
+		wrapped[i] = pyMod(v, period)
+	}
+	order := make([]int, len(xp))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return pyMod(xp[order[a]], period) < pyMod(xp[order[b]], period)
+	})
+	n := len(xp)
+	sxp := make(NpArray, n+2)
+	sfp := make(NpArray, n+2)
+	for i, k := range order {
+		sxp[i+1] = pyMod(xp[k], period)
+		sfp[i+1] = fp[k]
+	}
+	sxp[0], sfp[0] = sxp[n]-period, sfp[n]
+	sxp[n+1], sfp[n+1] = sxp[1]+period, sfp[1]
+	return wrapped, sxp, sfp
+}
+
+// pyMod is the floating point remainder with the sign of the divisor, like
+// python's % and numpy.remainder.
+func pyMod(a, b float64) float64 {
+	mod := math.Mod(a, b)
+	if mod == 0 {
+		return math.Copysign(0, b)
+	}
+	if (b < 0) != (mod < 0) {
+		mod += b
+	}
+	return mod
+}
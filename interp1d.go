@@ -0,0 +1,239 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// InterpKind selects the interpolant of an Interpolator, the kind argument
+// of scipy.interpolate.interp1d.
+type InterpKind int
+
+const (
+	InterpLinear InterpKind = iota
+	// InterpNearest rounds half way points down.
+	InterpNearest
+	// InterpNearestUp rounds half way points up.
+	InterpNearestUp
+	// InterpZero, InterpSLinear, InterpQuadratic and InterpCubic are
+	// interpolating B-splines of degree 0 to 3.
+	InterpZero
+	InterpSLinear
+	InterpQuadratic
+	InterpCubic
+	// InterpPrevious and InterpNext take the value of the sample point at or
+	// before, or at or after, each query.
+	InterpPrevious
+	InterpNext
+)
+
+// Interp1DOptions control queries outside [x[0], x[n-1]]. By default they
+// are filled with NaN, scipy raises unless bounds_error=False is given.
+type Interp1DOptions struct {
+	// BoundsError makes Eval fail on queries outside the range.
+	BoundsError bool
+	// FillBelow and FillAbove replace queries below and above the range,
+	// NaN when nil.
+	FillBelow, FillAbove *float64
+	// Extrapolate evaluates the end pieces outside the range, like
+	// fill_value="extrapolate".
+	Extrapolate bool
+}
+
+// Interpolator evaluates a 1-D interpolant through the points (x, y), like
+// the object returned by scipy.interpolate.interp1d.
+type Interpolator struct {
+	kind        InterpKind
+	x, y        NpArray
+	boundsError bool
+	extrapolate bool
+	below       float64
+	above       float64
+	// bounds holds the midpoints for nearest or the shifted x for
+	// previous/next
+	bounds NpArray
+	spline *bspline
+}
+
+// NewInterpolator builds an interpolant of the given kind. x need not be
+// sorted, the points are sorted by x first.
+func NewInterpolator(x, y NpArray, kind InterpKind, opts *Interp1DOptions) (*Interpolator, error) {
+	if opts == nil {
+		opts = &Interp1DOptions{}
+	}
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("x and y arrays must be equal in length along interpolation axis")
+	}
+	order := make([]int, len(x))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return x[order[a]] < x[order[b]] })
+	f := &Interpolator{
+		kind:        kind,
+		x:           make(NpArray, len(x)),
+		y:           make(NpArray, len(y)),
+		boundsError: opts.BoundsError,
+		extrapolate: opts.Extrapolate,
+		below:       math.NaN(),
+		above:       math.NaN(),
+	}
+	for i, k := range order {
+		f.x[i], f.y[i] = x[k], y[k]
+	}
+	if opts.FillBelow != nil {
+		f.below = *opts.FillBelow
+	}
+	if opts.FillAbove != nil {
+		f.above = *opts.FillAbove
+	}
+
+	minval := 1
+	if kind == InterpLinear {
+		minval = 2
+	}
+	if kind >= InterpZero && kind <= InterpCubic {
+		minval = int(kind-InterpZero) + 1
+	}
+	if len(f.x) < minval {
+		return nil, fmt.Errorf("x and y arrays must have at least %d entries", minval)
+	}
+
+	n := len(f.x)
+	switch kind {
+	case InterpLinear:
+	case InterpNearest, InterpNearestUp:
+		f.bounds = make(NpArray, n-1)
+		for i := range f.bounds {
+			f.bounds[i] = f.x[i]/2 + f.x[i+1]/2
+		}
+	case InterpPrevious, InterpNext:
+		f.bounds = make(NpArray, n)
+		dir := math.Inf(-1)
+		if kind == InterpNext {
+			dir = math.Inf(1)
+		}
+		for i, v := range f.x {
+			f.bounds[i] = math.Nextafter(v, dir)
+		}
+		if f.extrapolate {
+			// only the side the steps extend to can be extrapolated
+			f.extrapolate = false
+			if kind == InterpPrevious {
+				f.below, f.above = math.NaN(), f.y[n-1]
+			} else {
+				f.below, f.above = f.y[0], math.NaN()
+			}
+		}
+	case InterpZero, InterpSLinear, InterpQuadratic, InterpCubic:
+		if err := checkIncreasing(f.x); err != nil {
+			return nil, err
+		}
+		spline, err := makeInterpSpline(f.x, f.y, int(kind-InterpZero))
+		if err != nil {
+			return nil, err
+		}
+		f.spline = spline
+	default:
+		return nil, fmt.Errorf("unknown interpolation kind %d", kind)
+	}
+	return f, nil
+}
+
+// Eval evaluates the interpolant at each query point.
+func (f *Interpolator) Eval(xnew NpArray) (NpArray, error) {
+	var ret NpArray
+	n := len(f.x)
+	switch f.kind {
+	case InterpLinear:
+		if !f.extrapolate {
+			var err error
+			if ret, err = Interp(xnew, f.x, f.y, nil); err != nil {
+				return nil, err
+			}
+			break
+		}
+		ret = make(NpArray, len(xnew))
+		for i, v := range xnew {
+			hi := sort.SearchFloat64s(f.x, v)
+			if hi < 1 {
+				hi = 1
+			}
+			if hi > n-1 {
+				hi = n - 1
+			}
+			lo := hi - 1
+			slope := (f.y[hi] - f.y[lo]) / (f.x[hi] - f.x[lo])
+			ret[i] = slope*(v-f.x[lo]) + f.y[lo]
+		}
+	case InterpNearest, InterpNearestUp:
+		ret = make(NpArray, len(xnew))
+		for i, v := range xnew {
+			ret[i] = f.y[searchSide(f.bounds, v, f.kind == InterpNearestUp)]
+		}
+	case InterpPrevious, InterpNext:
+		ret = make(NpArray, len(xnew))
+		ind := 0
+		if f.kind == InterpNext {
+			ind = 1
+		}
+		for i, v := range xnew {
+			j := searchSide(f.bounds, v, f.kind == InterpNext)
+			if j < 1-ind {
+				j = 1 - ind
+			}
+			if j > n-ind {
+				j = n - ind
+			}
+			ret[i] = f.y[j+ind-1]
+		}
+	default:
+		ret = make(NpArray, len(xnew))
+		for i, v := range xnew {
+			ret[i] = f.spline.at(v)
+		}
+	}
+	if f.extrapolate {
+		return ret, nil
+	}
+	for i, v := range xnew {
+		switch {
+		case v < f.x[0]:
+			if f.boundsError {
+				return nil, fmt.Errorf("a value (%v) in x_new is below the interpolation range's minimum value (%v)", v, f.x[0])
+			}
+			ret[i] = f.below
+		case v > f.x[n-1]:
+			if f.boundsError {
+				return nil, fmt.Errorf("a value (%v) in x_new is above the interpolation range's maximum value (%v)", v, f.x[n-1])
+			}
+			ret[i] = f.above
+		}
+	}
+	return ret, nil
+}
+
+// searchSide is numpy's searchsorted with side='left', or side='right' when
+// right is set.
+func searchSide(a NpArray, v float64, right bool) int {
+	if right {
+		return sort.Search(len(a), func(i int) bool { return a[i] > v })
+	}
+	return sort.Search(len(a), func(i int) bool { return a[i] >= v })
+}
+
+// Interp1D resamples every row of m, sampled at x, onto xnew.
+func (m NpStack) Interp1D(x, xnew NpArray, kind InterpKind, opts *Interp1DOptions) (NpStack, error) {
+	ret := make(NpStack, len(m))
+	for i, row := range m {
+		f, err := NewInterpolator(x, row, kind, opts)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		if ret[i], err = f.Eval(xnew); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return ret, nil
+}
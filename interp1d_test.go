@@ -0,0 +1,91 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestInterpolator_StepKinds(t *testing.T) {
+	x := NpArray{0, 1, 2}
+	y := NpArray{10, 20, 30}
+	xnew := NpArray{0, 0.5, 0.6, 1, 1.5, 2}
+	cases := []struct {
+		kind     InterpKind
+		expected NpArray
+	}{
+		{InterpNearest, NpArray{10, 10, 20, 20, 20, 30}},
+		{InterpNearestUp, NpArray{10, 20, 20, 20, 30, 30}},
+		{InterpPrevious, NpArray{10, 10, 10, 20, 20, 30}},
+		{InterpNext, NpArray{10, 20, 20, 20, 30, 30}},
+		{InterpZero, NpArray{10, 10, 10, 20, 20, 30}},
+		{InterpLinear, NpArray{10, 15, 16, 20, 25, 30}},
+		{InterpSLinear, NpArray{10, 15, 16, 20, 25, 30}},
+	}
+	for _, c := range cases {
+		f, err := NewInterpolator(x, y, c.kind, nil)
+		assert.NoError(t, err)
+		ret, err := f.Eval(xnew)
+		assert.NoError(t, err)
+		assert.True(t, ret.AlmostEqual(c.expected, 1e-12), "kind %d: %v", c.kind, ret)
+	}
+}
+
+func TestInterpolator_Splines(t *testing.T) {
+	x := NpArray{3, 0, 1, 2, 5, 4}
+	cube := x.Cond(func(v float64) float64 { return v*v*v - v })
+	f, err := NewInterpolator(x, cube, InterpCubic, nil)
+	assert.NoError(t, err)
+	ret, err := f.Eval(NpArray{0.5, 4.25})
+	assert.NoError(t, err)
+	assert.True(t, ret.AlmostEqual(NpArray{-0.375, 72.515625}, 1e-9))
+
+	square := x.Cond(func(v float64) float64 { return v * v })
+	f, err = NewInterpolator(x, square, InterpQuadratic, nil)
+	assert.NoError(t, err)
+	ret, err = f.Eval(NpArray{2.5})
+	assert.NoError(t, err)
+	assert.InDelta(t, 6.25, ret[0], 1e-9)
+
+	_, err = NewInterpolator(NpArray{0, 1}, NpArray{0, 1}, InterpCubic, nil)
+	assert.Error(t, err)
+}
+
+func TestInterpolator_OutOfRange(t *testing.T) {
+	x := NpArray{0, 1, 2}
+	y := NpArray{10, 20, 40}
+	xnew := NpArray{-1, 3}
+
+	f, _ := NewInterpolator(x, y, InterpLinear, nil)
+	ret, err := f.Eval(xnew)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret[0]) && math.IsNaN(ret[1]))
+
+	below, above := -5.0, 5.0
+	f, _ = NewInterpolator(x, y, InterpLinear, &Interp1DOptions{FillBelow: &below, FillAbove: &above})
+	ret, _ = f.Eval(xnew)
+	assert.Equal(t, NpArray{-5, 5}, ret)
+
+	f, _ = NewInterpolator(x, y, InterpLinear, &Interp1DOptions{Extrapolate: true})
+	ret, _ = f.Eval(xnew)
+	assert.Equal(t, NpArray{0, 60}, ret)
+
+	f, _ = NewInterpolator(x, y, InterpPrevious, &Interp1DOptions{Extrapolate: true})
+	ret, _ = f.Eval(xnew)
+	assert.True(t, math.IsNaN(ret[0]))
+	assert.Equal(t, 40.0, ret[1])
+
+	f, err = NewInterpolator(NpArray{0, 1, 2, 3}, NpArray{1, 0, 1, 0}, InterpCubic, &Interp1DOptions{BoundsError: true})
+	assert.NoError(t, err)
+	_, err = f.Eval(xnew)
+	assert.Error(t, err)
+	_, err = f.Eval(NpArray{-0.5})
+	assert.Error(t, err)
+}
+
+func TestNpStack_Interp1D(t *testing.T) {
+	stack := NpStack{{0, 1, 8}, {1, 1, 1}}
+	ret, err := stack.Interp1D(NpArray{0, 1, 2}, NpArray{0.5, 1.5}, InterpLinear, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{0.5, 4.5}, {1, 1}}, ret)
+}
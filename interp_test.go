@@ -0,0 +1,83 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestInterp_NumpyExamples(t *testing.T) {
+	xp := NpArray{1, 2, 3}
+	fp := NpArray{3, 2, 0}
+	ret, err := Interp(NpArray{2.5}, xp, fp, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1}, ret)
+
+	ret, err = Interp(NpArray{0, 1, 1.5, 2.72, 3.14}, xp, fp, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{3, 3, 2.5, 0.5599999999999996, 0}, ret)
+
+	undef := -99.0
+	ret, err = Interp(NpArray{3.14, 0}, xp, fp, &InterpOptions{Right: &undef})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{-99, 3}, ret)
+
+	ret, err = Interp(NpArray{0, 5}, NpArray{2}, NpArray{7}, &InterpOptions{Left: &undef})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{-99, 7}, ret)
+}
+
+func TestInterp_Period(t *testing.T) {
+	x := NpArray{-180, -170, -185, 185, -10, -5, 0, 365}
+	xp := NpArray{190, -190, 350, -350}
+	fp := NpArray{5, 10, 3, 4}
+	ret, err := Interp(x, xp, fp, &InterpOptions{Period: 360})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{7.5, 5, 8.75, 6.25, 3, 3.25, 3.5, 3.75}, ret)
+
+	// 10 and 410 wrap to the same point and keep their order in xp
+	ret, err = Interp(NpArray{50, 250, 10}, NpArray{10, 410, 90}, NpArray{1, 2, 5}, &InterpOptions{Period: 400})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{3.5, 3, 2}, ret)
+}
+
+func TestInterp_MatchesLinearScan(t *testing.T) {
+	r := NewRandomState(3)
+	xp := LinSpace(0, 10, 200)
+	fp := r.RandN(200)
+	queries := r.Uniform(-1, 11, 1000)
+	sorted := queries.Copy()
+	r.Shuffle(queries)
+	for _, x := range []NpArray{queries, sorted} {
+		ret, err := Interp(x, xp, fp, nil)
+		assert.NoError(t, err)
+		for i, v := range x {
+			expected := fp[0]
+			if v >= xp[len(xp)-1] {
+				expected = fp[len(fp)-1]
+			} else if v > xp[0] {
+				for j := 0; j < len(xp)-1; j++ {
+					if v >= xp[j] && v < xp[j+1] {
+						expected = (fp[j+1]-fp[j])/(xp[j+1]-xp[j])*(v-xp[j]) + fp[j]
+						break
+					}
+				}
+			}
+			assert.Equal(t, expected, ret[i])
+		}
+	}
+}
+
+func TestInterp_NaNAndErrors(t *testing.T) {
+	ret, err := Interp(NpArray{math.NaN(), 1.5}, NpArray{1, 2}, NpArray{0, 1}, nil)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret[0]))
+	assert.Equal(t, 0.5, ret[1])
+
+	_, err = Interp(NpArray{1}, NpArray{1, 3, 2}, NpArray{0, 1, 2}, nil)
+	assert.Error(t, err)
+	_, err = Interp(NpArray{1}, NpArray{1, 2}, NpArray{0}, nil)
+	assert.Error(t, err)
+	_, err = Interp(NpArray{1}, NpArray{}, NpArray{}, nil)
+	assert.Error(t, err)
+}
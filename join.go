@@ -0,0 +1,473 @@
+package np
+
+import (
+	"fmt"
+)
+
+// asArrays converts every element of arrays with AsNDArray.
+func asArrays(arrays []interface{}) ([]NDArray, error) {
+	if len(arrays) == 0 {
+		return nil, fmt.Errorf("need at least one array to concatenate")
+	}
+	ret := make([]NDArray, len(arrays))
+	for i, v := range arrays {
+		a, err := AsNDArray(v)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = a
+	}
+	return ret, nil
+}
+
+// axisRange returns the view of a selecting start:stop along axis.
+func axisRange(a NDArray, axis, start, stop int) NDArray {
+	spans := make([]Span, axis+1)
+	for i := range spans {
+		spans[i] = All()
+	}
+	spans[axis] = Range(start, stop)
+	ret, _ := a.Slice(spans...)
+	return ret
+}
+
+// assign copies src into dst, which have the same shape.
+func assign(dst, src NDArray) {
+	eachPair(dst, src, func(pd, ps int) {
+		dst.data[pd] = src.data[ps]
+	})
+}
+
+// Concatenate joins arrays along an existing axis, or their flattened
+// forms for AxisNone, like numpy.concatenate. The arrays may be any of the
+// types accepted by AsNDArray and must match on every other axis.
+func Concatenate(arrays []interface{}, axis int) (NDArray, error) {
+	nds, err := asArrays(arrays)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return concatenate(nds, axis)
+}
+
+func concatenate(arrays []NDArray, axis int) (NDArray, error) {
+	if axis == AxisNone {
+		for i, a := range arrays {
+			arrays[i], _ = NewNDArray(a.Flatten(), a.Size())
+		}
+		axis = 0
+	}
+	first := arrays[0]
+	if first.Ndim() == 0 {
+		return NDArray{}, fmt.Errorf("zero-dimensional arrays cannot be concatenated")
+	}
+	axis, err := normalizeAxis(axis, first.Ndim())
+	if err != nil {
+		return NDArray{}, err
+	}
+	shape := copyInts(first.shape)
+	shape[axis] = 0
+	for i, a := range arrays {
+		if a.Ndim() != first.Ndim() {
+			return NDArray{}, fmt.Errorf("all the input arrays must have same number of dimensions, but the array at index 0 has %d dimension(s) and the array at index %d has %d dimension(s)", first.Ndim(), i, a.Ndim())
+		}
+		for d, n := range a.shape {
+			if d != axis && n != first.shape[d] {
+				return NDArray{}, fmt.Errorf("all the input array dimensions except for the concatenation axis must match exactly, but along dimension %d, the array at index 0 has size %d and the array at index %d has size %d", d, first.shape[d], i, n)
+			}
+		}
+		shape[axis] += a.shape[axis]
+	}
+	ret := NDZeros(shape...)
+	start := 0
+	for _, a := range arrays {
+		stop := start + a.shape[axis]
+		assign(axisRange(ret, axis, start, stop), a)
+		start = stop
+	}
+	return ret, nil
+}
+
+// Stack joins arrays of the same shape along a new axis, like numpy.stack.
+func Stack(arrays []interface{}, axis int) (NDArray, error) {
+	nds, err := asArrays(arrays)
+	if err != nil {
+		return NDArray{}, err
+	}
+	shape := nds[0].shape
+	for i, a := range nds {
+		if !equalInts(a.shape, shape) {
+			return NDArray{}, fmt.Errorf("all input arrays must have the same shape")
+		}
+		if nds[i], err = a.ExpandDims(axis); err != nil {
+			return NDArray{}, err
+		}
+	}
+	if axis < 0 {
+		axis += nds[0].Ndim()
+	}
+	return concatenate(nds, axis)
+}
+
+// VStack joins arrays row-wise, like numpy.vstack: an NpArray counts as a
+// single row, so per class NpStacks and extra rows can be mixed.
+func VStack(arrays []interface{}) (NDArray, error) {
+	nds, err := asArrays(arrays)
+	if err != nil {
+		return NDArray{}, err
+	}
+	for i, a := range nds {
+		for a.Ndim() < 2 {
+			a, _ = a.ExpandDims(0)
+		}
+		nds[i] = a
+	}
+	return concatenate(nds, 0)
+}
+
+// HStack joins arrays column-wise, like numpy.hstack: 1-D arrays are
+// joined end to end.
+func HStack(arrays []interface{}) (NDArray, error) {
+	nds, err := asArrays(arrays)
+	if err != nil {
+		return NDArray{}, err
+	}
+	for i, a := range nds {
+		if a.Ndim() == 0 {
+			nds[i], _ = a.ExpandDims(0)
+		}
+	}
+	if nds[0].Ndim() == 1 {
+		return concatenate(nds, 0)
+	}
+	return concatenate(nds, 1)
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitAt divides a along axis before each of the indices, like
+// numpy.split with a list of indices. The parts are views of a; indices
+// past the end give empty parts.
+func SplitAt(a interface{}, indices []int, axis int) ([]NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return nil, err
+	}
+	if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+		return nil, err
+	}
+	n := nd.shape[axis]
+	ret := make([]NDArray, 0, len(indices)+1)
+	start := 0
+	for _, stop := range append(copyInts(indices), n) {
+		// python slice semantics, as numpy applies them
+		ret = append(ret, axisRange(nd, axis, start, stop))
+		start = stop
+	}
+	return ret, nil
+}
+
+// Split divides a along axis into sections equal parts, like numpy.split.
+// The parts are views of a.
+func Split(a interface{}, sections, axis int) ([]NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return nil, err
+	}
+	if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+		return nil, err
+	}
+	if sections <= 0 {
+		return nil, fmt.Errorf("number sections must be larger than 0")
+	}
+	if nd.shape[axis]%sections != 0 {
+		return nil, fmt.Errorf("array split does not result in an equal division")
+	}
+	return ArraySplit(nd, sections, axis)
+}
+
+// ArraySplit divides a along axis into sections parts, like
+// numpy.array_split: when the length does not divide evenly the leading
+// parts are one longer.
+func ArraySplit(a interface{}, sections, axis int) ([]NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return nil, err
+	}
+	if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+		return nil, err
+	}
+	if sections <= 0 {
+		return nil, fmt.Errorf("number sections must be larger than 0")
+	}
+	n := nd.shape[axis]
+	each, extra := n/sections, n%sections
+	indices := make([]int, 0, sections-1)
+	stop := 0
+	for i := 0; i < sections-1; i++ {
+		stop += each
+		if i < extra {
+			stop++
+		}
+		indices = append(indices, stop)
+	}
+	return SplitAt(nd, indices, axis)
+}
+
+// Tile repeats a reps[i] times along each axis, like numpy.tile. a gains
+// leading axes when reps is longer than its shape, and reps is padded with
+// leading ones when shorter.
+func Tile(a interface{}, reps []int) (NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	for _, r := range reps {
+		if r < 0 {
+			return NDArray{}, fmt.Errorf("negative dimensions are not allowed")
+		}
+	}
+	for nd.Ndim() < len(reps) {
+		nd, _ = nd.ExpandDims(0)
+	}
+	full := make([]int, nd.Ndim())
+	for i := range full {
+		full[i] = 1
+	}
+	copy(full[len(full)-len(reps):], reps)
+	shape := make([]int, nd.Ndim())
+	for i, d := range nd.shape {
+		shape[i] = d * full[i]
+	}
+	ret := NDZeros(shape...)
+	src := make([]int, len(shape))
+	pos := 0
+	eachIndex(shape, func(idx []int) {
+		for i, v := range idx {
+			src[i] = v % nd.shape[i]
+		}
+		ret.data[pos] = nd.At(src...)
+		pos++
+	})
+	return ret, nil
+}
+
+// Repeat repeats each entry along axis, or of the flattened array for
+// AxisNone, like numpy.repeat. repeats holds one count for every entry or a
+// single count for all of them.
+func Repeat(a interface{}, repeats []int, axis int) (NDArray, error) {
+	nd, axis, err := flatAxis(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	n := nd.shape[axis]
+	counts := repeats
+	switch len(repeats) {
+	case n:
+	case 1:
+		counts = make([]int, n)
+		for i := range counts {
+			counts[i] = repeats[0]
+		}
+	default:
+		return NDArray{}, &ShapeError{Shapes: [][]int{{n}, {len(repeats)}}}
+	}
+	var indices []int
+	for i, c := range counts {
+		if c < 0 {
+			return NDArray{}, fmt.Errorf("repeats may not contain negative values")
+		}
+		for k := 0; k < c; k++ {
+			indices = append(indices, i)
+		}
+	}
+	return Take(nd, indices, axis)
+}
+
+// PadMode selects how Pad fills the new entries, numpy.pad's mode. The
+// zero value is numpy's default.
+type PadMode int
+
+const (
+	// PadConstant fills with a constant value, zero by default.
+	PadConstant PadMode = iota
+	// PadEdge repeats the edge values: a a a | a b c d | d d d.
+	PadEdge
+	// PadReflect mirrors about the edge values: d c b | a b c d | c b a.
+	PadReflect
+	// PadSymmetric mirrors including the edge values: c b a | a b c d | d c b.
+	PadSymmetric
+	// PadWrap repeats the array periodically: b c d | a b c d | a b c.
+	PadWrap
+)
+
+func (m PadMode) String() string {
+	switch m {
+	case PadConstant:
+		return "constant"
+	case PadEdge:
+		return "edge"
+	case PadReflect:
+		return "reflect"
+	case PadSymmetric:
+		return "symmetric"
+	case PadWrap:
+		return "wrap"
+	}
+	return fmt.Sprintf("PadMode(%d)", int(m))
+}
+
+// PadOptions are the optional arguments of Pad, the zero value pads with
+// zeros.
+type PadOptions struct {
+	Mode PadMode
+	// CVal is the value used by PadConstant.
+	CVal float64
+}
+
+// padIndex maps position i of an axis of length n extended by before
+// entries onto the original entries, -1 for a constant.
+func padIndex(i, before, n int, mode PadMode) int {
+	j := i - before
+	if j >= 0 && j < n {
+		return j
+	}
+	switch mode {
+	case PadEdge:
+		if j < 0 {
+			return 0
+		}
+		return n - 1
+	case PadReflect:
+		if n == 1 {
+			return 0
+		}
+		period := 2 * (n - 1)
+		j = ((j % period) + period) % period
+		if j >= n {
+			j = period - j
+		}
+		return j
+	case PadSymmetric:
+		period := 2 * n
+		j = ((j % period) + period) % period
+		if j >= n {
+			j = period - 1 - j
+		}
+		return j
+	case PadWrap:
+		return ((j % n) + n) % n
+	}
+	return -1
+}
+
+// Pad extends a by width[i][0] entries before and width[i][1] after along
+// each axis i, like numpy.pad. width holds one pair for every axis or a
+// single pair for all of them.
+func Pad(a interface{}, width [][2]int, opts *PadOptions) (NDArray, error) {
+	if opts == nil {
+		opts = &PadOptions{}
+	}
+	if opts.Mode < PadConstant || opts.Mode > PadWrap {
+		return NDArray{}, fmt.Errorf("mode %v is not supported", opts.Mode)
+	}
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	widths, err := perAxis(width, nd.Ndim(), "width")
+	if err != nil {
+		return NDArray{}, err
+	}
+	shape := copyInts(nd.shape)
+	for i, w := range widths {
+		if w[0] < 0 || w[1] < 0 {
+			return NDArray{}, fmt.Errorf("index can't contain negative values")
+		}
+		if nd.shape[i] == 0 && opts.Mode != PadConstant && w[0]+w[1] > 0 {
+			return NDArray{}, fmt.Errorf("can't extend empty axis %d using modes other than 'constant'", i)
+		}
+		shape[i] += w[0] + w[1]
+	}
+	ret := NDZeros(shape...)
+	src := make([]int, len(shape))
+	pos := 0
+	eachIndex(shape, func(idx []int) {
+		value := opts.CVal
+		inside := true
+		for i, v := range idx {
+			src[i] = padIndex(v, widths[i][0], nd.shape[i], opts.Mode)
+			if src[i] < 0 {
+				inside = false
+			}
+		}
+		if inside {
+			value = nd.At(src...)
+		}
+		ret.data[pos] = value
+		pos++
+	})
+	return ret, nil
+}
+
+// Roll shifts the entries of a along axis, wrapping around, like
+// numpy.roll. With AxisNone the flattened array is rolled and the shape
+// kept.
+func Roll(a interface{}, shift, axis int) (NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if axis == AxisNone {
+		flat, _ := NewNDArray(nd.Flatten(), nd.Size())
+		rolled, err := Roll(flat, shift, 0)
+		if err != nil {
+			return NDArray{}, err
+		}
+		return rolled.Reshape(nd.shape...)
+	}
+	if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+		return NDArray{}, err
+	}
+	n := nd.shape[axis]
+	if n == 0 {
+		return nd.Copy(), nil
+	}
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = (((i - shift) % n) + n) % n
+	}
+	return Take(nd, indices, axis)
+}
+
+// Flip reverses a along axis, or along every axis for AxisNone, like
+// numpy.flip. The result is a view of a.
+func Flip(a interface{}, axis int) (NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	spans := make([]Span, nd.Ndim())
+	for i := range spans {
+		spans[i] = All()
+		if axis == AxisNone {
+			spans[i] = Reversed()
+		}
+	}
+	if axis != AxisNone {
+		if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+			return NDArray{}, err
+		}
+		spans[axis] = Reversed()
+	}
+	return nd.Slice(spans...)
+}
@@ -0,0 +1,223 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConcatenateStack(t *testing.T) {
+	// the numpy.concatenate examples
+	a := NpStack{{1, 2}, {3, 4}}
+	b := NpStack{{5, 6}}
+	ret, err := Concatenate([]interface{}{a, b}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, ret.Flatten())
+	bt, _ := b.ToNDArray()
+	ret, err = Concatenate([]interface{}{a, bt.T()}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 5, 3, 4, 6}, ret.Flatten())
+	ret, err = Concatenate([]interface{}{a, b}, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6}, ret.Shape())
+
+	_, err = Concatenate([]interface{}{a, b}, 1)
+	assert.Error(t, err)
+	_, err = Concatenate([]interface{}{a, NpArray{1, 2}}, 0)
+	assert.Error(t, err)
+	_, err = Concatenate(nil, 0)
+	assert.Error(t, err)
+	_, err = Concatenate([]interface{}{1.0, 2.0}, 0)
+	assert.Error(t, err)
+
+	// the numpy.stack examples
+	x, y := NpArray{1, 2, 3}, NpArray{4, 5, 6}
+	ret, err = Stack([]interface{}{x, y}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, ret.Flatten())
+	ret, err = Stack([]interface{}{x, y}, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2}, ret.Shape())
+	assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, ret.Flatten())
+	_, err = Stack([]interface{}{x, NpArray{1}}, 0)
+	assert.Error(t, err)
+	_, err = Stack([]interface{}{x, y}, 3)
+	assert.Error(t, err)
+}
+
+func TestVStackHStack(t *testing.T) {
+	// the numpy.vstack and numpy.hstack examples
+	a, b := NpArray{1, 2, 3}, NpArray{4, 5, 6}
+	ret, err := VStack([]interface{}{a, b})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, ret.Flatten())
+	ret, err = HStack([]interface{}{a, b})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6}, ret.Shape())
+	ret, err = HStack([]interface{}{NpStack{{1}, {2}, {3}}, NpStack{{4}, {5}, {6}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2}, ret.Shape())
+	assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, ret.Flatten())
+
+	// per class stacks and single rows together
+	ret, err = VStack([]interface{}{NpStack{{1, 1}, {2, 2}}, NpArray{3, 3}, NpStack{{4, 4}}})
+	assert.NoError(t, err)
+	m, err := ret.ToNpStack()
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{1, 1}, {2, 2}, {3, 3}, {4, 4}}, m)
+	_, err = VStack([]interface{}{NpStack{{1, 1}}, NpArray{3}})
+	assert.Error(t, err)
+}
+
+func TestSplit(t *testing.T) {
+	// the numpy.split and numpy.array_split examples
+	x := Arrange(0, 9, 1)
+	parts, err := Split(x, 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(parts))
+	assert.Equal(t, []float64{3, 4, 5}, parts[1].Flatten())
+	_, err = Split(x, 4, 0)
+	assert.Error(t, err)
+
+	parts, err = SplitAt(Arrange(0, 8, 1), []int{3, 5, 6, 10}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(parts))
+	assert.Equal(t, []float64{0, 1, 2}, parts[0].Flatten())
+	assert.Equal(t, []float64{3, 4}, parts[1].Flatten())
+	assert.Equal(t, []float64{5}, parts[2].Flatten())
+	assert.Equal(t, []float64{6, 7}, parts[3].Flatten())
+	assert.Equal(t, []float64{}, parts[4].Flatten())
+
+	parts, err = ArraySplit(Arrange(0, 8, 1), 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2}, parts[0].Flatten())
+	assert.Equal(t, []float64{3, 4, 5}, parts[1].Flatten())
+	assert.Equal(t, []float64{6, 7}, parts[2].Flatten())
+
+	// a train and test split of rows, as views
+	m := NpStack{{1, 2}, {3, 4}, {5, 6}, {7, 8}}
+	parts, err = SplitAt(m, []int{3}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2}, parts[0].Shape())
+	assert.Equal(t, []float64{7, 8}, parts[1].Flatten())
+	parts, err = Split(m, 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 4, 6, 8}, parts[1].Flatten())
+	_, err = ArraySplit(m, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestTileRepeat(t *testing.T) {
+	// the numpy.tile examples
+	a := NpArray{0, 1, 2}
+	ret, err := Tile(a, []int{2})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2, 0, 1, 2}, ret.Flatten())
+	ret, err = Tile(a, []int{2, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 6}, ret.Shape())
+	assert.Equal(t, []float64{0, 1, 2, 0, 1, 2, 0, 1, 2, 0, 1, 2}, ret.Flatten())
+	b := NpStack{{1, 2}, {3, 4}}
+	ret, err = Tile(b, []int{2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 1, 2, 3, 4, 3, 4}, ret.Flatten())
+	ret, err = Tile(b, []int{2, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3, 4, 1, 2, 3, 4}, ret.Flatten())
+	_, err = Tile(a, []int{-1})
+	assert.Error(t, err)
+
+	// the numpy.repeat examples
+	ret, err = Repeat(3.0, []int{4}, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 3, 3, 3}, ret.Flatten())
+	ret, err = Repeat(b, []int{2}, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 2, 2, 3, 3, 4, 4}, ret.Flatten())
+	ret, err = Repeat(b, []int{3}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 1, 2, 2, 2, 3, 3, 3, 4, 4, 4}, ret.Flatten())
+	ret, err = Repeat(b, []int{1, 2}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2}, ret.Shape())
+	assert.Equal(t, []float64{1, 2, 3, 4, 3, 4}, ret.Flatten())
+	_, err = Repeat(b, []int{1, 2, 3}, 0)
+	assert.Error(t, err)
+	_, err = Repeat(b, []int{-1}, 0)
+	assert.Error(t, err)
+}
+
+func TestPad(t *testing.T) {
+	// the numpy.pad examples
+	a := NpArray{1, 2, 3, 4, 5}
+	ret, err := Pad(a, [][2]int{{2, 3}}, &PadOptions{CVal: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4, 4, 1, 2, 3, 4, 5, 4, 4, 4}, ret.Flatten())
+	ret, err = Pad(a, [][2]int{{2, 3}}, &PadOptions{Mode: PadEdge})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 1, 2, 3, 4, 5, 5, 5, 5}, ret.Flatten())
+	ret, err = Pad(a, [][2]int{{2, 3}}, &PadOptions{Mode: PadReflect})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 2, 1, 2, 3, 4, 5, 4, 3, 2}, ret.Flatten())
+	ret, err = Pad(a, [][2]int{{2, 3}}, &PadOptions{Mode: PadSymmetric})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 1, 1, 2, 3, 4, 5, 5, 4, 3}, ret.Flatten())
+	ret, err = Pad(a, [][2]int{{2, 3}}, &PadOptions{Mode: PadWrap})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4, 5, 1, 2, 3, 4, 5, 1, 2, 3}, ret.Flatten())
+
+	// wider than the array keeps reflecting
+	ret, err = Pad(NpArray{1, 2, 3}, [][2]int{{5, 5}}, &PadOptions{Mode: PadReflect})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 1, 2, 3, 2, 1, 2, 3, 2, 1, 2, 3, 2}, ret.Flatten())
+
+	ret, err = Pad(NpStack{{1, 2}, {3, 4}}, [][2]int{{1, 0}, {0, 1}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 3}, ret.Shape())
+	assert.Equal(t, []float64{0, 0, 0, 1, 2, 0, 3, 4, 0}, ret.Flatten())
+	ret, err = Pad(NpStack{{1, 2}, {3, 4}}, [][2]int{{1, 1}}, &PadOptions{Mode: PadEdge})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 2, 2, 1, 1, 2, 2, 3, 3, 4, 4, 3, 3, 4, 4}, ret.Flatten())
+
+	_, err = Pad(a, [][2]int{{-1, 0}}, nil)
+	assert.Error(t, err)
+	_, err = Pad(NpArray{}, [][2]int{{1, 1}}, &PadOptions{Mode: PadWrap})
+	assert.Error(t, err)
+	_, err = Pad(a, [][2]int{{1, 1}, {1, 1}}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, "symmetric", PadSymmetric.String())
+}
+
+func TestRollFlip(t *testing.T) {
+	// the numpy.roll examples
+	x := Arrange(0, 10, 1)
+	ret, err := Roll(x, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{8, 9, 0, 1, 2, 3, 4, 5, 6, 7}, ret.Flatten())
+	ret, err = Roll(x, -2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 3, 4, 5, 6, 7, 8, 9, 0, 1}, ret.Flatten())
+	x2, _ := x.ToNDArray().Reshape(2, 5)
+	ret, err = Roll(x2, 1, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 5}, ret.Shape())
+	assert.Equal(t, []float64{9, 0, 1, 2, 3, 4, 5, 6, 7, 8}, ret.Flatten())
+	ret, err = Roll(x2, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{5, 6, 7, 8, 9, 0, 1, 2, 3, 4}, ret.Flatten())
+	ret, err = Roll(x2, -1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3, 4, 0, 6, 7, 8, 9, 5}, ret.Flatten())
+
+	ret, err = Flip(x2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4, 3, 2, 1, 0, 9, 8, 7, 6, 5}, ret.Flatten())
+	ret, err = Flip(x2, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, ret.Flatten())
+	_, err = Flip(x2, 2)
+	assert.Error(t, err)
+}
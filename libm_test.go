@@ -0,0 +1,61 @@
+package np
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testdata/libm.py writes the fixture from the C library, including
+// arguments it does not round correctly.
+func TestLibm_Glibc(t *testing.T) {
+	buf, err := os.ReadFile(filepath.Join("testdata", "libm.bin"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	next := func() float64 {
+		v := math.Float64frombits(binary.LittleEndian.Uint64(buf))
+		buf = buf[8:]
+		return v
+	}
+	var counts [4]int
+	for i := range counts {
+		counts[i] = int(binary.LittleEndian.Uint64(buf))
+		buf = buf[8:]
+	}
+	for i, f := range []func(float64) float64{logLibm, expLibm, log1pLibm} {
+		for n := 0; n < counts[i]; n++ {
+			x, want := next(), next()
+			assert.Equal(t, math.Float64bits(want), math.Float64bits(f(x)), "function %d of %v", i, x)
+		}
+	}
+	for n := 0; n < counts[3]; n++ {
+		x, y, want := next(), next(), next()
+		assert.Equal(t, math.Float64bits(want), math.Float64bits(powLibm(x, y)), "pow(%v, %v)", x, y)
+	}
+	assert.Empty(t, buf)
+}
+
+func TestLibm_Special(t *testing.T) {
+	assert.True(t, math.IsInf(logLibm(0), -1))
+	assert.True(t, math.IsNaN(logLibm(-1)))
+	assert.True(t, math.IsNaN(logLibm(math.NaN())))
+	assert.Equal(t, math.Inf(1), logLibm(math.Inf(1)))
+	assert.Equal(t, math.Inf(1), expLibm(710))
+	assert.Equal(t, 0.0, expLibm(-746))
+	assert.Equal(t, 0.0, expLibm(math.Inf(-1)))
+	assert.True(t, math.IsNaN(expLibm(math.NaN())))
+	assert.True(t, math.IsInf(log1pLibm(-1), -1))
+	assert.True(t, math.IsNaN(log1pLibm(-2)))
+	assert.Equal(t, 1.0, powLibm(math.NaN(), 0))
+	assert.Equal(t, 1.0, powLibm(1, math.NaN()))
+	assert.Equal(t, -512.0, powLibm(-8, 3))
+	assert.True(t, math.IsNaN(powLibm(-8, 0.5)))
+	assert.Equal(t, math.Inf(-1), powLibm(math.Copysign(0, -1), -3))
+	assert.Equal(t, math.Inf(1), powLibm(0, -2))
+	assert.Equal(t, 0.0, powLibm(0.5, math.Inf(1)))
+	assert.Equal(t, math.Inf(-1), powLibm(-10, 1001))
+}
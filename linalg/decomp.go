@@ -0,0 +1,430 @@
+package linalg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	np "github.com/mdcfrancis/gonp"
+)
+
+// maxSweeps bounds the Jacobi iterations of SVD and Eigh.
+const maxSweeps = 100
+
+// QR returns the reduced QR decomposition a = q r, like numpy.linalg.qr with
+// mode='reduced'. For an m by n matrix q is m by k with orthonormal columns
+// and r is k by n upper triangular, k = min(m, n). Householder reflections
+// are chosen as LAPACK's dgeqrf does, so the signs match numpy.
+func QR(a interface{}) (q, r np.NDArray, err error) {
+	m, err := asMatrix(a)
+	if err != nil {
+		return np.NDArray{}, np.NDArray{}, err
+	}
+	rows, cols := m.rows, m.cols
+	k := rows
+	if cols < k {
+		k = cols
+	}
+	work := m.copy()
+	vs := make([][]float64, k)
+	taus := make([]float64, k)
+	for j := 0; j < k; j++ {
+		// the reflector H = I - tau v vᵀ zeroing work[j+1:, j]
+		alpha := work.at(j, j)
+		xnorm := 0.0
+		for i := j + 1; i < rows; i++ {
+			xnorm = math.Hypot(xnorm, work.at(i, j))
+		}
+		v := make([]float64, rows)
+		v[j] = 1
+		if xnorm == 0 {
+			vs[j] = v
+			continue
+		}
+		beta := -math.Copysign(math.Hypot(alpha, xnorm), alpha)
+		taus[j] = (beta - alpha) / beta
+		for i := j + 1; i < rows; i++ {
+			v[i] = work.at(i, j) / (alpha - beta)
+		}
+		vs[j] = v
+		applyReflector(work, v, taus[j], j)
+	}
+	rm := newMatrix(k, cols)
+	for i := 0; i < k; i++ {
+		for j := i; j < cols; j++ {
+			rm.set(i, j, work.at(i, j))
+		}
+	}
+	// accumulate q = H_0 H_1 ... H_(k-1) applied to the first k columns of I
+	qm := newMatrix(rows, k)
+	for i := 0; i < k; i++ {
+		qm.set(i, i, 1)
+	}
+	for j := k - 1; j >= 0; j-- {
+		applyReflector(qm, vs[j], taus[j], j)
+	}
+	return qm.nd(), rm.nd(), nil
+}
+
+// applyReflector replaces m with (I - tau v vᵀ) m, v being zero above row
+// first.
+func applyReflector(m *matrix, v []float64, tau float64, first int) {
+	if tau == 0 {
+		return
+	}
+	for c := 0; c < m.cols; c++ {
+		dot := 0.0
+		for i := first; i < m.rows; i++ {
+			dot += v[i] * m.at(i, c)
+		}
+		dot *= tau
+		for i := first; i < m.rows; i++ {
+			m.data[i*m.cols+c] -= dot * v[i]
+		}
+	}
+}
+
+// Cholesky returns the lower triangular l with a = l lᵀ, like
+// numpy.linalg.cholesky. Only the lower triangle of a is used. A matrix that
+// is not positive definite returns ErrNotPositiveDefinite.
+func Cholesky(a interface{}) (np.NDArray, error) {
+	m, err := asSquare(a)
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	n := m.rows
+	l := newMatrix(n, n)
+	for j := 0; j < n; j++ {
+		d := m.at(j, j)
+		for k := 0; k < j; k++ {
+			d -= l.at(j, k) * l.at(j, k)
+		}
+		if !(d > 0) {
+			return np.NDArray{}, fmt.Errorf("cholesky: %w", ErrNotPositiveDefinite)
+		}
+		d = math.Sqrt(d)
+		l.set(j, j, d)
+		for i := j + 1; i < n; i++ {
+			s := m.at(i, j)
+			for k := 0; k < j; k++ {
+				s -= l.at(i, k) * l.at(j, k)
+			}
+			l.set(i, j, s/d)
+		}
+	}
+	return l.nd(), nil
+}
+
+// svd is a singular value decomposition a = u diag(s) vᵀ with s descending.
+type svd struct {
+	u, v *matrix
+	s    []float64
+}
+
+// jacobiSVD decomposes a with one-sided Jacobi rotations, which is accurate
+// for small singular values. u is m by k and v is n by k, k = min(m, n).
+func jacobiSVD(a *matrix) (*svd, error) {
+	if a.rows < a.cols {
+		t, err := jacobiSVD(a.transpose())
+		if err != nil {
+			return nil, err
+		}
+		return &svd{u: t.v, v: t.u, s: t.s}, nil
+	}
+	m, n := a.rows, a.cols
+	u := a.copy()
+	v := identity(n)
+	converged := false
+	for sweep := 0; sweep < maxSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for i := 0; i < m; i++ {
+					up, uq := u.at(i, p), u.at(i, q)
+					alpha += up * up
+					beta += uq * uq
+					gamma += up * uq
+				}
+				if gamma == 0 || math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				converged = false
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				rotateColumns(u, p, q, c, s)
+				rotateColumns(v, p, q, c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, fmt.Errorf("svd: %w", ErrNoConvergence)
+	}
+	sv := make([]float64, n)
+	for j := range sv {
+		norm := 0.0
+		for i := 0; i < m; i++ {
+			norm = math.Hypot(norm, u.at(i, j))
+		}
+		sv[j] = norm
+		if norm > 0 {
+			for i := 0; i < m; i++ {
+				u.set(i, j, u.at(i, j)/norm)
+			}
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sv[order[i]] > sv[order[j]] })
+	ret := &svd{u: newMatrix(m, n), v: newMatrix(n, n), s: make([]float64, n)}
+	for c, j := range order {
+		ret.s[c] = sv[j]
+		for i := 0; i < m; i++ {
+			ret.u.set(i, c, u.at(i, j))
+		}
+		for i := 0; i < n; i++ {
+			ret.v.set(i, c, v.at(i, j))
+		}
+	}
+	// columns of u for zero singular values are not determined by a
+	rank := 0
+	for _, s := range ret.s {
+		if s > 0 {
+			rank++
+		}
+	}
+	completeBasis(ret.u, rank)
+	return ret, nil
+}
+
+func rotateColumns(m *matrix, p, q int, c, s float64) {
+	for i := 0; i < m.rows; i++ {
+		mp, mq := m.at(i, p), m.at(i, q)
+		m.set(i, p, c*mp-s*mq)
+		m.set(i, q, s*mp+c*mq)
+	}
+}
+
+// completeBasis replaces columns from valid on with unit vectors orthogonal
+// to the preceding columns.
+func completeBasis(m *matrix, valid int) {
+	candidate := 0
+	for j := valid; j < m.cols; j++ {
+		for ; candidate < m.rows; candidate++ {
+			v := make([]float64, m.rows)
+			v[candidate] = 1
+			// orthogonalise twice for stability
+			for pass := 0; pass < 2; pass++ {
+				for c := 0; c < j; c++ {
+					dot := 0.0
+					for i := range v {
+						dot += v[i] * m.at(i, c)
+					}
+					for i := range v {
+						v[i] -= dot * m.at(i, c)
+					}
+				}
+			}
+			norm := 0.0
+			for _, x := range v {
+				norm = math.Hypot(norm, x)
+			}
+			if norm > 0.5 {
+				for i, x := range v {
+					m.set(i, j, x/norm)
+				}
+				candidate++
+				break
+			}
+		}
+	}
+}
+
+func singularValues(m *matrix) ([]float64, error) {
+	d, err := jacobiSVD(m)
+	if err != nil {
+		return nil, err
+	}
+	return d.s, nil
+}
+
+// SVD returns the singular value decomposition a = u diag(s) vt with the
+// singular values in descending order, like numpy.linalg.svd. With
+// fullMatrices u is m by m and vt is n by n, otherwise they are m by k and k
+// by n, k = min(m, n). Singular vectors are only determined up to sign, and
+// for repeated singular values up to rotation, so they may differ from
+// numpy's while still reconstructing a.
+func SVD(a interface{}, fullMatrices bool) (u np.NDArray, s np.NpArray, vt np.NDArray, err error) {
+	m, err := asMatrix(a)
+	if err != nil {
+		return np.NDArray{}, nil, np.NDArray{}, err
+	}
+	d, err := jacobiSVD(m)
+	if err != nil {
+		return np.NDArray{}, nil, np.NDArray{}, err
+	}
+	um, vm := d.u, d.v
+	if fullMatrices {
+		um = extend(um, m.rows)
+		vm = extend(vm, m.cols)
+	}
+	return um.nd(), np.NpArray(d.s), vm.transpose().nd(), nil
+}
+
+// extend widens m with orthonormal columns up to cols columns.
+func extend(m *matrix, cols int) *matrix {
+	if m.cols == cols {
+		return m
+	}
+	ret := newMatrix(m.rows, cols)
+	for i := 0; i < m.rows; i++ {
+		copy(ret.data[i*cols:i*cols+m.cols], m.data[i*m.cols:(i+1)*m.cols])
+	}
+	completeBasis(ret, m.cols)
+	return ret
+}
+
+// Eigh returns the eigenvalues, in ascending order, and the eigenvectors, as
+// columns, of a symmetric matrix, like numpy.linalg.eigh. Only the lower
+// triangle of a is used. Eigenvectors are determined up to sign.
+func Eigh(a interface{}) (w np.NpArray, v np.NDArray, err error) {
+	m, err := asSquare(a)
+	if err != nil {
+		return nil, np.NDArray{}, err
+	}
+	n := m.rows
+	s := newMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			s.set(i, j, m.at(i, j))
+			s.set(j, i, m.at(i, j))
+		}
+	}
+	vecs := identity(n)
+	converged := false
+	for sweep := 0; sweep < maxSweeps && !converged; sweep++ {
+		off, diag := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			diag += s.at(i, i) * s.at(i, i)
+			for j := i + 1; j < n; j++ {
+				off += s.at(i, j) * s.at(i, j)
+			}
+		}
+		if off <= eps*eps*diag || off == 0 {
+			converged = true
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := s.at(p, q)
+				if apq == 0 {
+					continue
+				}
+				// the rotation zeroing s[p][q]
+				theta := (s.at(q, q) - s.at(p, p)) / (2 * apq)
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(1+theta*theta))
+				c := 1 / math.Sqrt(1+t*t)
+				sn := t * c
+				rotateColumns(s, p, q, c, sn)
+				// and the same rotation of the rows
+				for j := 0; j < n; j++ {
+					sp, sq := s.at(p, j), s.at(q, j)
+					s.set(p, j, c*sp-sn*sq)
+					s.set(q, j, sn*sp+c*sq)
+				}
+				s.set(p, q, 0)
+				s.set(q, p, 0)
+				rotateColumns(vecs, p, q, c, sn)
+			}
+		}
+	}
+	if !converged {
+		return nil, np.NDArray{}, fmt.Errorf("eigh: eigenvalues %w", ErrNoConvergence)
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s.at(order[i], order[i]) < s.at(order[j], order[j]) })
+	w = make(np.NpArray, n)
+	vm := newMatrix(n, n)
+	for c, j := range order {
+		w[c] = s.at(j, j)
+		for i := 0; i < n; i++ {
+			vm.set(i, c, vecs.at(i, j))
+		}
+	}
+	return w, vm.nd(), nil
+}
+
+// LstsqResult holds the results of Lstsq.
+type LstsqResult struct {
+	// X minimises ‖b - a x‖₂, a vector when b is one.
+	X np.NDArray
+	// Residuals holds the squared residual norm of each column of b, empty
+	// unless a has full column rank and more rows than columns.
+	Residuals np.NpArray
+	Rank      int
+	// S holds the singular values of a.
+	S np.NpArray
+}
+
+// Lstsq returns the least squares solution of a x = b, like
+// numpy.linalg.lstsq. Singular values below rcond times the largest are
+// treated as zero, rcond <= 0 uses numpy's default of machine precision
+// times max(m, n).
+func Lstsq(a, b interface{}, rcond float64) (*LstsqResult, error) {
+	m, err := asMatrix(a)
+	if err != nil {
+		return nil, err
+	}
+	rhs, vector, err := asRHS(b, m.rows)
+	if err != nil {
+		return nil, err
+	}
+	d, err := jacobiSVD(m)
+	if err != nil {
+		return nil, err
+	}
+	if rcond <= 0 {
+		rcond = eps * math.Max(float64(m.rows), float64(m.cols))
+	}
+	cutoff := 0.0
+	if len(d.s) > 0 {
+		cutoff = rcond * d.s[0]
+	}
+	rank := 0
+	for _, s := range d.s {
+		if s > cutoff {
+			rank++
+		}
+	}
+	// x = v diag(1/s) uᵀ b over the first rank singular values
+	utb := d.u.transpose().mul(rhs)
+	for i := 0; i < utb.rows; i++ {
+		for c := 0; c < utb.cols; c++ {
+			if i < rank {
+				utb.set(i, c, utb.at(i, c)/d.s[i])
+			} else {
+				utb.set(i, c, 0)
+			}
+		}
+	}
+	x := d.v.mul(utb)
+	ret := &LstsqResult{X: result(x, vector), Rank: rank, S: np.NpArray(d.s), Residuals: np.NpArray{}}
+	if rank == m.cols && m.rows > m.cols {
+		fit := m.mul(x)
+		ret.Residuals = make(np.NpArray, rhs.cols)
+		for c := range ret.Residuals {
+			for i := 0; i < m.rows; i++ {
+				r := rhs.at(i, c) - fit.at(i, c)
+				ret.Residuals[c] += r * r
+			}
+		}
+	}
+	return ret, nil
+}
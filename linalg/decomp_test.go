@@ -0,0 +1,140 @@
+package linalg
+
+import (
+	np "github.com/mdcfrancis/gonp"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// assertOrthonormalColumns checks qᵀq = I.
+func assertOrthonormalColumns(t *testing.T, q np.NDArray) {
+	t.Helper()
+	qt := q.T()
+	prod, err := MatMul(qt, q)
+	assert.NoError(t, err)
+	assertClose(t, identity(q.Shape()[1]).data, prod, 1e-13)
+}
+
+func TestQR(t *testing.T) {
+	q, r, err := QR(np.NpStack{{1, 2}, {3, 4}})
+	assert.NoError(t, err)
+	assertClose(t, []float64{-0.316227766016838, -0.9486832980505138, -0.9486832980505138, 0.316227766016838}, q, 1e-15)
+	assertClose(t, []float64{-3.1622776601683795, -4.427188724235731, 0, -0.6324555320336751}, r, 1e-15)
+
+	rs := np.NewRandomState(4)
+	for _, shape := range [][2]int{{6, 4}, {4, 6}, {5, 5}} {
+		a := nd(t, rs.RandN(shape[0]*shape[1]), shape[0], shape[1])
+		q, r, err := QR(a)
+		assert.NoError(t, err)
+		k := shape[0]
+		if shape[1] < k {
+			k = shape[1]
+		}
+		assert.Equal(t, []int{shape[0], k}, q.Shape())
+		assert.Equal(t, []int{k, shape[1]}, r.Shape())
+		assertOrthonormalColumns(t, q)
+		for i := 0; i < k; i++ {
+			for j := 0; j < i; j++ {
+				assert.Equal(t, 0.0, r.At(i, j))
+			}
+		}
+		back, _ := MatMul(q, r)
+		assertClose(t, a.Flatten(), back, 1e-13)
+	}
+}
+
+func TestCholesky(t *testing.T) {
+	l, err := Cholesky(np.NpStack{{4, 2}, {2, 3}})
+	assert.NoError(t, err)
+	assertClose(t, []float64{2, 0, 1, math.Sqrt2}, l, 1e-15)
+
+	// only the lower triangle is read
+	l, err = Cholesky(np.NpStack{{4, 100}, {2, 3}})
+	assert.NoError(t, err)
+	assertClose(t, []float64{2, 0, 1, math.Sqrt2}, l, 1e-15)
+
+	_, err = Cholesky(np.NpStack{{1, 2}, {2, 1}})
+	assert.ErrorIs(t, err, ErrNotPositiveDefinite)
+}
+
+func TestSVD(t *testing.T) {
+	rs := np.NewRandomState(5)
+	for _, shape := range [][2]int{{6, 4}, {4, 6}, {5, 5}, {1, 3}} {
+		a := nd(t, rs.RandN(shape[0]*shape[1]), shape[0], shape[1])
+		for _, full := range []bool{false, true} {
+			u, s, vt, err := SVD(a, full)
+			assert.NoError(t, err)
+			k := len(s)
+			for i := 1; i < k; i++ {
+				assert.GreaterOrEqual(t, s[i-1], s[i])
+			}
+			assertOrthonormalColumns(t, u)
+			assertOrthonormalColumns(t, vt.T())
+			if full {
+				assert.Equal(t, []int{shape[0], shape[0]}, u.Shape())
+				assert.Equal(t, []int{shape[1], shape[1]}, vt.Shape())
+			}
+			// a = u[:, :k] diag(s) vt[:k]
+			us, _ := u.Slice(np.All(), np.Range(0, k))
+			scaled := np.NDZeros(shape[0], k)
+			for i := 0; i < shape[0]; i++ {
+				for j := 0; j < k; j++ {
+					scaled.Set(us.At(i, j)*s[j], i, j)
+				}
+			}
+			vk, _ := vt.Slice(np.Range(0, k), np.All())
+			back, _ := MatMul(scaled, vk)
+			assertClose(t, a.Flatten(), back, 1e-13)
+		}
+	}
+
+	// a rank one matrix has one non-zero singular value
+	_, s, _, err := SVD(np.NpStack{{1, 2}, {2, 4}, {3, 6}}, true)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(70), s[0], 1e-14)
+	assert.InDelta(t, 0, s[1], 1e-15)
+}
+
+func TestEigh(t *testing.T) {
+	w, v, err := Eigh(np.NpStack{{2, 1}, {1, 2}})
+	assert.NoError(t, err)
+	assert.True(t, w.AlmostEqual(np.NpArray{1, 3}, 1e-15))
+	assert.InDelta(t, 1/math.Sqrt2, math.Abs(v.At(0, 0)), 1e-15)
+
+	rs := np.NewRandomState(6)
+	n := 7
+	x := nd(t, rs.RandN(n*n), n, n)
+	sym, _ := MatMul(x, x.T())
+	w, v, err = Eigh(sym)
+	assert.NoError(t, err)
+	for i := 1; i < n; i++ {
+		assert.LessOrEqual(t, w[i-1], w[i])
+	}
+	assertOrthonormalColumns(t, v)
+	av, _ := MatMul(sym, v)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			assert.InDelta(t, w[j]*v.At(i, j), av.At(i, j), 1e-12)
+		}
+	}
+}
+
+func TestLstsq(t *testing.T) {
+	// numpy's line fitting example
+	a := np.NpStack{{0, 1}, {1, 1}, {2, 1}, {3, 1}}
+	res, err := Lstsq(a, np.NpArray{-1, 0.2, 0.9, 2.1}, 0)
+	assert.NoError(t, err)
+	assertClose(t, []float64{1, -0.95}, res.X, 1e-14)
+	assert.Equal(t, 2, res.Rank)
+	assert.Len(t, res.S, 2)
+	assert.True(t, res.Residuals.AlmostEqual(np.NpArray{0.05}, 1e-14))
+
+	// rank deficient systems give the minimum norm solution and no residuals
+	res, err = Lstsq(np.NpStack{{1, 1}, {1, 1}}, np.NpStack{{2}, {2}}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Rank)
+	assert.Equal(t, []int{2, 1}, res.X.Shape())
+	assertClose(t, []float64{1, 1}, res.X, 1e-15)
+	assert.Len(t, res.Residuals, 0)
+}
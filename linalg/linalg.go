@@ -0,0 +1,380 @@
+// Package linalg provides the matrix routines of numpy.linalg. Matrices are
+// given as any value np.AsNDArray accepts, typically a 2-D NDArray or an
+// NpStack whose rows are the matrix rows, and results are NDArrays.
+package linalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	np "github.com/mdcfrancis/gonp"
+)
+
+var (
+	// ErrSingularMatrix is returned when a matrix that must be inverted has
+	// an exactly zero pivot.
+	ErrSingularMatrix = errors.New("singular matrix")
+	// ErrNotPositiveDefinite is returned by Cholesky.
+	ErrNotPositiveDefinite = errors.New("matrix is not positive definite")
+	// ErrNoConvergence is returned when an iterative decomposition fails to
+	// converge.
+	ErrNoConvergence = errors.New("did not converge")
+)
+
+const eps = 2.220446049250313e-16
+
+// matrix is a dense row-major matrix.
+type matrix struct {
+	rows, cols int
+	data       []float64
+}
+
+func newMatrix(rows, cols int) *matrix {
+	return &matrix{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+func identity(n int) *matrix {
+	m := newMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+func (m *matrix) at(i, j int) float64 {
+	return m.data[i*m.cols+j]
+}
+
+func (m *matrix) set(i, j int, v float64) {
+	m.data[i*m.cols+j] = v
+}
+
+func (m *matrix) copy() *matrix {
+	return &matrix{rows: m.rows, cols: m.cols, data: append([]float64(nil), m.data...)}
+}
+
+func (m *matrix) transpose() *matrix {
+	ret := newMatrix(m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			ret.set(j, i, m.at(i, j))
+		}
+	}
+	return ret
+}
+
+func (m *matrix) mul(b *matrix) *matrix {
+	ret := newMatrix(m.rows, b.cols)
+	for i := 0; i < m.rows; i++ {
+		for k := 0; k < m.cols; k++ {
+			v := m.at(i, k)
+			if v == 0 {
+				continue
+			}
+			for j := 0; j < b.cols; j++ {
+				ret.data[i*b.cols+j] += v * b.at(k, j)
+			}
+		}
+	}
+	return ret
+}
+
+func (m *matrix) nd() np.NDArray {
+	ret, err := np.NewNDArray(m.data, m.rows, m.cols)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// vector returns column j as a 1-D NDArray.
+func (m *matrix) vector(j int) np.NDArray {
+	data := make([]float64, m.rows)
+	for i := range data {
+		data[i] = m.at(i, j)
+	}
+	ret, _ := np.NewNDArray(data, len(data))
+	return ret
+}
+
+// asMatrix converts a to a 2-D matrix.
+func asMatrix(a interface{}) (*matrix, error) {
+	nd, err := np.AsNDArray(a)
+	if err != nil {
+		return nil, err
+	}
+	if nd.Ndim() != 2 {
+		return nil, fmt.Errorf("%d-dimensional array given. Array must be two-dimensional", nd.Ndim())
+	}
+	shape := nd.Shape()
+	return &matrix{rows: shape[0], cols: shape[1], data: nd.Flatten()}, nil
+}
+
+// asSquare converts a to a square matrix.
+func asSquare(a interface{}) (*matrix, error) {
+	m, err := asMatrix(a)
+	if err != nil {
+		return nil, err
+	}
+	if m.rows != m.cols {
+		return nil, fmt.Errorf("last 2 dimensions of the array must be square")
+	}
+	return m, nil
+}
+
+// asRHS converts the right hand side b of a system with n rows to a matrix,
+// reporting whether it was a vector.
+func asRHS(b interface{}, n int) (*matrix, bool, error) {
+	nd, err := np.AsNDArray(b)
+	if err != nil {
+		return nil, false, err
+	}
+	shape := nd.Shape()
+	var m *matrix
+	switch nd.Ndim() {
+	case 1:
+		m = &matrix{rows: shape[0], cols: 1, data: nd.Flatten()}
+	case 2:
+		m = &matrix{rows: shape[0], cols: shape[1], data: nd.Flatten()}
+	default:
+		return nil, false, fmt.Errorf("b must be one or two-dimensional, got %d dimensions", nd.Ndim())
+	}
+	if m.rows != n {
+		return nil, false, fmt.Errorf("b has %d rows, expected %d", m.rows, n)
+	}
+	return m, nd.Ndim() == 1, nil
+}
+
+// result returns m as a vector when the input was one.
+func result(m *matrix, vector bool) np.NDArray {
+	if vector {
+		return m.vector(0)
+	}
+	return m.nd()
+}
+
+// MatMul returns the matrix product of a and b, like numpy.matmul. A 1-D
+// operand is promoted to a matrix by prepending (for a) or appending (for b)
+// a unit axis which is removed afterwards. Operands with more than two
+// dimensions are stacks of matrices whose leading axes broadcast.
+func MatMul(a, b interface{}) (np.NDArray, error) {
+	x, err := np.AsNDArray(a)
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	y, err := np.AsNDArray(b)
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	if x.Ndim() == 0 || y.Ndim() == 0 {
+		return np.NDArray{}, fmt.Errorf("matmul: input operand does not have enough dimensions")
+	}
+	vecA, vecB := x.Ndim() == 1, y.Ndim() == 1
+	if vecA {
+		if x, err = x.ExpandDims(0); err != nil {
+			return np.NDArray{}, err
+		}
+	}
+	if vecB {
+		if y, err = y.ExpandDims(1); err != nil {
+			return np.NDArray{}, err
+		}
+	}
+	xs, ys := x.Shape(), y.Shape()
+	m, k := xs[len(xs)-2], xs[len(xs)-1]
+	k2, n := ys[len(ys)-2], ys[len(ys)-1]
+	if k != k2 {
+		return np.NDArray{}, fmt.Errorf("matmul: input operand 1 has a mismatch in its core dimension 0 (size %d is different from %d)", k2, k)
+	}
+	batch, err := np.BroadcastShapes(xs[:len(xs)-2], ys[:len(ys)-2])
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	if x, err = x.BroadcastTo(append(append([]int{}, batch...), m, k)...); err != nil {
+		return np.NDArray{}, err
+	}
+	if y, err = y.BroadcastTo(append(append([]int{}, batch...), k, n)...); err != nil {
+		return np.NDArray{}, err
+	}
+	count := 1
+	for _, d := range batch {
+		count *= d
+	}
+	// flattening the broadcast views gives contiguous matrices per batch
+	xd, yd := x.Flatten(), y.Flatten()
+	data := make([]float64, 0, count*m*n)
+	for i := 0; i < count; i++ {
+		xm := &matrix{rows: m, cols: k, data: xd[i*m*k : (i+1)*m*k]}
+		ym := &matrix{rows: k, cols: n, data: yd[i*k*n : (i+1)*k*n]}
+		data = append(data, xm.mul(ym).data...)
+	}
+	shape := append([]int{}, batch...)
+	if !vecA {
+		shape = append(shape, m)
+	}
+	if !vecB {
+		shape = append(shape, n)
+	}
+	return np.NewNDArray(data, shape...)
+}
+
+// Ord selects the norm computed by Norm. The zero value is numpy's default
+// ord=None, the 2-norm of vectors and Frobenius norm of matrices.
+type Ord struct {
+	kind ordKind
+	p    float64
+}
+
+type ordKind int
+
+const (
+	ordDefault ordKind = iota
+	ordFro
+	ordNuc
+	ordP
+)
+
+var (
+	// Fro is the Frobenius norm of a matrix, ord='fro'.
+	Fro = Ord{kind: ordFro}
+	// Nuc is the nuclear norm of a matrix, the sum of its singular values,
+	// ord='nuc'.
+	Nuc = Ord{kind: ordNuc}
+)
+
+// P is the numeric ord p, which may be ±Inf.
+func P(p float64) Ord {
+	return Ord{kind: ordP, p: p}
+}
+
+func (o Ord) String() string {
+	switch o.kind {
+	case ordDefault:
+		return "None"
+	case ordFro:
+		return "fro"
+	case ordNuc:
+		return "nuc"
+	}
+	return fmt.Sprint(o.p)
+}
+
+// Norm returns the vector or matrix norm of x selected by ord, like
+// numpy.linalg.norm.
+func Norm(x interface{}, ord Ord) (float64, error) {
+	a, err := np.AsNDArray(x)
+	if err != nil {
+		return 0, err
+	}
+	data := a.Flatten()
+	if ord.kind == ordDefault || (ord.kind == ordFro && a.Ndim() == 2) {
+		// numpy squares and sums the raveled array
+		sum := 0.0
+		for _, v := range data {
+			sum += v * v
+		}
+		return math.Sqrt(sum), nil
+	}
+	switch a.Ndim() {
+	case 1:
+		return vectorNorm(data, ord)
+	case 2:
+		m, _ := asMatrix(a)
+		return matrixNorm(m, ord)
+	}
+	return 0, fmt.Errorf("improper number of dimensions to norm")
+}
+
+func vectorNorm(x []float64, ord Ord) (float64, error) {
+	if ord.kind != ordP {
+		return 0, fmt.Errorf("invalid norm order '%v' for vectors", ord)
+	}
+	p := ord.p
+	ret := 0.0
+	switch {
+	case math.IsInf(p, 1):
+		for _, v := range x {
+			ret = math.Max(ret, math.Abs(v))
+		}
+	case math.IsInf(p, -1):
+		ret = math.Inf(1)
+		for _, v := range x {
+			ret = math.Min(ret, math.Abs(v))
+		}
+	case p == 0:
+		for _, v := range x {
+			if v != 0 {
+				ret++
+			}
+		}
+	case p == 1:
+		for _, v := range x {
+			ret += math.Abs(v)
+		}
+	case p == 2:
+		for _, v := range x {
+			ret += v * v
+		}
+		ret = math.Sqrt(ret)
+	default:
+		for _, v := range x {
+			ret += math.Pow(math.Abs(v), p)
+		}
+		ret = math.Pow(ret, 1/p)
+	}
+	return ret, nil
+}
+
+func matrixNorm(m *matrix, ord Ord) (float64, error) {
+	switch {
+	case ord.kind == ordNuc:
+		s, err := singularValues(m)
+		if err != nil {
+			return 0, err
+		}
+		sum := 0.0
+		for _, v := range s {
+			sum += v
+		}
+		return sum, nil
+	case ord.kind != ordP:
+	case ord.p == 2 || ord.p == -2:
+		s, err := singularValues(m)
+		if err != nil {
+			return 0, err
+		}
+		if len(s) == 0 {
+			return 0, nil
+		}
+		if ord.p == 2 {
+			return s[0], nil
+		}
+		return s[len(s)-1], nil
+	case ord.p == 1 || ord.p == -1:
+		return absSums(m.transpose(), ord.p > 0), nil
+	case math.IsInf(ord.p, 0):
+		return absSums(m, ord.p > 0), nil
+	}
+	return 0, fmt.Errorf("invalid norm order for matrices")
+}
+
+// absSums returns the largest, or smallest, sum of absolute values of a row.
+func absSums(m *matrix, largest bool) float64 {
+	ret := math.Inf(-1)
+	if !largest {
+		ret = math.Inf(1)
+	}
+	for i := 0; i < m.rows; i++ {
+		sum := 0.0
+		for j := 0; j < m.cols; j++ {
+			sum += math.Abs(m.at(i, j))
+		}
+		if largest {
+			ret = math.Max(ret, sum)
+		} else {
+			ret = math.Min(ret, sum)
+		}
+	}
+	return ret
+}
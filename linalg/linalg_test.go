@@ -0,0 +1,182 @@
+package linalg
+
+import (
+	np "github.com/mdcfrancis/gonp"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func nd(t *testing.T, data []float64, shape ...int) np.NDArray {
+	t.Helper()
+	ret, err := np.NewNDArray(data, shape...)
+	assert.NoError(t, err)
+	return ret
+}
+
+func assertClose(t *testing.T, want []float64, got np.NDArray, tol float64) {
+	t.Helper()
+	assert.True(t, np.NpArray(got.Flatten()).AlmostEqual(want, tol), "want %v got %v", want, got.Flatten())
+}
+
+func TestMatMul(t *testing.T) {
+	a := np.NpStack{{1, 0}, {0, 1}}
+	b := np.NpStack{{4, 1}, {2, 2}}
+	ret, err := MatMul(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, ret.Shape())
+	assert.Equal(t, []float64{4, 1, 2, 2}, ret.Flatten())
+
+	// 1-D operands lose their promoted axis
+	ret, err = MatMul(b, np.NpArray{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, ret.Shape())
+	assert.Equal(t, []float64{6, 6}, ret.Flatten())
+	ret, err = MatMul(np.NpArray{1, 2}, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{8, 5}, ret.Flatten())
+	ret, err = MatMul(np.NpArray{1, 2}, np.NpArray{3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ret.Ndim())
+	assert.Equal(t, []float64{11}, ret.Flatten())
+
+	// stacks broadcast over the leading axes
+	data := make([]float64, 8)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	stack := nd(t, data, 2, 2, 2)
+	ret, err = MatMul(stack, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 2}, ret.Shape())
+	assert.Equal(t, []float64{2, 2, 14, 8, 26, 14, 38, 20}, ret.Flatten())
+
+	_, err = MatMul(a, np.NpStack{{1, 2, 3}})
+	assert.Error(t, err)
+	_, err = MatMul(1.0, a)
+	assert.Error(t, err)
+}
+
+func TestSolveInvDet(t *testing.T) {
+	x, err := Solve(np.NpStack{{3, 1}, {1, 2}}, np.NpArray{9, 8})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, x.Shape())
+	assertClose(t, []float64{2, 3}, x, 1e-15)
+
+	x, err = Solve(np.NpStack{{3, 1}, {1, 2}}, np.NpStack{{9, 1}, {8, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, x.Shape())
+	assertClose(t, []float64{2, 0, 3, 1}, x, 1e-15)
+
+	a := np.NpStack{{1, 2}, {3, 4}}
+	inv, err := Inv(a)
+	assert.NoError(t, err)
+	assertClose(t, []float64{-2, 1, 1.5, -0.5}, inv, 1e-15)
+	det, err := Det(a)
+	assert.NoError(t, err)
+	assert.InDelta(t, -2, det, 1e-15)
+
+	// a random system solves back to its right hand side
+	r := np.NewRandomState(3)
+	m := nd(t, r.RandN(36), 6, 6)
+	b := r.RandN(6)
+	x, err = Solve(m, b)
+	assert.NoError(t, err)
+	back, _ := MatMul(m, x)
+	assertClose(t, b, back, 1e-12)
+	inv, err = Inv(m)
+	assert.NoError(t, err)
+	eye, _ := MatMul(m, inv)
+	assertClose(t, identity(6).data, eye, 1e-12)
+
+	_, err = Solve(a, np.NpArray{1, 2, 3})
+	assert.Error(t, err)
+	_, err = Inv(np.NpStack{{1, 2, 3}})
+	assert.Error(t, err)
+
+	empty, err := np.NewNDArray(nil, 0, 0)
+	assert.NoError(t, err)
+	x, err = Solve(empty, np.NpArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0}, x.Shape())
+	inv, err = Inv(empty)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 0}, inv.Shape())
+	rcond, err := RCond(empty)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, rcond)
+}
+
+func TestSingularAndRCond(t *testing.T) {
+	singular := np.NpStack{{1, 2}, {2, 4}}
+	_, err := Solve(singular, np.NpArray{1, 2})
+	assert.ErrorIs(t, err, ErrSingularMatrix)
+	_, err = Inv(singular)
+	assert.ErrorIs(t, err, ErrSingularMatrix)
+	det, err := Det(singular)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, det)
+
+	rcond, err := RCond(singular)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, rcond)
+
+	// a nearly singular matrix still has a solution, RCond tells how far to
+	// trust it
+	nearly := np.NpStack{{1, 1}, {1, 1 + 4e-16}}
+	x, err := Solve(nearly, np.NpArray{2, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, x.Shape())
+	_, err = Inv(nearly)
+	assert.NoError(t, err)
+	rcond, err = RCond(nearly)
+	assert.NoError(t, err)
+	assert.Less(t, rcond, eps)
+	rcond, err = RCond(np.NpStack{{1, 2}, {3, 4}})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1/21.0, rcond, 1e-15)
+	_, err = RCond(np.NpStack{{1, 2, 3}})
+	assert.Error(t, err)
+
+	f := factorLU(&matrix{rows: 2, cols: 2, data: []float64{1, 2, 3, 4}})
+	// ‖A‖₁ = 6 and ‖A⁻¹‖₁ = 3.5
+	assert.InDelta(t, 1/21.0, f.rcond(6), 1e-15)
+}
+
+func TestNorm(t *testing.T) {
+	a := np.NpArray{-4, -3, -2, -1, 0, 1, 2, 3, 4}
+	b := nd(t, a, 3, 3)
+	cases := []struct {
+		x    interface{}
+		ord  Ord
+		want float64
+	}{
+		{a, Ord{}, 7.745966692414834},
+		{b, Ord{}, 7.745966692414834},
+		{b, Fro, 7.745966692414834},
+		{a, P(math.Inf(1)), 4},
+		{b, P(math.Inf(1)), 9},
+		{a, P(math.Inf(-1)), 0},
+		{b, P(math.Inf(-1)), 2},
+		{a, P(1), 20},
+		{b, P(1), 7},
+		{a, P(-1), 0},
+		{b, P(-1), 6},
+		{a, P(2), 7.745966692414834},
+		{b, P(2), 7.3484692283495345},
+		{a, P(-2), 0},
+		{b, P(-2), 0},
+		{a, P(3), 5.848035476425731},
+		{a, P(0), 8},
+		{b, Nuc, 9.797958971132712},
+	}
+	for _, c := range cases {
+		got, err := Norm(c.x, c.ord)
+		assert.NoError(t, err)
+		assert.InDelta(t, c.want, got, 1e-14, "ord %v", c.ord)
+	}
+	_, err := Norm(a, Fro)
+	assert.Error(t, err)
+	_, err = Norm(b, P(3))
+	assert.Error(t, err)
+}
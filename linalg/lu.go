@@ -0,0 +1,228 @@
+package linalg
+
+import (
+	"fmt"
+	"math"
+
+	np "github.com/mdcfrancis/gonp"
+)
+
+// lu is the factorisation PA = LU with partial pivoting, L unit lower
+// triangular and U upper triangular, stored together as LAPACK's dgetrf does.
+type lu struct {
+	lu    *matrix
+	pivot []int
+	// sign is the parity of the row exchanges
+	sign float64
+	// singular is set when a pivot is exactly zero
+	singular bool
+}
+
+func factorLU(a *matrix) *lu {
+	n := a.rows
+	f := &lu{lu: a.copy(), pivot: make([]int, n), sign: 1}
+	m := f.lu
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(m.at(i, k)) > math.Abs(m.at(p, k)) {
+				p = i
+			}
+		}
+		f.pivot[k] = p
+		if p != k {
+			f.sign = -f.sign
+			for j := 0; j < n; j++ {
+				m.data[k*n+j], m.data[p*n+j] = m.data[p*n+j], m.data[k*n+j]
+			}
+		}
+		pivot := m.at(k, k)
+		if pivot == 0 {
+			f.singular = true
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			l := m.at(i, k) / pivot
+			m.set(i, k, l)
+			if l == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				m.data[i*n+j] -= l * m.at(k, j)
+			}
+		}
+	}
+	return f
+}
+
+// solve overwrites the columns of b with the solution of A x = b, or of
+// Aᵀ x = b when trans is set.
+func (f *lu) solve(b *matrix, trans bool) {
+	n := f.lu.rows
+	m := f.lu
+	for c := 0; c < b.cols; c++ {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = b.at(i, c)
+		}
+		if !trans {
+			for k, p := range f.pivot {
+				x[k], x[p] = x[p], x[k]
+			}
+			for i := 0; i < n; i++ {
+				for j := 0; j < i; j++ {
+					x[i] -= m.at(i, j) * x[j]
+				}
+			}
+			for i := n - 1; i >= 0; i-- {
+				for j := i + 1; j < n; j++ {
+					x[i] -= m.at(i, j) * x[j]
+				}
+				x[i] /= m.at(i, i)
+			}
+		} else {
+			// Uᵀ Lᵀ P x = b
+			for i := 0; i < n; i++ {
+				for j := 0; j < i; j++ {
+					x[i] -= m.at(j, i) * x[j]
+				}
+				x[i] /= m.at(i, i)
+			}
+			for i := n - 1; i >= 0; i-- {
+				for j := i + 1; j < n; j++ {
+					x[i] -= m.at(j, i) * x[j]
+				}
+			}
+			for k := n - 1; k >= 0; k-- {
+				p := f.pivot[k]
+				x[k], x[p] = x[p], x[k]
+			}
+		}
+		for i, v := range x {
+			b.set(i, c, v)
+		}
+	}
+}
+
+// rcond estimates the reciprocal 1-norm condition number of A from its
+// factorisation with Hager's method, as LAPACK's dgecon does.
+func (f *lu) rcond(anorm float64) float64 {
+	n := f.lu.rows
+	if anorm == 0 {
+		return 0
+	}
+	x := newMatrix(n, 1)
+	for i := range x.data {
+		x.data[i] = 1 / float64(n)
+	}
+	est := 0.0
+	for iter := 0; iter < 5; iter++ {
+		y := x.copy()
+		f.solve(y, false)
+		est = 0
+		for _, v := range y.data {
+			est += math.Abs(v)
+		}
+		z := y
+		for i, v := range z.data {
+			if v >= 0 {
+				z.data[i] = 1
+			} else {
+				z.data[i] = -1
+			}
+		}
+		f.solve(z, true)
+		jmax, zx := 0, 0.0
+		for i, v := range z.data {
+			if math.Abs(v) > math.Abs(z.data[jmax]) {
+				jmax = i
+			}
+			zx += v * x.data[i]
+		}
+		if math.Abs(z.data[jmax]) <= zx {
+			break
+		}
+		for i := range x.data {
+			x.data[i] = 0
+		}
+		x.data[jmax] = 1
+	}
+	return 1 / (anorm * est)
+}
+
+// Solve returns x solving a x = b for a square a, like numpy.linalg.solve.
+// b may be a vector or a matrix of right hand sides. An exactly singular a
+// returns ErrSingularMatrix. A nearly singular one still gives a solution,
+// check RCond when its accuracy matters.
+func Solve(a, b interface{}) (np.NDArray, error) {
+	m, err := asSquare(a)
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	rhs, vector, err := asRHS(b, m.rows)
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	if m.rows == 0 {
+		return result(rhs, vector), nil
+	}
+	f := factorLU(m)
+	if f.singular {
+		return np.NDArray{}, fmt.Errorf("solve: %w", ErrSingularMatrix)
+	}
+	f.solve(rhs, false)
+	return result(rhs, vector), nil
+}
+
+// Inv returns the inverse of a square matrix, like numpy.linalg.inv, with
+// the same errors as Solve.
+func Inv(a interface{}) (np.NDArray, error) {
+	m, err := asSquare(a)
+	if err != nil {
+		return np.NDArray{}, err
+	}
+	if m.rows == 0 {
+		return m.nd(), nil
+	}
+	f := factorLU(m)
+	if f.singular {
+		return np.NDArray{}, fmt.Errorf("inv: %w", ErrSingularMatrix)
+	}
+	inv := identity(m.rows)
+	f.solve(inv, false)
+	return inv.nd(), nil
+}
+
+// RCond estimates the reciprocal 1-norm condition number 1 / (‖a‖₁ ‖a⁻¹‖₁)
+// of a square matrix as LAPACK's dgecon does. scipy warns that a solution
+// may not be accurate when it is below machine precision. A singular matrix
+// gives zero and an empty one gives one.
+func RCond(a interface{}) (float64, error) {
+	m, err := asSquare(a)
+	if err != nil {
+		return 0, err
+	}
+	if m.rows == 0 {
+		return 1, nil
+	}
+	f := factorLU(m)
+	if f.singular {
+		return 0, nil
+	}
+	return f.rcond(absSums(m.transpose(), true)), nil
+}
+
+// Det returns the determinant of a square matrix from its LU factorisation,
+// like numpy.linalg.det. A singular matrix has determinant zero.
+func Det(a interface{}) (float64, error) {
+	m, err := asSquare(a)
+	if err != nil {
+		return 0, err
+	}
+	f := factorLU(m)
+	det := f.sign
+	for i := 0; i < m.rows; i++ {
+		det *= f.lu.at(i, i)
+	}
+	return det, nil
+}
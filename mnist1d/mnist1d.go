@@ -0,0 +1,282 @@
+// Package mnist1d generates the MNIST-1D dataset of Greydanus, following the
+// Python reference at github.com/greydanus/mnist1d. Ten hand drawn digit
+// templates are padded, dilated, scaled, translated, made noisy, sheared and
+// subsampled. With the same arguments and seed MakeDataset draws the same
+// random numbers in the same order as the reference, so it produces the
+// same x, y and t arrays.
+//
+// Translations are drawn with np.RandChoice and the resampling uses
+// NpArray.LinearInterpolate. Normal noise comes from RandomState.RandN
+// rather than np.RandN: np.RandN rounds its logarithms as Go's math.Log does
+// and keeps its own spare normal, so its draws would not be numpy's.
+package mnist1d
+
+import (
+	"fmt"
+
+	np "github.com/mdcfrancis/gonp"
+)
+
+// DatasetArgs are the parameters of the dataset, get_dataset_args in the
+// reference.
+type DatasetArgs struct {
+	NumSamples int
+	// TrainSplit is the fraction of the samples in the training set.
+	TrainSplit float64
+	// TemplateLen is the length of the digit templates.
+	TemplateLen int
+	// Padding is the range of the number of zeros appended to a template
+	// before it is dilated to TemplateLen + Padding[1] points.
+	Padding    [2]int
+	ScaleCoeff float64
+	// MaxTranslation bounds the circular shift of each sample.
+	MaxTranslation int
+	CorrNoiseScale float64
+	IIDNoiseScale  float64
+	ShearScale     float64
+	// ShuffleSeq permutes the points of every sample the same way.
+	ShuffleSeq bool
+	// FinalSeqLength is the number of points in each sample.
+	FinalSeqLength int
+	Seed           uint64
+	// URL is where the reference's pickled dataset can be downloaded.
+	URL string
+}
+
+// GetDatasetArgs returns the arguments of the published dataset.
+func GetDatasetArgs() DatasetArgs {
+	return DatasetArgs{
+		NumSamples:     5000,
+		TrainSplit:     0.8,
+		TemplateLen:    12,
+		Padding:        [2]int{36, 60},
+		ScaleCoeff:     0.4,
+		MaxTranslation: 48,
+		CorrNoiseScale: 0.25,
+		IIDNoiseScale:  2e-2,
+		ShearScale:     0.75,
+		ShuffleSeq:     false,
+		FinalSeqLength: 40,
+		Seed:           42,
+		URL:            "https://github.com/greydanus/mnist1d/raw/master/mnist1d_data.pkl",
+	}
+}
+
+// Templates are the digits the samples are made from.
+type Templates struct {
+	// X holds one whitened template per digit.
+	X np.NpStack
+	// T is the horizontal coordinate of the template points.
+	T np.NpArray
+	// Y is the label of each template.
+	Y np.NpArray
+}
+
+// GetTemplates returns the ten digit templates of the reference, each
+// whitened and shifted to start at zero.
+func GetTemplates() Templates {
+	x := np.NpStack{
+		{5, 6, 6.5, 6.75, 7, 7, 7, 7, 6.75, 6.5, 6, 5},
+		{5, 3, 3, 3.4, 3.8, 4.2, 4.6, 5, 5.4, 5.8, 5, 5},
+		{5, 6, 6.5, 6.5, 6, 5.25, 4.75, 4, 3.5, 3.5, 4, 5},
+		{5, 6, 6.5, 6.5, 6, 5, 5, 6, 6.5, 6.5, 6, 5},
+		{5, 4.4, 3.8, 3.2, 2.6, 2.6, 5, 5, 5, 5, 5, 5},
+		{5, 3, 3, 3, 3, 5, 6, 6.5, 6.5, 6, 4.5, 5},
+		{5, 4, 3.5, 3.25, 3, 3, 3, 3, 3.25, 3.5, 4, 5},
+		{5, 7, 7, 6.6, 6.2, 5.8, 5.4, 5, 4.6, 4.2, 5, 5},
+		{5, 4, 3.5, 3.5, 4, 5, 5, 4, 3.5, 3.5, 4, 5},
+		{5, 4, 3.5, 3.5, 4, 5, 5, 5, 5, 4.7, 4.3, 5},
+	}
+	mean, _ := x.MeanAxis(1, false)
+	for i, row := range x {
+		x[i] = row.AddFloat64(-mean.At(i))
+	}
+	std, _ := x.StdAxis(1, 0, false)
+	y := make(np.NpArray, len(x))
+	for i, row := range x {
+		row = row.DivFloat64(std.At(i))
+		x[i] = row.AddFloat64(-row[0]).DivFloat64(6)
+		y[i] = float64(i)
+	}
+	return Templates{
+		X: x,
+		T: np.LinSpace(-5, 5, len(x[0])).DivFloat64(6),
+		Y: y,
+	}
+}
+
+// Pad appends between padding[0] and padding[1] zeros to x.
+func Pad(rs *np.RandomState, x np.NpArray, padding [2]int) np.NpArray {
+	low, high := padding[0], padding[1]
+	p := low + int(rs.RandomSample()*float64(high-low+1))
+	return append(x.Copy(), np.Zeros(p)...)
+}
+
+// Shear adds a random slope of at most scale/2 in either direction to x.
+func Shear(rs *np.RandomState, x np.NpArray, scale float64) np.NpArray {
+	coeff := scale * (rs.RandomSample() - 0.5)
+	ramp := np.LinSpace(-0.5, 0.5, len(x))
+	ret := make(np.NpArray, len(x))
+	for i, v := range x {
+		ret[i] = v - coeff*ramp[i]
+	}
+	return ret
+}
+
+// Translate rotates x right by up to maxTranslation-1 places.
+func Translate(rs *np.RandomState, x np.NpArray, maxTranslation int) np.NpArray {
+	k := np.RandChoice(rs.RKState(), maxTranslation)
+	if k == 0 {
+		return x.Copy()
+	}
+	return append(x[len(x)-k:].Copy(), x[:len(x)-k]...)
+}
+
+// CorrNoiseLike returns normal noise with standard deviation scale, the
+// shape of x, smoothed by a Gaussian of width two.
+func CorrNoiseLike(rs *np.RandomState, x np.NpArray, scale float64) np.NpArray {
+	noise := rs.RandN(len(x)).MulFloat64(scale)
+	smooth, err := np.GaussianFilter1D(noise, 2, 0, nil)
+	if err != nil {
+		panic(err)
+	}
+	ret, err := smooth.ToNpArray()
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// IIDNoiseLike returns normal noise with standard deviation scale, the
+// shape of x.
+func IIDNoiseLike(rs *np.RandomState, x np.NpArray, scale float64) np.NpArray {
+	return rs.RandN(len(x)).MulFloat64(scale)
+}
+
+// Interpolate resamples x linearly at n evenly spaced points. The reference
+// uses scipy's interp1d, which for a float64 x inside its range defers to
+// numpy.interp, as LinearInterpolate does.
+func Interpolate(x np.NpArray, n int) np.NpArray {
+	return x.LinearInterpolate(np.LinSpace(0, 1, len(x)), np.LinSpace(0, 1, n))
+}
+
+// Transform makes one sample from the template x with coordinates t,
+// returning the sample and its coordinates.
+func Transform(rs *np.RandomState, x, t np.NpArray, args DatasetArgs) (np.NpArray, np.NpArray) {
+	const eps = 1e-8
+	length := args.TemplateLen + args.Padding[1]
+	newX := Interpolate(Pad(rs, x.AddFloat64(eps), args.Padding), length)
+	newT := Interpolate(t, length)
+	newX = newX.MulFloat64(1 + args.ScaleCoeff*(rs.RandomSample()-0.5))
+	newX = Translate(rs, newX, args.MaxTranslation)
+
+	// the noise only shows where the digit is not
+	noise := CorrNoiseLike(rs, newX, args.CorrNoiseScale)
+	for i, v := range newX {
+		if v == 0 {
+			newX[i] = noise[i]
+		}
+	}
+	newX = newX.Add(IIDNoiseLike(rs, newX, args.IIDNoiseScale))
+
+	newX = Shear(rs, newX, args.ShearScale)
+	return Interpolate(newX, args.FinalSeqLength), Interpolate(newT, args.FinalSeqLength)
+}
+
+// Dataset is a generated dataset split into training and test samples.
+type Dataset struct {
+	X, XTest np.NpStack
+	Y, YTest np.NpArray
+	// T is the horizontal coordinate of the sample points.
+	T         np.NpArray
+	Templates Templates
+}
+
+// MakeDataset generates a dataset like make_dataset in the reference. nil
+// args or templates mean GetDatasetArgs and GetTemplates. The samples are
+// shuffled and normalised to zero mean and unit standard deviation over the
+// whole dataset before they are split.
+func MakeDataset(args *DatasetArgs, templates *Templates) (Dataset, error) {
+	if args == nil {
+		defaults := GetDatasetArgs()
+		args = &defaults
+	}
+	if templates == nil {
+		defaults := GetTemplates()
+		templates = &defaults
+	}
+	if len(templates.X) != len(templates.Y) {
+		return Dataset{}, fmt.Errorf("%d templates but %d labels", len(templates.X), len(templates.Y))
+	}
+	perClass := 0
+	if len(templates.Y) > 0 {
+		perClass = args.NumSamples / len(templates.Y)
+	}
+	if perClass == 0 {
+		return Dataset{}, fmt.Errorf("num_samples %d is fewer than one per template", args.NumSamples)
+	}
+	if args.Padding[0] > args.Padding[1] {
+		return Dataset{}, fmt.Errorf("padding low %d is greater than high %d", args.Padding[0], args.Padding[1])
+	}
+	if args.MaxTranslation < 1 {
+		return Dataset{}, fmt.Errorf("max_translation must be positive")
+	}
+	if args.FinalSeqLength < 1 {
+		return Dataset{}, fmt.Errorf("final_seq_length must be positive")
+	}
+
+	rs := np.NewRandomState(args.Seed)
+	var xs np.NpStack
+	var ys np.NpArray
+	var newT np.NpArray
+	for label, template := range templates.X {
+		for i := 0; i < perClass; i++ {
+			var x np.NpArray
+			x, newT = Transform(rs, template, templates.T, *args)
+			xs = append(xs, x)
+			ys = append(ys, templates.Y[label])
+		}
+	}
+
+	order := rs.Permutation(len(ys))
+	shuffledX := make(np.NpStack, len(xs))
+	shuffledY := make(np.NpArray, len(ys))
+	for i, k := range order {
+		shuffledX[i], shuffledY[i] = xs[k], ys[k]
+	}
+	xs, ys = shuffledX, shuffledY
+	if args.ShuffleSeq {
+		seq := rs.Permutation(args.FinalSeqLength)
+		for i, x := range xs {
+			shuffled := make(np.NpArray, len(seq))
+			for j, k := range seq {
+				shuffled[j] = x[k]
+			}
+			xs[i] = shuffled
+		}
+	}
+
+	mean, err := xs.MeanAxis(np.AxisNone, false)
+	if err != nil {
+		return Dataset{}, err
+	}
+	std, err := xs.StdAxis(np.AxisNone, 0, false)
+	if err != nil {
+		return Dataset{}, err
+	}
+	m, s := mean.At(), std.At()
+	newT = newT.DivFloat64(s)
+	for i, x := range xs {
+		xs[i] = x.AddFloat64(-m).DivFloat64(s)
+	}
+
+	split := int(float64(len(ys)) * args.TrainSplit)
+	return Dataset{
+		X:         xs[:split],
+		XTest:     xs[split:],
+		Y:         ys[:split],
+		YTest:     ys[split:],
+		T:         newT,
+		Templates: *templates,
+	}, nil
+}
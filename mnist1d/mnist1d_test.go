@@ -0,0 +1,143 @@
+package mnist1d
+
+import (
+	np "github.com/mdcfrancis/gonp"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetTemplates(t *testing.T) {
+	tp := GetTemplates()
+	assert.Len(t, tp.X, 10)
+	assert.Equal(t, np.NpArray{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, tp.Y)
+	assert.Len(t, tp.T, 12)
+	assert.InDelta(t, -5.0/6, tp.T[0], 1e-15)
+	assert.InDelta(t, 5.0/6, tp.T[11], 1e-15)
+	for i, x := range tp.X {
+		assert.Len(t, x, 12)
+		// every template starts at zero and has standard deviation 1/6
+		assert.Equal(t, 0.0, x[0], "template %d", i)
+		std, _ := np.StdAxis(x, np.AxisNone, 0, false)
+		assert.InDelta(t, 1.0/6, std.At(), 1e-15, "template %d", i)
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	rs := np.NewRandomState(0)
+	x := np.NpArray{1, 2, 3}
+	padded := Pad(rs, x, [2]int{2, 4})
+	assert.GreaterOrEqual(t, len(padded), 5)
+	assert.LessOrEqual(t, len(padded), 7)
+	assert.Equal(t, x, padded[:3])
+	assert.Equal(t, np.NpArray{1, 2, 3}, x)
+
+	assert.Equal(t, x, Translate(rs, x, 1))
+	for i := 0; i < 20; i++ {
+		moved := Translate(rs, np.NpArray{1, 2, 3, 4}, 4)
+		k := 0
+		for moved[k] != 1 {
+			k++
+		}
+		assert.Equal(t, np.NpArray{1, 2, 3, 4}, append(moved[k:].Copy(), moved[:k]...))
+	}
+
+	// numpy.interp and scipy's interp1d give these exactly
+	assert.Equal(t, np.NpArray{0, 0.5, 1}, Interpolate(np.NpArray{0, 1}, 3))
+	assert.Equal(t, np.NpArray{0, 0.5, 1, 2.5, 4}, Interpolate(np.NpArray{0, 1, 4}, 5))
+	assert.Equal(t, np.NpArray{2, 0, -2, 2, 6, 3, 0, 0.5, 1}, Interpolate(np.NpArray{2, -2, 6, 0, 1}, 9))
+	assert.Equal(t, np.NpArray{0.1, 0.1}, Interpolate(np.NpArray{0.1, 0.1, 0.1}, 2))
+	sheared := Shear(rs, np.Zeros(5), 1)
+	assert.InDelta(t, 0, sheared[2], 1e-15)
+	assert.InDelta(t, -sheared[0], sheared[4], 1e-15)
+
+	noise := CorrNoiseLike(rs, np.Zeros(72), 0.25)
+	assert.Len(t, noise, 72)
+	assert.Len(t, IIDNoiseLike(rs, np.Zeros(7), 1), 7)
+}
+
+func TestTransform(t *testing.T) {
+	tp := GetTemplates()
+	args := GetDatasetArgs()
+	x, tt := Transform(np.NewRandomState(1), tp.X[3], tp.T, args)
+	assert.Len(t, x, 40)
+	assert.Len(t, tt, 40)
+	assert.InDelta(t, tp.T[0], tt[0], 1e-15)
+	assert.InDelta(t, tp.T[11], tt[39], 1e-15)
+	for _, v := range x {
+		assert.False(t, math.IsNaN(v))
+	}
+}
+
+func TestMakeDataset(t *testing.T) {
+	d, err := MakeDataset(nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, d.X, 4000)
+	assert.Len(t, d.XTest, 1000)
+	assert.Len(t, d.Y, 4000)
+	assert.Len(t, d.YTest, 1000)
+	assert.Len(t, d.T, 40)
+	assert.Len(t, d.X[0], 40)
+
+	// testdata/reference.py writes the reference's make_dataset with seed 42,
+	// computing each numpy and scipy call as their C code does
+	v, err := np.LoadPickle(filepath.Join("testdata", "mnist1d_seed42.pkl"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	ref := v.(map[interface{}]interface{})
+	assert.Equal(t, ref["y"], d.Y)
+	assert.Equal(t, ref["y_test"], d.YTest)
+	assert.Equal(t, ref["t"], d.T)
+	templates := ref["templates"].(map[interface{}]interface{})
+	assert.Equal(t, templates["x"], d.Templates.X)
+	assert.Equal(t, templates["t"], d.Templates.T)
+	assert.Equal(t, ref["x"], d.X)
+	assert.Equal(t, ref["x_test"], d.XTest)
+
+	// the whole dataset is normalised
+	all := append(append(np.NpStack{}, d.X...), d.XTest...)
+	mean, _ := all.MeanAxis(np.AxisNone, false)
+	std, _ := all.StdAxis(np.AxisNone, 0, false)
+	assert.InDelta(t, 0, mean.At(), 1e-12)
+	assert.InDelta(t, 1, std.At(), 1e-12)
+
+	again, err := MakeDataset(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, d.X, again.X)
+}
+
+func TestMakeDatasetShuffleSeq(t *testing.T) {
+	args := GetDatasetArgs()
+	args.NumSamples = 200
+	plain, err := MakeDataset(&args, nil)
+	assert.NoError(t, err)
+	args.ShuffleSeq = true
+	shuffled, err := MakeDataset(&args, nil)
+	assert.NoError(t, err)
+	assert.Len(t, shuffled.X, 160)
+	assert.Len(t, shuffled.XTest, 40)
+	assert.Equal(t, plain.Y, shuffled.Y)
+	assert.NotEqual(t, plain.X[0], shuffled.X[0])
+	assert.InDelta(t, plain.X[0].Sum(), shuffled.X[0].Sum(), 1e-12)
+}
+
+func TestMakeDatasetErrors(t *testing.T) {
+	args := GetDatasetArgs()
+	args.NumSamples = 9
+	_, err := MakeDataset(&args, nil)
+	assert.Error(t, err)
+
+	args = GetDatasetArgs()
+	args.Padding = [2]int{5, 4}
+	_, err = MakeDataset(&args, nil)
+	assert.Error(t, err)
+
+	tp := GetTemplates()
+	tp.Y = tp.Y[:3]
+	_, err = MakeDataset(nil, &tp)
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+"""Writes mnist1d_seed42.pkl, make_dataset() of github.com/greydanus/mnist1d
+with its default arguments, for mnist1d_test.go.
+
+numpy and scipy are not needed. The script follows the reference's
+data.py and transform.py line by line and computes each numpy and scipy
+call the way their C code does: MT19937 seeded as RandomState(42) with the
+legacy polar gauss, numpy's pairwise summation for sums, means and standard
+deviations, numpy.interp (which interp1d calls for 1-D float64 data),
+numpy.linspace, and the symmetric loop of scipy.ndimage's correlate1d with
+mode reflect. math.log and math.exp are the C library's, as in numpy. The
+dataset is pickled in the reference's layout with the stand-in ndarray of
+../../testdata/pickles.py. The fixture was written with Python 3.11.7 on
+glibc 2.36 (Debian 2.36-9+deb12u13), x86-64 with FMA.
+"""
+import math
+import os
+import pickle
+import random
+import struct
+import sys
+
+sys.path.insert(0, os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "testdata"))
+from pickles import ndarray  # noqa: E402
+
+
+class RandomState:
+    def __init__(self, seed):
+        key = [seed & 0xFFFFFFFF]
+        for i in range(1, 624):
+            key.append((1812433253 * (key[-1] ^ (key[-1] >> 30)) + i) & 0xFFFFFFFF)
+        self.mt = random.Random()
+        self.mt.setstate((3, tuple(key) + (624,), None))
+        self.gauss_next = None
+
+    def rand(self):
+        return self.mt.random()
+
+    def randn(self, n):
+        return [self.gauss() for _ in range(n)]
+
+    def gauss(self):
+        if self.gauss_next is not None:
+            g, self.gauss_next = self.gauss_next, None
+            return g
+        while True:
+            x1 = 2.0 * self.rand() - 1.0
+            x2 = 2.0 * self.rand() - 1.0
+            r2 = x1 * x1 + x2 * x2
+            if r2 < 1.0 and r2 != 0.0:
+                break
+        f = math.sqrt(-2.0 * math.log(r2) / r2)
+        self.gauss_next = f * x1
+        return f * x2
+
+    def interval(self, mx):
+        # random_interval with 32-bit draws, as choice(n) and permutation use
+        if mx == 0:
+            return 0
+        mask = mx
+        for s in (1, 2, 4, 8, 16):
+            mask |= mask >> s
+        while True:
+            v = self.mt.getrandbits(32) & mask
+            if v <= mx:
+                return v
+
+    def choice(self, n):
+        return self.interval(n - 1)
+
+    def permutation(self, n):
+        a = list(range(n))
+        for i in range(n - 1, 0, -1):
+            j = self.interval(i)
+            a[i], a[j] = a[j], a[i]
+        return a
+
+
+def pairwise(a):
+    # numpy's pairwise_sum for a contiguous float64 run
+    n = len(a)
+    if n < 8:
+        res = 0.0
+        for v in a:
+            res += v
+        return res
+    if n <= 128:
+        r = list(a[:8])
+        i = 8
+        while i < n - n % 8:
+            for j in range(8):
+                r[j] += a[i + j]
+            i += 8
+        res = ((r[0] + r[1]) + (r[2] + r[3])) + ((r[4] + r[5]) + (r[6] + r[7]))
+        while i < n:
+            res += a[i]
+            i += 1
+        return res
+    n2 = n // 2
+    n2 -= n2 % 8
+    return pairwise(a[:n2]) + pairwise(a[n2:])
+
+
+def mean(a):
+    return pairwise(a) / len(a)
+
+
+def std(a):
+    m = mean(a)
+    return math.sqrt(pairwise([(v - m) * (v - m) for v in a]) / len(a))
+
+
+def linspace(start, stop, num):
+    step = (stop - start) / (num - 1)
+    y = [i * step + start for i in range(num)]
+    y[-1] = stop
+    return y
+
+
+def interp(x, xp, fp):
+    n = len(xp)
+    slopes = [(fp[i + 1] - fp[i]) / (xp[i + 1] - xp[i]) for i in range(n - 1)]
+    out = []
+    for v in x:
+        if v < xp[0]:
+            out.append(fp[0])
+            continue
+        if v > xp[-1]:
+            out.append(fp[-1])
+            continue
+        j = max(i for i in range(n) if xp[i] <= v)
+        if j == n - 1 or xp[j] == v:
+            out.append(fp[j])
+        else:
+            out.append(slopes[j] * (v - xp[j]) + fp[j])
+    return out
+
+
+def gaussian_filter(a, sigma, truncate=4.0):
+    radius = int(truncate * sigma + 0.5)
+    s2 = sigma * sigma
+    phi = [math.exp(-0.5 / s2 * (x * x)) for x in range(-radius, radius + 1)]
+    total = pairwise(phi)
+    w = [p / total for p in phi][::-1]
+    n = len(a)
+    # mode reflect: d c b a | a b c d | d c b a
+    line = [a[radius - 1 - i] for i in range(radius)] + list(a) + [a[n - 1 - i] for i in range(radius)]
+    out = []
+    for k in range(n):
+        c = k + radius
+        acc = line[c] * w[radius]
+        for j in range(-radius, 0):
+            acc += (line[c + j] + line[c - j]) * w[radius + j]
+        out.append(acc)
+    return out
+
+
+# transform.py
+
+def pad(rs, x, padding):
+    low, high = padding
+    p = low + int(rs.rand() * (high - low + 1))
+    return list(x) + [0.0] * p
+
+
+def shear(rs, x, scale=10):
+    coeff = scale * (rs.rand() - 0.5)
+    return [v - coeff * s for v, s in zip(x, linspace(-0.5, 0.5, len(x)))]
+
+
+def translate(rs, x, max_translation):
+    k = rs.choice(max_translation)
+    return x[len(x) - k:] + x[:len(x) - k] if k else list(x)
+
+
+def corr_noise_like(rs, x, scale):
+    noise = [scale * v for v in rs.randn(len(x))]
+    return gaussian_filter(noise, 2)
+
+
+def iid_noise_like(rs, x, scale):
+    return [scale * v for v in rs.randn(len(x))]
+
+
+def interpolate(x, n):
+    return interp(linspace(0, 1, n), linspace(0, 1, len(x)), x)
+
+
+def transform(rs, x, y, args, eps=1e-8):
+    new_x = pad(rs, [v + eps for v in x], args["padding"])
+    new_x = interpolate(new_x, args["template_len"] + args["padding"][-1])
+    new_y = interpolate(y, args["template_len"] + args["padding"][-1])
+    scale = 1 + args["scale_coeff"] * (rs.rand() - 0.5)
+    new_x = [v * scale for v in new_x]
+    new_x = translate(rs, new_x, args["max_translation"])
+
+    corr = corr_noise_like(rs, new_x, args["corr_noise_scale"])
+    new_x = [v if v != 0 else c for v, c in zip(new_x, corr)]
+    iid = iid_noise_like(rs, new_x, args["iid_noise_scale"])
+    new_x = [v + n for v, n in zip(new_x, iid)]
+
+    new_x = shear(rs, new_x, args["shear_scale"])
+    return interpolate(new_x, args["final_seq_length"]), interpolate(new_y, args["final_seq_length"])
+
+
+# data.py
+
+def get_dataset_args():
+    return {"num_samples": 5000, "train_split": 0.8, "template_len": 12,
+            "padding": [36, 60], "scale_coeff": .4, "max_translation": 48,
+            "corr_noise_scale": 0.25, "iid_noise_scale": 2e-2, "shear_scale": 0.75,
+            "shuffle_seq": False, "final_seq_length": 40, "seed": 42}
+
+
+def get_templates():
+    ds = [[5, 6, 6.5, 6.75, 7, 7, 7, 7, 6.75, 6.5, 6, 5],
+          [5, 3, 3, 3.4, 3.8, 4.2, 4.6, 5, 5.4, 5.8, 5, 5],
+          [5, 6, 6.5, 6.5, 6, 5.25, 4.75, 4, 3.5, 3.5, 4, 5],
+          [5, 6, 6.5, 6.5, 6, 5, 5, 6, 6.5, 6.5, 6, 5],
+          [5, 4.4, 3.8, 3.2, 2.6, 2.6, 5, 5, 5, 5, 5, 5],
+          [5, 3, 3, 3, 3, 5, 6, 6.5, 6.5, 6, 4.5, 5],
+          [5, 4, 3.5, 3.25, 3, 3, 3, 3, 3.25, 3.5, 4, 5],
+          [5, 7, 7, 6.6, 6.2, 5.8, 5.4, 5, 4.6, 4.2, 5, 5],
+          [5, 4, 3.5, 3.5, 4, 5, 5, 4, 3.5, 3.5, 4, 5],
+          [5, 4, 3.5, 3.5, 4, 5, 5, 5, 5, 4.7, 4.3, 5]]
+    xs = []
+    for d in ds:
+        d = [float(v) for v in d]
+        m = mean(d)
+        x = [v - m for v in d]
+        s = std(x)
+        x = [v / s for v in x]
+        x = [v - x[0] for v in x]
+        xs.append([v / 6. for v in x])
+    return {"x": xs, "t": [v / 6. for v in linspace(-5, 5, 12)], "y": list(range(10))}
+
+
+def make_dataset():
+    templates = get_templates()
+    args = get_dataset_args()
+    rs = RandomState(args["seed"])
+
+    xs, ys = [], []
+    per_class = args["num_samples"] // len(templates["y"])
+    for label in range(len(templates["y"])):
+        for _ in range(per_class):
+            x, new_t = transform(rs, templates["x"][label], templates["t"], args)
+            xs.append(x)
+            ys.append(templates["y"][label])
+
+    order = rs.permutation(len(ys))
+    xs = [xs[k] for k in order]
+    ys = [ys[k] for k in order]
+
+    flat = [v for x in xs for v in x]
+    s = std(flat)
+    m = mean(flat)
+    new_t = [v / s for v in new_t]
+    xs = [[(v - m) / s for v in x] for x in xs]
+
+    split = int(len(ys) * args["train_split"])
+    return {"x": xs[:split], "x_test": xs[split:], "y": ys[:split], "y_test": ys[split:],
+            "t": new_t, "templates": templates}
+
+
+def f8(rows):
+    flat = [v for r in rows for v in r] if rows and isinstance(rows[0], list) else rows
+    shape = (len(rows), len(rows[0])) if rows and isinstance(rows[0], list) else (len(rows),)
+    return ndarray(shape, "<f8", struct.pack("<%dd" % len(flat), *flat))
+
+
+def i8(values):
+    return ndarray((len(values),), "<i8", struct.pack("<%dq" % len(values), *values))
+
+
+if __name__ == "__main__":
+    d = make_dataset()
+    tp = d["templates"]
+    data = {"x": f8(d["x"]), "x_test": f8(d["x_test"]), "y": i8(d["y"]), "y_test": i8(d["y_test"]),
+            "t": f8(d["t"]),
+            "templates": {"x": f8(tp["x"]), "t": f8(tp["t"]), "y": i8(tp["y"])}}
+    with open(os.path.join(os.path.dirname(os.path.abspath(__file__)), "mnist1d_seed42.pkl"), "wb") as f:
+        pickle.dump(data, f, protocol=4)
@@ -0,0 +1,378 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// withoutNaN returns the values of line that are not NaN.
+func withoutNaN(line NpArray) NpArray {
+	ret := make(NpArray, 0, len(line))
+	for _, v := range line {
+		if !math.IsNaN(v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// replaceNaN returns line with NaN replaced by v and the number of values
+// that were not NaN.
+func replaceNaN(line NpArray, v float64) (NpArray, int) {
+	ret := make(NpArray, len(line))
+	count := 0
+	for i, x := range line {
+		if math.IsNaN(x) {
+			ret[i] = v
+		} else {
+			ret[i] = x
+			count++
+		}
+	}
+	return ret, count
+}
+
+// NanSum sums a along axis treating NaN as zero, like numpy.nansum.
+func NanSum(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, r.nanSum)
+}
+
+func (r reduction) nanSum(line NpArray) float64 {
+	zeroed, _ := replaceNaN(line, 0)
+	return r.sum(zeroed)
+}
+
+// NanProd multiplies a along axis treating NaN as one, like numpy.nanprod.
+func NanProd(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		prod := 1.0
+		for _, v := range line {
+			if !math.IsNaN(v) {
+				prod *= v
+			}
+		}
+		return prod
+	})
+}
+
+// NanMean averages the values of a along axis that are not NaN, like
+// numpy.nanmean. An all NaN slice gives NaN.
+func NanMean(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, r.nanMean)
+}
+
+func (r reduction) nanMean(line NpArray) float64 {
+	zeroed, count := replaceNaN(line, 0)
+	return r.sum(zeroed) / float64(count)
+}
+
+// NanVar returns the variance of the values along axis that are not NaN,
+// with divisor count - ddof, like numpy.nanvar. Slices with no more than
+// ddof values give NaN.
+func NanVar(a interface{}, axis, ddof int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return r.nanVariance(line, ddof)
+	})
+}
+
+// NanStd returns the standard deviation of the values along axis that are
+// not NaN, like numpy.nanstd.
+func NanStd(a interface{}, axis, ddof int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return math.Sqrt(r.nanVariance(line, ddof))
+	})
+}
+
+// nanVariance follows numpy's _nanvar, where the NaN positions contribute
+// zero to the sum of squared deviations.
+func (r reduction) nanVariance(line NpArray, ddof int) float64 {
+	zeroed, count := replaceNaN(line, 0)
+	mean := r.sum(zeroed) / float64(count)
+	for i, v := range line {
+		if math.IsNaN(v) {
+			zeroed[i] = 0
+			continue
+		}
+		d := v - mean
+		zeroed[i] = d * d
+	}
+	dof := count - ddof
+	if dof <= 0 {
+		return math.NaN()
+	}
+	return r.sum(zeroed) / float64(dof)
+}
+
+// NanMin returns the minimum along axis ignoring NaN, like numpy.nanmin. An
+// all NaN slice gives NaN.
+func NanMin(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	return nanExtremum(a, axis, keepdims, "minimum", func(x, y float64) bool { return x < y })
+}
+
+// NanMax returns the maximum along axis ignoring NaN, like numpy.nanmax.
+func NanMax(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	return nanExtremum(a, axis, keepdims, "maximum", func(x, y float64) bool { return x > y })
+}
+
+func nanExtremum(a interface{}, axis int, keepdims bool, name string, better func(x, y float64) bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if r.length() == 0 {
+		return NDArray{}, fmt.Errorf("zero-size array to reduction operation %s which has no identity", name)
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		i := nanArgExtremum(line, better)
+		if i < 0 {
+			return math.NaN()
+		}
+		return line[i]
+	})
+}
+
+// nanArgExtremum returns the index of the first best value that is not NaN,
+// or -1 if they all are.
+func nanArgExtremum(line NpArray, better func(x, y float64) bool) int {
+	best := -1
+	for i, v := range line {
+		if math.IsNaN(v) {
+			continue
+		}
+		if best < 0 || better(v, line[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// NanArgMin returns the indices of the minima along axis ignoring NaN, like
+// numpy.nanargmin. An all NaN slice is an error.
+func NanArgMin(a interface{}, axis int, keepdims bool) (Array[int64], error) {
+	return nanArgExtremumAxis(a, axis, keepdims, func(x, y float64) bool { return x < y })
+}
+
+// NanArgMax returns the indices of the maxima along axis ignoring NaN, like
+// numpy.nanargmax.
+func NanArgMax(a interface{}, axis int, keepdims bool) (Array[int64], error) {
+	return nanArgExtremumAxis(a, axis, keepdims, func(x, y float64) bool { return x > y })
+}
+
+func nanArgExtremumAxis(a interface{}, axis int, keepdims bool, better func(x, y float64) bool) (Array[int64], error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return Array[int64]{}, err
+	}
+	data := make([]int64, 0)
+	allNaN := false
+	r.lines(func(line NpArray) {
+		i := nanArgExtremum(line, better)
+		if i < 0 {
+			allNaN = true
+		}
+		data = append(data, int64(i))
+	})
+	if allNaN {
+		return Array[int64]{}, fmt.Errorf("all-NaN slice encountered")
+	}
+	return NewArray(data, r.shape(keepdims)...)
+}
+
+// NanMedian returns the median along axis ignoring NaN, like
+// numpy.nanmedian. An all NaN slice gives NaN.
+func NanMedian(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		sorted := withoutNaN(line)
+		sort.Float64s(sorted)
+		return medianSorted(sorted)
+	})
+}
+
+// NanQuantile returns the q-th quantile, q in [0, 1], along axis ignoring
+// NaN, like numpy.nanquantile. An all NaN slice gives NaN.
+func NanQuantile(a interface{}, q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	if err := checkQuantile(q, method); err != nil {
+		return NDArray{}, err
+	}
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		sorted := withoutNaN(line)
+		sort.Float64s(sorted)
+		return quantileSorted(sorted, q, method)
+	})
+}
+
+// NanPercentile returns the q-th percentile, q in [0, 100], along axis
+// ignoring NaN, like numpy.nanpercentile.
+func NanPercentile(a interface{}, q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	if !(q >= 0 && q <= 100) {
+		return NDArray{}, fmt.Errorf("percentiles must be in the range [0, 100]")
+	}
+	return NanQuantile(a, q/100, axis, method, keepdims)
+}
+
+// IsNaN returns a mask of the NaN elements of a, like numpy.isnan.
+func IsNaN(a interface{}) (Array[bool], error) {
+	return mask(a, math.IsNaN)
+}
+
+// IsInf returns a mask of the infinite elements of a, like numpy.isinf.
+func IsInf(a interface{}) (Array[bool], error) {
+	return mask(a, func(v float64) bool { return math.IsInf(v, 0) })
+}
+
+// IsFinite returns a mask of the elements of a that are neither NaN nor
+// infinite, like numpy.isfinite.
+func IsFinite(a interface{}) (Array[bool], error) {
+	return mask(a, func(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) })
+}
+
+func mask(a interface{}, f func(float64) bool) (Array[bool], error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return Array[bool]{}, err
+	}
+	data := nd.Flatten()
+	ret := make([]bool, len(data))
+	for i, v := range data {
+		ret[i] = f(v)
+	}
+	return NewArray(ret, nd.shape...)
+}
+
+// NanToNumOptions hold the replacements made by NanToNum. The zero value
+// matches numpy: NaN becomes 0 and infinities the largest finite values.
+type NanToNumOptions struct {
+	Nan float64
+	// PosInf and NegInf replace +Inf and -Inf, ±math.MaxFloat64 when nil.
+	PosInf, NegInf *float64
+}
+
+// NanToNum returns a copy of a with NaN and infinities replaced by finite
+// numbers, like numpy.nan_to_num.
+func NanToNum(a interface{}, opts *NanToNumOptions) (NDArray, error) {
+	if opts == nil {
+		opts = &NanToNumOptions{}
+	}
+	posInf, negInf := math.MaxFloat64, -math.MaxFloat64
+	if opts.PosInf != nil {
+		posInf = *opts.PosInf
+	}
+	if opts.NegInf != nil {
+		negInf = *opts.NegInf
+	}
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	data := nd.Flatten()
+	for i, v := range data {
+		switch {
+		case math.IsNaN(v):
+			data[i] = opts.Nan
+		case math.IsInf(v, 1):
+			data[i] = posInf
+		case math.IsInf(v, -1):
+			data[i] = negInf
+		}
+	}
+	return NewNDArray(data, nd.shape...)
+}
+
+// NanSum returns the sum of the values that are not NaN.
+func (a NpArray) NanSum() float64 {
+	return reduction{a: a.ToNDArray()}.nanSum(a)
+}
+
+// NanMean returns the mean of the values that are not NaN, NaN if there are
+// none.
+func (a NpArray) NanMean() float64 {
+	return reduction{a: a.ToNDArray()}.nanMean(a)
+}
+
+// NanStd returns the standard deviation of the values that are not NaN with
+// divisor count - ddof.
+func (a NpArray) NanStd(ddof int) float64 {
+	return math.Sqrt(reduction{a: a.ToNDArray()}.nanVariance(a, ddof))
+}
+
+// NanMin returns the smallest value that is not NaN, NaN if there are none.
+func (a NpArray) NanMin() float64 {
+	if i := nanArgExtremum(a, func(x, y float64) bool { return x < y }); i >= 0 {
+		return a[i]
+	}
+	return math.NaN()
+}
+
+// NanMax returns the largest value that is not NaN, NaN if there are none.
+func (a NpArray) NanMax() float64 {
+	if i := nanArgExtremum(a, func(x, y float64) bool { return x > y }); i >= 0 {
+		return a[i]
+	}
+	return math.NaN()
+}
+
+// NanSum sums the stack along axis treating NaN as zero.
+func (m NpStack) NanSum(axis int, keepdims bool) (NDArray, error) {
+	return NanSum(m, axis, keepdims)
+}
+
+// NanMean averages the stack along axis ignoring NaN.
+func (m NpStack) NanMean(axis int, keepdims bool) (NDArray, error) {
+	return NanMean(m, axis, keepdims)
+}
+
+// NanVar returns the variance of the stack along axis ignoring NaN.
+func (m NpStack) NanVar(axis, ddof int, keepdims bool) (NDArray, error) {
+	return NanVar(m, axis, ddof, keepdims)
+}
+
+// NanStd returns the standard deviation of the stack along axis ignoring
+// NaN.
+func (m NpStack) NanStd(axis, ddof int, keepdims bool) (NDArray, error) {
+	return NanStd(m, axis, ddof, keepdims)
+}
+
+// NanMin returns the minimum of the stack along axis ignoring NaN.
+func (m NpStack) NanMin(axis int, keepdims bool) (NDArray, error) {
+	return NanMin(m, axis, keepdims)
+}
+
+// NanMax returns the maximum of the stack along axis ignoring NaN.
+func (m NpStack) NanMax(axis int, keepdims bool) (NDArray, error) {
+	return NanMax(m, axis, keepdims)
+}
+
+// NanPercentile returns the q-th percentile of the stack along axis ignoring
+// NaN.
+func (m NpStack) NanPercentile(q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	return NanPercentile(m, q, axis, method, keepdims)
+}
@@ -0,0 +1,140 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+var nan = math.NaN()
+
+func TestNanMeanStdVar(t *testing.T) {
+	m := NpStack{{1, nan}, {3, 4}}
+	ret, err := NanMean(m, AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2.6666666666666665}, ret.Flatten())
+	ret, err = m.NanMean(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 4}, ret.Flatten())
+	ret, err = m.NanMean(1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, ret.Shape())
+	assert.Equal(t, []float64{1, 3.5}, ret.Flatten())
+
+	ret, err = NanVar(m, AxisNone, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.5555555555555554}, ret.Flatten())
+	ret, err = NanStd(m, AxisNone, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.247219128924647}, ret.Flatten())
+	ret, err = m.NanStd(0, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 0}, ret.Flatten())
+	ret, err = m.NanStd(1, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0.5}, ret.Flatten())
+
+	// one value left is not enough for ddof=1
+	ret, err = m.NanVar(0, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, ret.Flatten()[0])
+	assert.True(t, math.IsNaN(ret.Flatten()[1]))
+
+	a := NpArray{1, nan, 3, 4}
+	assert.Equal(t, 8.0, a.NanSum())
+	assert.Equal(t, 2.6666666666666665, a.NanMean())
+	assert.Equal(t, 1.247219128924647, a.NanStd(0))
+	assert.True(t, math.IsNaN(NpArray{nan, nan}.NanMean()))
+}
+
+func TestNanSumProdMinMax(t *testing.T) {
+	m := NpStack{{1, nan, 3}, {nan, nan, 2}}
+	ret, err := m.NanSum(1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4, 2}, ret.Flatten())
+	ret, err = m.NanSum(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 5}, ret.Flatten())
+	ret, err = NanProd(m, AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6}, ret.Flatten())
+
+	ret, err = m.NanMin(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, ret.Flatten()[0])
+	assert.True(t, math.IsNaN(ret.Flatten()[1]))
+	assert.Equal(t, 2.0, ret.Flatten()[2])
+	ret, err = m.NanMax(AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3}, ret.Flatten())
+	_, err = NanMin(NpArray{}, 0, false)
+	assert.Error(t, err)
+
+	assert.Equal(t, 1.0, NpArray{nan, 1, 3}.NanMin())
+	assert.Equal(t, 3.0, NpArray{nan, 1, 3}.NanMax())
+	assert.True(t, math.IsNaN(NpArray{nan}.NanMax()))
+
+	idx, err := NanArgMax(m, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 2}, idx.Flatten())
+	idx, err = NanArgMin(NpArray{nan, 4, 2, 2}, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, idx.Flatten())
+	_, err = NanArgMin(m, 0, false)
+	assert.Error(t, err)
+}
+
+func TestNanPercentileMedian(t *testing.T) {
+	m := NpStack{{10, nan, 4}, {3, 2, 1}}
+	ret, err := m.NanPercentile(50, AxisNone, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3}, ret.Flatten())
+	ret, err = m.NanPercentile(50, 0, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6.5, 2, 2.5}, ret.Flatten())
+	ret, err = m.NanPercentile(50, 1, QuantileLinear, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, ret.Shape())
+	assert.Equal(t, []float64{7, 2}, ret.Flatten())
+
+	ret, err = NanMedian(m, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6.5, 2, 2.5}, ret.Flatten())
+
+	ret, err = NanPercentile(NpArray{1, nan, 3, 4}, 25, 0, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2}, ret.Flatten())
+	ret, err = NanQuantile(NpArray{nan, nan}, 0.5, 0, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret.Flatten()[0]))
+
+	_, err = NanPercentile(m, 101, 0, QuantileLinear, false)
+	assert.Error(t, err)
+	_, err = NanQuantile(m, -0.1, 0, QuantileLinear, false)
+	assert.Error(t, err)
+}
+
+func TestMasksAndNanToNum(t *testing.T) {
+	inf := math.Inf(1)
+	a := NpArray{-inf, inf, nan, -128, 128}
+	m, err := IsNaN(a)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, false, true, false, false}, m.Flatten())
+	m, err = IsInf(a)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, false, false, false}, m.Flatten())
+	m, err = IsFinite(NpStack{{1, nan}, {inf, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, m.Shape())
+	assert.Equal(t, []bool{true, false, false, true}, m.Flatten())
+
+	ret, err := NanToNum(a, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{-math.MaxFloat64, math.MaxFloat64, 0, -128, 128}, ret.Flatten())
+	big := 33333333.0
+	ret, err = NanToNum(a, &NanToNumOptions{Nan: -9999, PosInf: &big, NegInf: &big})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{33333333, 33333333, -9999, -128, 128}, ret.Flatten())
+	// the input is not modified
+	assert.True(t, math.IsNaN(a[2]))
+}
@@ -0,0 +1,452 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Array is an n-dimensional array stored in a flat buffer and addressed
+// through a shape, per-axis strides (in elements) and an offset, like a numpy
+// ndarray. Reshape, Transpose and Slice return views that share the buffer.
+type Array[T Element] struct {
+	data    []T
+	shape   []int
+	strides []int
+	offset  int
+}
+
+// NDArray is the float64 array most of the package works with.
+type NDArray = Array[float64]
+
+// NewArray wraps data in an Array of the given shape without copying it.
+func NewArray[T Element](data []T, shape ...int) (Array[T], error) {
+	size, err := shapeSize(shape)
+	if err != nil {
+		return Array[T]{}, err
+	}
+	if size != len(data) {
+		return Array[T]{}, fmt.Errorf("cannot reshape array of size %d into shape %v", len(data), shape)
+	}
+	return Array[T]{data: data, shape: copyInts(shape), strides: cStrides(shape)}, nil
+}
+
+// NewNDArray wraps data in an NDArray of the given shape without copying it.
+func NewNDArray(data []float64, shape ...int) (NDArray, error) {
+	return NewArray(data, shape...)
+}
+
+// ZerosOf returns a zero filled Array of the given shape.
+func ZerosOf[T Element](shape ...int) Array[T] {
+	size, err := shapeSize(shape)
+	if err != nil {
+		panic(err)
+	}
+	return Array[T]{data: make([]T, size), shape: copyInts(shape), strides: cStrides(shape)}
+}
+
+// NDZeros returns a zero filled NDArray of the given shape.
+func NDZeros(shape ...int) NDArray {
+	return ZerosOf[float64](shape...)
+}
+
+func (a NpArray) ToNDArray() NDArray {
+	return NDArray{data: a, shape: []int{len(a)}, strides: []int{1}}
+}
+
+// ToNDArray copies a rectangular stack into a 2-D NDArray.
+func (m NpStack) ToNDArray() (NDArray, error) {
+	cols := 0
+	if len(m) > 0 {
+		cols = len(m[0])
+	}
+	data := make([]float64, 0, len(m)*cols)
+	for i, a := range m {
+		if len(a) != cols {
+			return NDArray{}, fmt.Errorf("row %d has length %d, expected %d", i, len(a), cols)
+		}
+		data = append(data, a...)
+	}
+	return NDArray{data: data, shape: []int{len(m), cols}, strides: []int{cols, 1}}, nil
+}
+
+// ToNpArray returns the elements of a 1-D array converted to float64.
+func (a Array[T]) ToNpArray() (NpArray, error) {
+	if len(a.shape) != 1 {
+		return nil, fmt.Errorf("expected a 1-D array, got shape %v", a.shape)
+	}
+	ret, _ := AsType[float64](a, CastUnsafe)
+	return ret.data, nil
+}
+
+// ToNpStack returns the rows of a 2-D array converted to float64.
+func (a Array[T]) ToNpStack() (NpStack, error) {
+	if len(a.shape) != 2 {
+		return nil, fmt.Errorf("expected a 2-D array, got shape %v", a.shape)
+	}
+	ret := make(NpStack, a.shape[0])
+	for i := range ret {
+		row := make(NpArray, a.shape[1])
+		for j := range row {
+			row[j] = castValue[float64](a.data[a.offset+i*a.strides[0]+j*a.strides[1]])
+		}
+		ret[i] = row
+	}
+	return ret, nil
+}
+
+func (a Array[T]) Shape() []int {
+	return copyInts(a.shape)
+}
+
+func (a Array[T]) Strides() []int {
+	return copyInts(a.strides)
+}
+
+func (a Array[T]) Ndim() int {
+	return len(a.shape)
+}
+
+func (a Array[T]) Size() int {
+	size := 1
+	for _, s := range a.shape {
+		size *= s
+	}
+	return size
+}
+
+func (a Array[T]) At(idx ...int) T {
+	return a.data[a.index(idx)]
+}
+
+func (a Array[T]) Set(v T, idx ...int) {
+	a.data[a.index(idx)] = v
+}
+
+func (a Array[T]) index(idx []int) int {
+	if len(idx) != len(a.shape) {
+		panic(fmt.Errorf("expected %d indices, got %d", len(a.shape), len(idx)))
+	}
+	pos := a.offset
+	for i, v := range idx {
+		if v < 0 {
+			v += a.shape[i]
+		}
+		if v < 0 || v >= a.shape[i] {
+			panic(&IndexError{Index: idx[i], Axis: i, Size: a.shape[i]})
+		}
+		pos += v * a.strides[i]
+	}
+	return pos
+}
+
+// IsContiguous reports whether the elements are laid out in C order with no gaps.
+func (a Array[T]) IsContiguous() bool {
+	expected := 1
+	for i := len(a.shape) - 1; i >= 0; i-- {
+		if a.shape[i] != 1 && a.strides[i] != expected {
+			return false
+		}
+		expected *= a.shape[i]
+	}
+	return true
+}
+
+// Copy returns a C-contiguous copy that does not share memory with a.
+func (a Array[T]) Copy() Array[T] {
+	return Array[T]{data: a.Flatten(), shape: copyInts(a.shape), strides: cStrides(a.shape)}
+}
+
+// Flatten returns a copy of the elements in C order.
+func (a Array[T]) Flatten() []T {
+	ret := make([]T, 0, a.Size())
+	a.each(func(pos int) {
+		ret = append(ret, a.data[pos])
+	})
+	return ret
+}
+
+// each calls f with the buffer position of every element in C order.
+func (a Array[T]) each(f func(pos int)) {
+	if a.Size() == 0 {
+		return
+	}
+	idx := make([]int, len(a.shape))
+	pos := a.offset
+	for {
+		f(pos)
+		axis := len(a.shape) - 1
+		for ; axis >= 0; axis-- {
+			idx[axis]++
+			pos += a.strides[axis]
+			if idx[axis] < a.shape[axis] {
+				break
+			}
+			pos -= idx[axis] * a.strides[axis]
+			idx[axis] = 0
+		}
+		if axis < 0 {
+			return
+		}
+	}
+}
+
+// Reshape returns a view with the new shape, one dimension may be -1 and is
+// inferred. Arrays that are not contiguous are copied first, as numpy does.
+func (a Array[T]) Reshape(shape ...int) (Array[T], error) {
+	shape = copyInts(shape)
+	size := a.Size()
+	known, unknown := 1, -1
+	for i, s := range shape {
+		switch {
+		case s == -1 && unknown < 0:
+			unknown = i
+		case s < 0:
+			return Array[T]{}, fmt.Errorf("invalid shape %v", shape)
+		default:
+			known *= s
+		}
+	}
+	if unknown >= 0 {
+		if known == 0 || size%known != 0 {
+			return Array[T]{}, fmt.Errorf("cannot reshape array of size %d into shape %v", size, shape)
+		}
+		shape[unknown] = size / known
+		known = size
+	}
+	if known != size {
+		return Array[T]{}, fmt.Errorf("cannot reshape array of size %d into shape %v", size, shape)
+	}
+	if !a.IsContiguous() {
+		a = a.Copy()
+	}
+	return Array[T]{data: a.data, shape: shape, strides: cStrides(shape), offset: a.offset}, nil
+}
+
+// Transpose returns a view with the axes permuted, reversing them when no
+// permutation is given.
+func (a Array[T]) Transpose(axes ...int) (Array[T], error) {
+	n := len(a.shape)
+	if len(axes) == 0 {
+		axes = make([]int, n)
+		for i := range axes {
+			axes[i] = n - 1 - i
+		}
+	}
+	if len(axes) != n {
+		return Array[T]{}, fmt.Errorf("axes %v don't match array of %d dimensions", axes, n)
+	}
+	seen := make([]bool, n)
+	shape := make([]int, n)
+	strides := make([]int, n)
+	for i, ax := range axes {
+		if ax < 0 {
+			ax += n
+		}
+		if ax < 0 || ax >= n || seen[ax] {
+			return Array[T]{}, fmt.Errorf("invalid axes %v for array of %d dimensions", axes, n)
+		}
+		seen[ax] = true
+		shape[i] = a.shape[ax]
+		strides[i] = a.strides[ax]
+	}
+	return Array[T]{data: a.data, shape: shape, strides: strides, offset: a.offset}, nil
+}
+
+// T returns the transposed view of a.
+func (a Array[T]) T() Array[T] {
+	ret, _ := a.Transpose()
+	return ret
+}
+
+// ExpandDims returns a view with a new axis of length one inserted at axis,
+// for example turning a row of length n into a column of shape (n, 1).
+func (a Array[T]) ExpandDims(axis int) (Array[T], error) {
+	n := len(a.shape) + 1
+	if axis < 0 {
+		axis += n
+	}
+	if axis < 0 || axis >= n {
+		return Array[T]{}, fmt.Errorf("axis %d is out of bounds for array of dimension %d", axis, n)
+	}
+	shape := append(append(copyInts(a.shape[:axis]), 1), a.shape[axis:]...)
+	strides := append(append(copyInts(a.strides[:axis]), 0), a.strides[axis:]...)
+	return Array[T]{data: a.data, shape: shape, strides: strides, offset: a.offset}, nil
+}
+
+// Span selects the elements of one axis, see All, Range, RangeStep, Reversed
+// and Index.
+type Span struct {
+	start, stop, step int
+	hasStart, hasStop bool
+	index             bool
+}
+
+// All selects a whole axis, like ":".
+func All() Span {
+	return Span{step: 1}
+}
+
+// Range selects start:stop, negative values count from the end.
+func Range(start, stop int) Span {
+	return Span{start: start, stop: stop, step: 1, hasStart: true, hasStop: true}
+}
+
+// RangeStep selects start:stop:step.
+func RangeStep(start, stop, step int) Span {
+	return Span{start: start, stop: stop, step: step, hasStart: true, hasStop: true}
+}
+
+// Reversed selects a whole axis backwards, like "::-1".
+func Reversed() Span {
+	return Span{step: -1}
+}
+
+// Index selects a single element and removes the axis.
+func Index(i int) Span {
+	return Span{start: i, index: true}
+}
+
+// bounds follows python's slice.indices.
+func (s Span) bounds(n int) (start, count, step int, err error) {
+	step = s.step
+	if step == 0 {
+		return 0, 0, 0, fmt.Errorf("slice step cannot be zero")
+	}
+	clamp := func(v, lo, hi int) int {
+		if v < 0 {
+			v += n
+		}
+		if v < lo {
+			return lo
+		}
+		if v > hi {
+			return hi
+		}
+		return v
+	}
+	var stop int
+	if step > 0 {
+		start, stop = 0, n
+		if s.hasStart {
+			start = clamp(s.start, 0, n)
+		}
+		if s.hasStop {
+			stop = clamp(s.stop, 0, n)
+		}
+		if stop > start {
+			count = (stop - start + step - 1) / step
+		}
+	} else {
+		start, stop = n-1, -1
+		if s.hasStart {
+			start = clamp(s.start, -1, n-1)
+		}
+		if s.hasStop {
+			stop = clamp(s.stop, -1, n-1)
+		}
+		if start > stop {
+			count = (start - stop - step - 1) / -step
+		}
+	}
+	return start, count, step, nil
+}
+
+// Slice returns a view selecting spans along the leading axes, the remaining
+// axes are kept whole.
+func (a Array[T]) Slice(spans ...Span) (Array[T], error) {
+	if len(spans) > len(a.shape) {
+		return Array[T]{}, fmt.Errorf("too many indices for array: array is %d-dimensional, but %d were indexed", len(a.shape), len(spans))
+	}
+	ret := Array[T]{data: a.data, offset: a.offset}
+	for axis, n := range a.shape {
+		if axis >= len(spans) {
+			ret.shape = append(ret.shape, n)
+			ret.strides = append(ret.strides, a.strides[axis])
+			continue
+		}
+		s := spans[axis]
+		if s.index {
+			i := s.start
+			if i < 0 {
+				i += n
+			}
+			if i < 0 || i >= n {
+				return Array[T]{}, &IndexError{Index: s.start, Axis: axis, Size: n}
+			}
+			ret.offset += i * a.strides[axis]
+			continue
+		}
+		start, count, step, err := s.bounds(n)
+		if err != nil {
+			return Array[T]{}, err
+		}
+		if count > 0 {
+			ret.offset += start * a.strides[axis]
+		}
+		ret.shape = append(ret.shape, count)
+		ret.strides = append(ret.strides, step*a.strides[axis])
+	}
+	if ret.shape == nil {
+		ret.shape, ret.strides = []int{}, []int{}
+	}
+	return ret, nil
+}
+
+func (a Array[T]) String() string {
+	// format like numpy with nested brackets, floats use the NpArray element
+	// format for example [[1.00000000 2.00000000 ]
+	//  [3.00000000 4.00000000 ]]
+	if len(a.shape) == 0 {
+		return formatElement(a.data[a.offset])
+	}
+	var buf strings.Builder
+	var format func(offset, axis int)
+	format = func(offset, axis int) {
+		buf.WriteString("[")
+		for i := 0; i < a.shape[axis]; i++ {
+			pos := offset + i*a.strides[axis]
+			if axis == len(a.shape)-1 {
+				buf.WriteString(formatElement(a.data[pos]) + " ")
+				continue
+			}
+			if i > 0 {
+				buf.WriteString("\n" + strings.Repeat(" ", axis+1))
+			}
+			format(pos, axis+1)
+		}
+		buf.WriteString("]")
+	}
+	format(a.offset, 0)
+	return buf.String()
+}
+
+func shapeSize(shape []int) (int, error) {
+	size := 1
+	for _, s := range shape {
+		if s < 0 {
+			return 0, fmt.Errorf("negative dimensions are not allowed: %v", shape)
+		}
+		if s > 0 && size > math.MaxInt/s {
+			return 0, fmt.Errorf("array is too big: %v", shape)
+		}
+		size *= s
+	}
+	return size, nil
+}
+
+func cStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return strides
+}
+
+func copyInts(v []int) []int {
+	ret := make([]int, len(v))
+	copy(ret, v)
+	return ret
+}
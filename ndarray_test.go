@@ -0,0 +1,155 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewNDArray(t *testing.T) {
+	a, err := NewNDArray([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, a.Shape())
+	assert.Equal(t, []int{3, 1}, a.Strides())
+	assert.Equal(t, 2, a.Ndim())
+	assert.Equal(t, 6, a.Size())
+	assert.Equal(t, 6.0, a.At(1, 2))
+	assert.Equal(t, 4.0, a.At(-1, 0))
+}
+
+func TestNewNDArrayErrorsOnSizeMismatch(t *testing.T) {
+	_, err := NewNDArray([]float64{1, 2, 3}, 2, 2)
+	assert.Error(t, err)
+}
+
+func TestNDZeros(t *testing.T) {
+	a := NDZeros(2, 2, 2)
+	assert.Equal(t, []float64{0, 0, 0, 0, 0, 0, 0, 0}, a.Flatten())
+}
+
+func TestNDArray_Set(t *testing.T) {
+	a := NDZeros(2, 2)
+	a.Set(5, 1, 0)
+	assert.Equal(t, []float64{0, 0, 5, 0}, a.Flatten())
+}
+
+func TestNDArray_ReshapeIsView(t *testing.T) {
+	a, _ := NewNDArray([]float64{1, 2, 3, 4, 5, 6}, 6)
+	b, err := a.Reshape(2, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, b.Shape())
+	b.Set(10, 0, 0)
+	assert.Equal(t, 10.0, a.At(0))
+}
+
+func TestNDArray_ReshapeErrors(t *testing.T) {
+	a := NDZeros(6)
+	_, err := a.Reshape(4, -1)
+	assert.Error(t, err)
+	_, err = a.Reshape(-1, -1)
+	assert.Error(t, err)
+}
+
+func TestNDArray_Transpose(t *testing.T) {
+	a, _ := NewNDArray([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	b := a.T()
+	assert.Equal(t, []int{3, 2}, b.Shape())
+	assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, b.Flatten())
+	assert.False(t, b.IsContiguous())
+	b.Set(0, 2, 1)
+	assert.Equal(t, 0.0, a.At(1, 2))
+}
+
+func TestNDArray_TransposeAxes(t *testing.T) {
+	a, _ := NewNDArray(Arrange(0, 24, 1), 2, 3, 4)
+	b, err := a.Transpose(1, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2, 4}, b.Shape())
+	assert.Equal(t, a.At(1, 2, 3), b.At(2, 1, 3))
+	_, err = a.Transpose(0, 0, 1)
+	assert.Error(t, err)
+}
+
+func TestNDArray_ReshapeCopiesNonContiguous(t *testing.T) {
+	a, _ := NewNDArray([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	b, err := a.T().Reshape(6)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, b.Flatten())
+	b.Set(0, 0)
+	assert.Equal(t, 1.0, a.At(0, 0))
+}
+
+func TestNDArray_Slice(t *testing.T) {
+	a, _ := NewNDArray(Arrange(0, 12, 1), 3, 4)
+	b, err := a.Slice(Range(1, 3), RangeStep(0, 4, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, b.Shape())
+	assert.Equal(t, []float64{4, 6, 8, 10}, b.Flatten())
+	b.Set(-1, 0, 0)
+	assert.Equal(t, -1.0, a.At(1, 0))
+}
+
+func TestNDArray_SliceIndexAndReversed(t *testing.T) {
+	a, _ := NewNDArray(Arrange(0, 12, 1), 3, 4)
+	row, err := a.Slice(Index(-1))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4}, row.Shape())
+	assert.Equal(t, []float64{8, 9, 10, 11}, row.Flatten())
+
+	rev, err := a.Slice(All(), Reversed())
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 2, 1, 0}, rev.Flatten()[:4])
+
+	empty, err := a.Slice(Range(2, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 4}, empty.Shape())
+	assert.Equal(t, []float64{}, empty.Flatten())
+}
+
+func TestNDArray_SliceErrors(t *testing.T) {
+	a := NDZeros(3, 4)
+	_, err := a.Slice(Index(3))
+	assert.Error(t, err)
+	_, err = a.Slice(All(), All(), All())
+	assert.Error(t, err)
+	_, err = a.Slice(RangeStep(0, 3, 0))
+	assert.Error(t, err)
+}
+
+func TestNDArray_Conversions(t *testing.T) {
+	stack := NpStack{
+		NpArray{1, 2, 3},
+		NpArray{4, 5, 6},
+	}
+	a, err := stack.ToNDArray()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, a.Shape())
+
+	back, err := a.ToNpStack()
+	assert.NoError(t, err)
+	assert.Equal(t, stack, back)
+
+	cols, err := a.T().ToNpStack()
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{1, 4}, {2, 5}, {3, 6}}, cols)
+
+	_, err = a.ToNpArray()
+	assert.Error(t, err)
+
+	arr, err := NpArray{1, 2, 3}.ToNDArray().ToNpArray()
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 2, 3}, arr)
+}
+
+func TestNpStack_ToNDArrayErrorsOnRaggedStack(t *testing.T) {
+	stack := NpStack{
+		NpArray{1, 2, 3},
+		NpArray{4, 5},
+	}
+	_, err := stack.ToNDArray()
+	assert.Error(t, err)
+}
+
+func TestNDArray_String(t *testing.T) {
+	a, _ := NewNDArray([]float64{1, 2, 3, 4}, 2, 2)
+	assert.Equal(t, "[[1.00000000 2.00000000 ]\n [3.00000000 4.00000000 ]]", a.String())
+}
@@ -0,0 +1,520 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BoundaryMode selects how filters extend the input past its edges, the
+// mode argument of scipy.ndimage. For the input a b c d:
+//
+//	ModeReflect   d c b a | a b c d | d c b a
+//	ModeConstant  k k k k | a b c d | k k k k
+//	ModeNearest   a a a a | a b c d | d d d d
+//	ModeMirror      d c b | a b c d | c b a
+//	ModeWrap      a b c d | a b c d | a b c d
+type BoundaryMode int
+
+const (
+	ModeReflect BoundaryMode = iota
+	ModeConstant
+	ModeNearest
+	ModeMirror
+	ModeWrap
+)
+
+func (m BoundaryMode) String() string {
+	switch m {
+	case ModeReflect:
+		return "reflect"
+	case ModeConstant:
+		return "constant"
+	case ModeNearest:
+		return "nearest"
+	case ModeMirror:
+		return "mirror"
+	case ModeWrap:
+		return "wrap"
+	}
+	return fmt.Sprintf("BoundaryMode(%d)", int(m))
+}
+
+// FilterOptions holds the optional arguments shared by the filters. The zero
+// value matches scipy's defaults.
+type FilterOptions struct {
+	Mode BoundaryMode
+	// CVal is the value past the edges for ModeConstant.
+	CVal float64
+	// Order is the derivative order of the Gaussian filters.
+	Order int
+	// Truncate is the Gaussian kernel radius in standard deviations, 4 when
+	// zero.
+	Truncate float64
+}
+
+// extendIndex maps a position outside [0, n) back into the line, returning
+// -1 for ModeConstant.
+func extendIndex(i, n int, mode BoundaryMode) int {
+	if i >= 0 && i < n {
+		return i
+	}
+	switch mode {
+	case ModeConstant:
+		return -1
+	case ModeNearest:
+		if i < 0 {
+			return 0
+		}
+		return n - 1
+	case ModeWrap:
+		i %= n
+		if i < 0 {
+			i += n
+		}
+		return i
+	case ModeMirror:
+		if n == 1 {
+			return 0
+		}
+		period := 2*n - 2
+		i %= period
+		if i < 0 {
+			i += period
+		}
+		if i >= n {
+			i = period - i
+		}
+		return i
+	}
+	// reflect
+	period := 2 * n
+	i %= period
+	if i < 0 {
+		i += period
+	}
+	if i >= n {
+		i = period - 1 - i
+	}
+	return i
+}
+
+// extendLine returns the line padded with before and after extended values.
+func extendLine(line NpArray, before, after int, opts *FilterOptions) NpArray {
+	n := len(line)
+	ret := make(NpArray, n+before+after)
+	for i := range ret {
+		if j := extendIndex(i-before, n, opts.Mode); j >= 0 {
+			ret[i] = line[j]
+		} else {
+			ret[i] = opts.CVal
+		}
+	}
+	return ret
+}
+
+// alongAxis applies f to every 1-D line of a along axis and returns the
+// results as a new C-contiguous array of the same shape.
+func alongAxis(a NDArray, axis int, f func(line NpArray) NpArray) (NDArray, error) {
+	n := a.Ndim()
+	axis, err := normalizeAxis(axis, n)
+	if err != nil {
+		return NDArray{}, err
+	}
+	ret := NDZeros(a.shape...)
+	length := a.shape[axis]
+	if length == 0 || a.Size() == 0 {
+		return ret, nil
+	}
+	// walk every position with the index along axis fixed at zero
+	outer := a.shape[:axis:axis]
+	outer = append(outer, 1)
+	outer = append(outer, a.shape[axis+1:]...)
+	idx := make([]int, n)
+	line := make(NpArray, length)
+	for {
+		pos := a.offset
+		out := 0
+		for i, v := range idx {
+			pos += v * a.strides[i]
+			out += v * ret.strides[i]
+		}
+		for k := range line {
+			line[k] = a.data[pos+k*a.strides[axis]]
+		}
+		for k, v := range f(line) {
+			ret.data[out+k*ret.strides[axis]] = v
+		}
+		i := n - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < outer[i] {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return ret, nil
+		}
+	}
+}
+
+// dblEpsilon is C's DBL_EPSILON, scipy's tolerance for symmetric weights.
+const dblEpsilon = 0x1p-52
+
+// correlateLine correlates line with weights centred on each element,
+// shifted left by origin, as scipy.ndimage.correlate1d. Like scipy it folds
+// odd length symmetric and antisymmetric weights about their centre, which
+// fixes the order of the sums.
+func correlateLine(line, weights NpArray, origin int, opts *FilterOptions) NpArray {
+	size1 := len(weights) / 2
+	size2 := len(weights) - size1 - 1
+	ext := extendLine(line, size1+origin, size2-origin, opts)
+	symmetric := 0
+	if len(weights)%2 == 1 {
+		symmetric = 1
+		for i := 1; i <= size1; i++ {
+			if math.Abs(weights[size1+i]-weights[size1-i]) > dblEpsilon {
+				symmetric = 0
+				break
+			}
+		}
+		if symmetric == 0 {
+			symmetric = -1
+			for i := 1; i <= size1; i++ {
+				if math.Abs(weights[size1+i]+weights[size1-i]) > dblEpsilon {
+					symmetric = 0
+					break
+				}
+			}
+		}
+	}
+	ret := make(NpArray, len(line))
+	for i := range ret {
+		c := i + size1
+		switch symmetric {
+		case 1:
+			sum := ext[c] * weights[size1]
+			for j := -size1; j < 0; j++ {
+				sum += (ext[c+j] + ext[c-j]) * weights[size1+j]
+			}
+			ret[i] = sum
+		case -1:
+			sum := ext[c] * weights[size1]
+			for j := -size1; j < 0; j++ {
+				sum += (ext[c+j] - ext[c-j]) * weights[size1+j]
+			}
+			ret[i] = sum
+		default:
+			sum := ext[c+size2] * weights[size1+size2]
+			for j := -size1; j < size2; j++ {
+				sum += ext[c+j] * weights[size1+j]
+			}
+			ret[i] = sum
+		}
+	}
+	return ret
+}
+
+func filterOptions(opts *FilterOptions) (*FilterOptions, error) {
+	if opts == nil {
+		return &FilterOptions{}, nil
+	}
+	if opts.Mode < ModeReflect || opts.Mode > ModeWrap {
+		return nil, fmt.Errorf("boundary mode not supported: %v", opts.Mode)
+	}
+	return opts, nil
+}
+
+// Correlate1D correlates input with weights along axis, see
+// scipy.ndimage.correlate1d. input may be any type accepted by AsNDArray.
+func Correlate1D(input interface{}, weights NpArray, axis int, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	if len(weights) == 0 {
+		return NDArray{}, fmt.Errorf("no filter weights given")
+	}
+	return alongAxis(a, axis, func(line NpArray) NpArray {
+		return correlateLine(line, weights, 0, opts)
+	})
+}
+
+// Convolve1D convolves input with weights along axis, see
+// scipy.ndimage.convolve1d.
+func Convolve1D(input interface{}, weights NpArray, axis int, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	if len(weights) == 0 {
+		return NDArray{}, fmt.Errorf("no filter weights given")
+	}
+	reversed := make(NpArray, len(weights))
+	for i, w := range weights {
+		reversed[len(weights)-1-i] = w
+	}
+	// an even kernel is centred one place further right once reversed
+	origin := 0
+	if len(weights)%2 == 0 {
+		origin = -1
+	}
+	return alongAxis(a, axis, func(line NpArray) NpArray {
+		return correlateLine(line, reversed, origin, opts)
+	})
+}
+
+// gaussianKernel returns the normalised Gaussian, or its order-th
+// derivative, sampled at -radius..radius.
+func gaussianKernel(sigma float64, order, radius int) NpArray {
+	sigma2 := sigma * sigma
+	phi := make(NpArray, 2*radius+1)
+	// the C library's exp, which numpy.exp uses for float64 unless it has
+	// an AVX-512 loop of its own
+	for i := range phi {
+		x := float64(i - radius)
+		phi[i] = expLibm(-0.5 / sigma2 * (x * x))
+	}
+	sum := pairwiseSum(phi)
+	for i := range phi {
+		phi[i] /= sum
+	}
+	if order == 0 {
+		return phi
+	}
+	// the derivative is q(x) * phi(x) for a polynomial q, with
+	// q' = dq/dx - x q / sigma^2 applied order times
+	q := make(NpArray, order+1)
+	q[0] = 1
+	for k := 0; k < order; k++ {
+		next := make(NpArray, order+1)
+		for i := 0; i < order; i++ {
+			next[i] = float64(i+1) * q[i+1]
+		}
+		for i := 1; i <= order; i++ {
+			next[i] += -q[i-1] / sigma2
+		}
+		q = next
+	}
+	for i := range phi {
+		x := float64(i - radius)
+		poly, xp := 0.0, 1.0
+		for _, c := range q {
+			poly += c * xp
+			xp *= x
+		}
+		phi[i] *= poly
+	}
+	return phi
+}
+
+// GaussianFilter1D filters input along axis with a Gaussian of standard
+// deviation sigma, see scipy.ndimage.gaussian_filter1d. opts.Order selects a
+// derivative of the Gaussian and opts.Truncate the kernel radius.
+func GaussianFilter1D(input interface{}, sigma float64, axis int, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	return gaussianFilter1D(a, sigma, axis, opts)
+}
+
+func gaussianFilter1D(a NDArray, sigma float64, axis int, opts *FilterOptions) (NDArray, error) {
+	if opts.Order < 0 {
+		return NDArray{}, fmt.Errorf("order must be non-negative")
+	}
+	truncate := opts.Truncate
+	if truncate == 0 {
+		truncate = 4.0
+	}
+	radius := int(truncate*sigma + 0.5)
+	kernel := gaussianKernel(sigma, opts.Order, radius)
+	// scipy correlates with the reversed kernel, so odd derivatives keep
+	// their sign convention
+	for i, j := 0, len(kernel)-1; i < j; i, j = i+1, j-1 {
+		kernel[i], kernel[j] = kernel[j], kernel[i]
+	}
+	return alongAxis(a, axis, func(line NpArray) NpArray {
+		return correlateLine(line, kernel, 0, opts)
+	})
+}
+
+// GaussianFilter filters input along every axis in turn, see
+// scipy.ndimage.gaussian_filter. sigma holds one value per axis, or a single
+// value used for all of them.
+func GaussianFilter(input interface{}, sigma []float64, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	sigmas, err := perAxis(sigma, a.Ndim(), "sigma")
+	if err != nil {
+		return NDArray{}, err
+	}
+	ret := a.Copy()
+	for axis, s := range sigmas {
+		if s > 1e-15 {
+			if ret, err = gaussianFilter1D(ret, s, axis, opts); err != nil {
+				return NDArray{}, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+// UniformFilter1D replaces each element with the mean of the size elements
+// around it along axis, see scipy.ndimage.uniform_filter1d.
+func UniformFilter1D(input interface{}, size, axis int, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	return uniformFilter1D(a, size, axis, opts)
+}
+
+func uniformFilter1D(a NDArray, size, axis int, opts *FilterOptions) (NDArray, error) {
+	if size < 1 {
+		return NDArray{}, fmt.Errorf("incorrect filter size")
+	}
+	weights := make(NpArray, size)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return alongAxis(a, axis, func(line NpArray) NpArray {
+		ret := correlateLine(line, weights, 0, opts)
+		for i := range ret {
+			ret[i] /= float64(size)
+		}
+		return ret
+	})
+}
+
+// UniformFilter applies UniformFilter1D along every axis, size holds one
+// value per axis or a single value used for all of them.
+func UniformFilter(input interface{}, size []int, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	sizes, err := perAxis(size, a.Ndim(), "size")
+	if err != nil {
+		return NDArray{}, err
+	}
+	ret := a.Copy()
+	for axis, s := range sizes {
+		if s > 1 {
+			if ret, err = uniformFilter1D(ret, s, axis, opts); err != nil {
+				return NDArray{}, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+// MedianFilter replaces each element with the median of the box of the given
+// size around it, see scipy.ndimage.median_filter. For even sized boxes the
+// upper of the two middle values is used, as scipy does.
+func MedianFilter(input interface{}, size []int, opts *FilterOptions) (NDArray, error) {
+	a, err := AsNDArray(input)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if opts, err = filterOptions(opts); err != nil {
+		return NDArray{}, err
+	}
+	sizes, err := perAxis(size, a.Ndim(), "size")
+	if err != nil {
+		return NDArray{}, err
+	}
+	count := 1
+	for _, s := range sizes {
+		if s < 1 {
+			return NDArray{}, fmt.Errorf("incorrect filter size")
+		}
+		count *= s
+	}
+	ret := NDZeros(a.shape...)
+	window := make(NpArray, 0, count)
+	i := 0
+	eachIndex(a.shape, func(idx []int) {
+		window = window[:0]
+		eachIndex(sizes, func(off []int) {
+			pos := a.offset
+			for d := range idx {
+				j := extendIndex(idx[d]+off[d]-sizes[d]/2, a.shape[d], opts.Mode)
+				if j < 0 {
+					pos = -1
+					break
+				}
+				pos += j * a.strides[d]
+			}
+			if pos < 0 {
+				window = append(window, opts.CVal)
+			} else {
+				window = append(window, a.data[pos])
+			}
+		})
+		sort.Float64s(window)
+		ret.data[i] = window[count/2]
+		i++
+	})
+	return ret, nil
+}
+
+// eachIndex calls f with every index of shape in C order.
+func eachIndex(shape []int, f func(idx []int)) {
+	for _, d := range shape {
+		if d == 0 {
+			return
+		}
+	}
+	idx := make([]int, len(shape))
+	for {
+		f(idx)
+		i := len(shape) - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < shape[i] {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// perAxis expands a per axis argument given once or once per axis.
+func perAxis[T any](v []T, n int, name string) ([]T, error) {
+	switch len(v) {
+	case n:
+		return v, nil
+	case 1:
+		ret := make([]T, n)
+		for i := range ret {
+			ret[i] = v[0]
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("%s must have one value or one per axis, got %d for %d axes", name, len(v), n)
+}
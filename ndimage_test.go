@@ -0,0 +1,199 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func filter1D(t *testing.T, ret NDArray, err error) NpArray {
+	t.Helper()
+	assert.NoError(t, err)
+	a, err := ret.ToNpArray()
+	assert.NoError(t, err)
+	return a
+}
+
+func TestGaussianFilter1D_Scipy(t *testing.T) {
+	ret, err := GaussianFilter1D(NpArray{1, 2, 3, 4, 5}, 1, -1, nil)
+	assert.True(t, filter1D(t, ret, err).AlmostEqual(NpArray{1.4270409503911734, 2.0678220347792573, 3, 3.932177965220743, 4.572959049608826}, 1e-12))
+
+	ret, err = GaussianFilter1D(NpArray{1, 2, 3, 4, 5}, 4, -1, nil)
+	assert.True(t, filter1D(t, ret, err).AlmostEqual(NpArray{2.91948343, 2.95023502, 3, 3.04976498, 3.08051657}, 1e-8))
+}
+
+func TestGaussianKernel(t *testing.T) {
+	// scipy's _gaussian_kernel1d(1.5, 0, 6), two of whose exponentials Go's
+	// math.Exp rounds the other way
+	assert.Equal(t, NpArray{8.922106863539231e-05, 0.001028196578138909, 0.007597402196596929, 0.03599434807370883, 0.1093411749545305, 0.212967528547854, 0.2659642571610709, 0.212967528547854, 0.1093411749545305, 0.03599434807370883, 0.007597402196596929, 0.001028196578138909, 8.922106863539231e-05}, gaussianKernel(1.5, 0, 6))
+}
+
+func TestGaussianFilter1D_Modes(t *testing.T) {
+	a := NpArray{2, 8, 0, 4, 1, 9, 9, 0}
+	expected := map[BoundaryMode]NpArray{
+		ModeReflect:  {3.7301855477315717, 3.615477693128651, 3.409084417865326, 3.555785726865977, 4.3345892123831415, 5.101451962653882, 4.965819486677504, 4.287605952693947},
+		ModeConstant: {3.314844974566036, 3.4897644084277553, 3.38795559233488, 3.5561429539553173, 4.3463428855821755, 5.135549882939642, 4.957234510571099, 3.885420735479302},
+		ModeNearest:  {3.131336038856304, 3.41273923699195, 3.3653779557047514, 3.5489919999166957, 4.323997127150361, 5.023732352612624, 4.572108653392072, 2.96787605693064},
+		ModeMirror:   {4.262672077712608, 4.0115887458672175, 3.5689194014591714, 3.602755220898239, 4.407707474523181, 5.417978512309869, 5.891838549155379, 5.93575211386128},
+		ModeWrap:     {3.7170301917806814, 3.498349384534161, 3.35385767204912, 3.544389280756283, 4.345985658492836, 5.156678708470088, 5.082947795271995, 4.300761308644837},
+	}
+	for mode, want := range expected {
+		ret, err := GaussianFilter1D(a, 1.5, 0, &FilterOptions{Mode: mode, CVal: 2.5})
+		assert.True(t, filter1D(t, ret, err).AlmostEqual(want, 1e-12), mode.String())
+	}
+}
+
+func TestGaussianFilter1D_Order(t *testing.T) {
+	a := NpArray{2, 8, 0, 4, 1, 9, 9, 0}
+	ret, err := GaussianFilter1D(a, 1, 0, &FilterOptions{Order: 1})
+	assert.True(t, filter1D(t, ret, err).AlmostEqual(NpArray{0.6395470071533321, -0.3562295731649097, -0.9822636209486961, 0.44195214952174816, 2.074262197978015, 1.5043778683856297, -2.2144294389642156, -2.072984299524033}, 1e-12))
+
+	ret, err = GaussianFilter1D(a, 2, 0, &FilterOptions{Order: 2, Mode: ModeWrap, Truncate: 2})
+	assert.True(t, filter1D(t, ret, err).AlmostEqual(NpArray{-0.0616251312904645, 0.10425188912290995, 0.15404014694241802, -0.010101062339184855, -0.24161735039268334, -0.42420120659720445, -0.5148532059482277, -0.18511200426934227}, 1e-12))
+
+	_, err = GaussianFilter1D(a, 1, 0, &FilterOptions{Order: -1})
+	assert.Error(t, err)
+}
+
+func TestGaussianFilter_Stack(t *testing.T) {
+	m := NpStack{{2, 8, 0, 4, 1, 9, 9, 0}, {1, 2, 3, 4, 5, 6, 7, 8}}
+	ret, err := GaussianFilter1D(m, 1.5, 1, nil)
+	assert.NoError(t, err)
+	rows, err := ret.ToNpStack()
+	assert.NoError(t, err)
+	assert.True(t, rows[0].AlmostEqual(NpArray{3.7301855477315717, 3.615477693128651, 3.409084417865326, 3.555785726865977, 4.3345892123831415, 5.101451962653882, 4.965819486677504, 4.287605952693947}, 1e-12))
+
+	// filtering the transposed stack along axis 0 is the same
+	tr, err := ret.Transpose()
+	assert.NoError(t, err)
+	nd, err := m.ToNDArray()
+	assert.NoError(t, err)
+	cols, err := GaussianFilter1D(nd.T(), 1.5, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tr.Flatten(), cols.Flatten())
+
+	// the separable filter over both axes of a 5x5 ramp, scipy's
+	// documentation example before it truncates to integers
+	data := make(NpArray, 25)
+	for i := range data {
+		data[i] = float64(2 * i)
+	}
+	img, _ := NewNDArray(data, 5, 5)
+	ret, err = GaussianFilter(img, []float64{1}, nil)
+	assert.NoError(t, err)
+	expected := NpArray{
+		5.124491404694082, 6.40605357347025, 8.270409503911736, 10.134765434353222, 11.416327603129389,
+		11.532302248574918, 12.813864417351088, 14.678220347792573, 16.54257627823406, 17.824138447010228,
+		20.854081900782347, 22.135644069558516, 24, 25.864355930441487, 27.145918099217656,
+		30.175861552989776, 31.457423721765945, 33.32177965220743, 35.186135582648916, 36.467697751425085,
+		36.58367239687062, 37.86523456564678, 39.72959049608827, 41.59394642652975, 42.875508595305924,
+	}
+	assert.True(t, NpArray(ret.Flatten()).AlmostEqual(expected, 1e-12))
+
+	// a zero sigma leaves that axis alone
+	ret, err = GaussianFilter(img, []float64{0, 1}, nil)
+	assert.NoError(t, err)
+	rowsOnly, err := GaussianFilter1D(img, 1, 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, rowsOnly.Flatten(), ret.Flatten())
+
+	_, err = GaussianFilter(img, []float64{1, 2, 3}, nil)
+	assert.Error(t, err)
+	_, err = GaussianFilter1D(img, 1, 2, nil)
+	assert.Error(t, err)
+}
+
+func TestCorrelateConvolve1D(t *testing.T) {
+	a := NpArray{2, 8, 0, 4, 1, 9, 9, 0}
+	ret, err := Correlate1D(a, NpArray{1, 3}, 0, nil)
+	assert.Equal(t, NpArray{8, 26, 8, 12, 7, 28, 36, 9}, filter1D(t, ret, err))
+
+	ret, err = Convolve1D(a, NpArray{1, 3}, 0, nil)
+	assert.Equal(t, NpArray{14, 24, 4, 13, 12, 36, 27, 0}, filter1D(t, ret, err))
+
+	ret, err = Convolve1D(NpArray{1, 2, 3}, NpArray{1, 0, -1}, 0, &FilterOptions{Mode: ModeConstant})
+	assert.Equal(t, NpArray{2, 2, -2}, filter1D(t, ret, err))
+
+	_, err = Convolve1D(a, nil, 0, nil)
+	assert.Error(t, err)
+	_, err = Convolve1D(a, NpArray{1}, 0, &FilterOptions{Mode: BoundaryMode(9)})
+	assert.Error(t, err)
+}
+
+func TestExtendIndex(t *testing.T) {
+	// a b c d extended by four on each side
+	expected := map[BoundaryMode][]int{
+		ModeReflect:  {3, 2, 1, 0, 0, 1, 2, 3, 3, 2, 1, 0},
+		ModeConstant: {-1, -1, -1, -1, 0, 1, 2, 3, -1, -1, -1, -1},
+		ModeNearest:  {0, 0, 0, 0, 0, 1, 2, 3, 3, 3, 3, 3},
+		ModeMirror:   {2, 3, 2, 1, 0, 1, 2, 3, 2, 1, 0, 1},
+		ModeWrap:     {0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3},
+	}
+	for mode, want := range expected {
+		got := make([]int, 12)
+		for i := range got {
+			got[i] = extendIndex(i-4, 4, mode)
+		}
+		assert.Equal(t, want, got, mode.String())
+	}
+	assert.Equal(t, 0, extendIndex(-3, 1, ModeMirror))
+}
+
+func TestUniformFilter(t *testing.T) {
+	ret, err := UniformFilter1D(NpArray{2, 8, 0, 4, 1, 9, 9, 0}, 3, 0, nil)
+	assert.True(t, filter1D(t, ret, err).AlmostEqual(NpArray{4, 10.0 / 3, 4, 5.0 / 3, 14.0 / 3, 19.0 / 3, 6, 3}, 1e-12))
+
+	// even sizes reach one further left than right
+	ret, err = UniformFilter1D(NpArray{1, 2, 3, 4}, 2, 0, &FilterOptions{Mode: ModeConstant})
+	assert.Equal(t, NpArray{0.5, 1.5, 2.5, 3.5}, filter1D(t, ret, err))
+
+	img, _ := NewNDArray([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3, 3)
+	ret, err = UniformFilter(img, []int{3}, &FilterOptions{Mode: ModeWrap})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{5, 5, 5, 5, 5, 5, 5, 5, 5}, ret.Flatten())
+
+	_, err = UniformFilter1D(img, 0, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestMedianFilter(t *testing.T) {
+	ret, err := MedianFilter(NpArray{1, 5, 2, 8, 3}, []int{3}, nil)
+	assert.Equal(t, NpArray{1, 2, 5, 3, 3}, filter1D(t, ret, err))
+
+	ret, err = MedianFilter(NpArray{1, 5, 2, 8, 3}, []int{3}, &FilterOptions{Mode: ModeConstant, CVal: 10})
+	assert.Equal(t, NpArray{5, 2, 5, 3, 8}, filter1D(t, ret, err))
+
+	// the upper middle value for even sizes
+	ret, err = MedianFilter(NpArray{4, 1, 3, 2}, []int{2}, nil)
+	assert.Equal(t, NpArray{4, 4, 3, 3}, filter1D(t, ret, err))
+
+	img, _ := NewNDArray([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3, 3)
+	ret, err = MedianFilter(img, []int{3, 3}, &FilterOptions{Mode: ModeNearest})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, ret.At(0, 0))
+	assert.Equal(t, 5.0, ret.At(1, 1))
+	assert.Equal(t, 8.0, ret.At(2, 2))
+}
+
+func TestCorrelate1D_Folding(t *testing.T) {
+	// like scipy, odd length symmetric and antisymmetric weights are folded
+	// about their centre, so mirroring the input mirrors the output exactly
+	a := NewRandomState(5).RandN(101)
+	rev := func(x NpArray) NpArray {
+		ret := make(NpArray, len(x))
+		for i, v := range x {
+			ret[len(x)-1-i] = v
+		}
+		return ret
+	}
+	sym := NpArray{0.1, 0.2, 0.4, 0.2, 0.1}
+	ret, err := Correlate1D(a, sym, 0, nil)
+	fwd := filter1D(t, ret, err)
+	ret, err = Correlate1D(rev(a), sym, 0, nil)
+	assert.Equal(t, rev(fwd), filter1D(t, ret, err))
+
+	anti := NpArray{-0.3, -0.1, 0, 0.1, 0.3}
+	ret, err = Correlate1D(a, anti, 0, nil)
+	fwd = filter1D(t, ret, err)
+	ret, err = Correlate1D(rev(a), anti, 0, nil)
+	assert.Equal(t, rev(fwd).MulFloat64(-1), filter1D(t, ret, err))
+}
@@ -0,0 +1,505 @@
+package np
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The .npy format is described in numpy/lib/format.py, a magic string and a
+// version followed by a python dict literal holding descr, fortran_order and
+// shape, padded so the data starts on a 64 byte boundary.
+const (
+	npyMagic       = "\x93NUMPY"
+	npyArrayAlign  = 64
+	npyGrowthSpace = 21
+	// npyMaxHeaderSize is numpy's default max_header_size, larger headers
+	// are refused as unsafe to parse.
+	npyMaxHeaderSize = 10000
+)
+
+// npyHeader is the decoded header dict of a .npy file.
+type npyHeader struct {
+	descr        string
+	fortranOrder bool
+	shape        []int
+}
+
+// Save writes an NpArray, NpStack or NDArray to path in the .npy format.
+func Save(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := WriteNpy(w, v); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads a .npy file, booleans, integers and floats are converted to
+// float64.
+func Load(path string) (NDArray, error) {
+	return LoadAs[float64](path)
+}
+
+// LoadAs reads a .npy file into an Array of element type T. The stored dtype
+// must convert to T under numpy's same_kind rule, so int64 labels can be
+// loaded as int64 or float64 but float data cannot be loaded as integers.
+func LoadAs[T Element](path string) (Array[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Array[T]{}, err
+	}
+	defer f.Close()
+	return ReadNpyAs[T](bufio.NewReader(f))
+}
+
+// LoadNpArray reads a 1-D .npy file.
+func LoadNpArray(path string) (NpArray, error) {
+	a, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.ToNpArray()
+}
+
+// LoadNpStack reads a 2-D .npy file.
+func LoadNpStack(path string) (NpStack, error) {
+	a, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.ToNpStack()
+}
+
+// npyEncoder is implemented by every Array, whatever its element type.
+type npyEncoder interface {
+	encodeNpy() (npyHeader, []byte)
+}
+
+// WriteNpy writes v as a .npy stream. An Array keeps its dtype, for example
+// Array[uint8] is written as |u1, other values are written as little endian
+// float64 (<f8). NpStack values must be rectangular.
+func WriteNpy(w io.Writer, v interface{}) error {
+	enc, ok := v.(npyEncoder)
+	if !ok {
+		a, err := AsNDArray(v)
+		if err != nil {
+			return err
+		}
+		enc = a
+	}
+	h, data := enc.encodeNpy()
+	if err := writeNpyHeader(w, h); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func (a Array[T]) encodeNpy() (npyHeader, []byte) {
+	dtype := DTypeOf[T]()
+	order := "<"
+	if dtype.ItemSize() == 1 {
+		order = "|"
+	}
+	descr := fmt.Sprintf("%s%c%d", order, dtype.Kind(), dtype.ItemSize())
+	width := dtype.ItemSize()
+	buf := make([]byte, width*a.Size())
+	i := 0
+	a.each(func(pos int) {
+		putNpyValue(buf[i*width:(i+1)*width], a.data[pos])
+		i++
+	})
+	return npyHeader{descr: descr, shape: a.shape}, buf
+}
+
+// ReadNpy reads a .npy stream of version 1, 2 or 3 as float64.
+func ReadNpy(r io.Reader) (NDArray, error) {
+	return ReadNpyAs[float64](r)
+}
+
+// ReadNpyAs reads a .npy stream into an Array of element type T, see LoadAs.
+func ReadNpyAs[T Element](r io.Reader) (Array[T], error) {
+	h, order, dtype, raw, err := readNpyRaw(r)
+	if err != nil {
+		return Array[T]{}, err
+	}
+	return decodeNpyData[T](raw, order, dtype, h.shape, h.fortranOrder)
+}
+
+// readNpyRaw reads the header of a .npy stream and its undecoded data.
+func readNpyRaw(r io.Reader) (npyHeader, binary.ByteOrder, DType, []byte, error) {
+	h, err := readNpyHeader(r)
+	if err != nil {
+		return npyHeader{}, nil, 0, nil, err
+	}
+	size, err := shapeSize(h.shape)
+	if err != nil {
+		return npyHeader{}, nil, 0, nil, err
+	}
+	order, dtype, err := parseDescr(h.descr)
+	if err != nil {
+		return npyHeader{}, nil, 0, nil, err
+	}
+	if size > math.MaxInt/dtype.ItemSize() {
+		return npyHeader{}, nil, 0, nil, fmt.Errorf("npy: array of shape %v is too big", h.shape)
+	}
+	// the shape comes from the header, so the buffer only grows as the data
+	// arrives rather than trusting it for the allocation
+	n := int64(size * dtype.ItemSize())
+	var raw bytes.Buffer
+	if _, err := io.CopyN(&raw, r, n); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return npyHeader{}, nil, 0, nil, fmt.Errorf("npy: reading %d elements: %w", size, err)
+	}
+	return h, order, dtype, raw.Bytes(), nil
+}
+
+// decodeNpyData converts a raw buffer of dtype elements to an Array of T,
+// shared by .npy files and pickled arrays.
+func decodeNpyData[T Element](raw []byte, order binary.ByteOrder, dtype DType, shape []int, fortran bool) (Array[T], error) {
+	if !CanCast(dtype, DTypeOf[T](), CastSameKind) {
+		return Array[T]{}, fmt.Errorf("npy: cannot load dtype('%s') as %s", dtype, DTypeOf[T]())
+	}
+	width := dtype.ItemSize()
+	data := make([]T, len(raw)/width)
+	for i := range data {
+		data[i] = decodeNpyValue[T](raw[i*width:(i+1)*width], order, dtype)
+	}
+	ret := Array[T]{data: data, shape: shape, strides: cStrides(shape)}
+	if fortran {
+		// the data is stored with the first axis varying fastest
+		stride := 1
+		for i, d := range shape {
+			ret.strides[i] = stride
+			stride *= d
+		}
+	}
+	return ret, nil
+}
+
+func writeNpyHeader(w io.Writer, h npyHeader) error {
+	dims := make([]string, len(h.shape))
+	for i, d := range h.shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	shape := "(" + strings.Join(dims, ", ") + ")"
+	if len(dims) == 1 {
+		shape = "(" + dims[0] + ",)"
+	}
+	fortran := "False"
+	if h.fortranOrder {
+		fortran = "True"
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': %s, }", h.descr, fortran, shape)
+	// numpy leaves room for the growth axis to be rewritten in place
+	if len(dims) > 0 {
+		grow := dims[0]
+		if h.fortranOrder {
+			grow = dims[len(dims)-1]
+		}
+		header += strings.Repeat(" ", npyGrowthSpace-len(grow))
+	}
+	major, lenSize := byte(1), 2
+	pad := npyArrayAlign - (len(npyMagic)+2+lenSize+len(header)+1)%npyArrayAlign
+	if len(header)+pad+1 >= 1<<16 {
+		major, lenSize = 2, 4
+		pad = npyArrayAlign - (len(npyMagic)+2+lenSize+len(header)+1)%npyArrayAlign
+	}
+	hlen := len(header) + pad + 1
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if lenSize == 2 {
+		binary.Write(&buf, binary.LittleEndian, uint16(hlen))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(hlen))
+	}
+	buf.WriteString(header)
+	buf.WriteString(strings.Repeat(" ", pad))
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func readNpyHeader(r io.Reader) (npyHeader, error) {
+	prefix := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return npyHeader{}, fmt.Errorf("npy: reading magic: %w", err)
+	}
+	if string(prefix[:len(npyMagic)]) != npyMagic {
+		return npyHeader{}, fmt.Errorf("npy: bad magic string %q", prefix[:len(npyMagic)])
+	}
+	var hlen int
+	switch major := prefix[len(npyMagic)]; major {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return npyHeader{}, err
+		}
+		hlen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return npyHeader{}, err
+		}
+		hlen = int(n)
+	default:
+		return npyHeader{}, fmt.Errorf("npy: unsupported format version %d.%d", major, prefix[len(npyMagic)+1])
+	}
+	if hlen > npyMaxHeaderSize {
+		return npyHeader{}, fmt.Errorf("npy: header length %d exceeds %d", hlen, npyMaxHeaderSize)
+	}
+	raw := make([]byte, hlen)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return npyHeader{}, fmt.Errorf("npy: reading header: %w", err)
+	}
+	return parseNpyHeader(string(raw))
+}
+
+// parseNpyHeader decodes the subset of python literal syntax numpy writes in
+// a header: a dict of string keys mapping to strings, booleans and tuples of
+// integers.
+func parseNpyHeader(s string) (npyHeader, error) {
+	p := &literalParser{s: s}
+	var h npyHeader
+	seen := map[string]bool{}
+	if !p.consume('{') {
+		return h, fmt.Errorf("npy: header is not a dict: %q", s)
+	}
+	for {
+		if p.consume('}') {
+			break
+		}
+		key, err := p.str()
+		if err != nil {
+			return h, err
+		}
+		if !p.consume(':') {
+			return h, fmt.Errorf("npy: expected ':' after %q", key)
+		}
+		switch key {
+		case "descr":
+			if h.descr, err = p.str(); err != nil {
+				return h, fmt.Errorf("npy: unsupported descr: %w", err)
+			}
+		case "fortran_order":
+			if h.fortranOrder, err = p.boolean(); err != nil {
+				return h, err
+			}
+		case "shape":
+			if h.shape, err = p.intTuple(); err != nil {
+				return h, err
+			}
+		default:
+			return h, fmt.Errorf("npy: unexpected header key %q", key)
+		}
+		seen[key] = true
+		if !p.consume(',') {
+			if !p.consume('}') {
+				return h, fmt.Errorf("npy: malformed header %q", s)
+			}
+			break
+		}
+	}
+	for _, key := range []string{"descr", "fortran_order", "shape"} {
+		if !seen[key] {
+			return h, fmt.Errorf("npy: header is missing %q", key)
+		}
+	}
+	return h, nil
+}
+
+type literalParser struct {
+	s   string
+	pos int
+}
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *literalParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *literalParser) str() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) || (p.s[p.pos] != '\'' && p.s[p.pos] != '"') {
+		return "", fmt.Errorf("npy: expected a string at offset %d", p.pos)
+	}
+	quote := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], quote)
+	if end < 0 {
+		return "", fmt.Errorf("npy: unterminated string at offset %d", p.pos)
+	}
+	ret := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return ret, nil
+}
+
+func (p *literalParser) boolean() (bool, error) {
+	p.skipSpace()
+	for _, lit := range []string{"True", "False"} {
+		if strings.HasPrefix(p.s[p.pos:], lit) {
+			p.pos += len(lit)
+			return lit == "True", nil
+		}
+	}
+	return false, fmt.Errorf("npy: expected a boolean at offset %d", p.pos)
+}
+
+func (p *literalParser) intTuple() ([]int, error) {
+	if !p.consume('(') {
+		return nil, fmt.Errorf("npy: expected a tuple at offset %d", p.pos)
+	}
+	ret := []int{}
+	for {
+		if p.consume(')') {
+			return ret, nil
+		}
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		v, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return nil, fmt.Errorf("npy: bad shape entry at offset %d", start)
+		}
+		ret = append(ret, v)
+		// python 2 wrote long integers with an L suffix
+		p.consume('L')
+		if !p.consume(',') {
+			if !p.consume(')') {
+				return nil, fmt.Errorf("npy: malformed tuple at offset %d", p.pos)
+			}
+			return ret, nil
+		}
+	}
+}
+
+// parseDescr splits a simple dtype string such as <f8 or |u1 into its byte
+// order and DType.
+func parseDescr(descr string) (binary.ByteOrder, DType, error) {
+	if len(descr) < 3 {
+		return nil, 0, fmt.Errorf("npy: unsupported descr %q", descr)
+	}
+	var order binary.ByteOrder
+	switch descr[0] {
+	case '<', '|', '=':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	default:
+		return nil, 0, fmt.Errorf("npy: unsupported descr %q", descr)
+	}
+	width, err := strconv.Atoi(descr[2:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("npy: unsupported descr %q", descr)
+	}
+	for d := range dtypeInfo {
+		if dtypeInfo[d].kind == descr[1] && dtypeInfo[d].width == width {
+			return order, DType(d), nil
+		}
+	}
+	return nil, 0, fmt.Errorf("npy: unsupported descr %q", descr)
+}
+
+func decodeNpyValue[T Element](b []byte, order binary.ByteOrder, dtype DType) T {
+	var u uint64
+	switch len(b) {
+	case 1:
+		u = uint64(b[0])
+	case 2:
+		u = uint64(order.Uint16(b))
+	case 4:
+		u = uint64(order.Uint32(b))
+	case 8:
+		u = order.Uint64(b)
+	}
+	switch dtype {
+	case Bool:
+		return castValue[T](u != 0)
+	case Float32:
+		return castValue[T](math.Float32frombits(uint32(u)))
+	case Float64:
+		return castValue[T](math.Float64frombits(u))
+	case Complex64:
+		re := math.Float32frombits(order.Uint32(b))
+		im := math.Float32frombits(order.Uint32(b[4:]))
+		return castValue[T](complex(re, im))
+	case Complex128:
+		re := math.Float64frombits(order.Uint64(b))
+		im := math.Float64frombits(order.Uint64(b[8:]))
+		return castValue[T](complex(re, im))
+	}
+	if dtype.Kind() == 'i' {
+		// sign extend from the item width
+		shift := 64 - 8*uint(len(b))
+		return castValue[T](int64(u<<shift) >> shift)
+	}
+	return castValue[T](u)
+}
+
+// putNpyValue writes v little endian into b.
+func putNpyValue[T Element](b []byte, v T) {
+	le := binary.LittleEndian
+	switch x := any(v).(type) {
+	case bool:
+		b[0] = 0
+		if x {
+			b[0] = 1
+		}
+	case int8:
+		b[0] = byte(x)
+	case uint8:
+		b[0] = x
+	case int16:
+		le.PutUint16(b, uint16(x))
+	case uint16:
+		le.PutUint16(b, x)
+	case int32:
+		le.PutUint32(b, uint32(x))
+	case uint32:
+		le.PutUint32(b, x)
+	case int64:
+		le.PutUint64(b, uint64(x))
+	case uint64:
+		le.PutUint64(b, x)
+	case float32:
+		le.PutUint32(b, math.Float32bits(x))
+	case float64:
+		le.PutUint64(b, math.Float64bits(x))
+	case complex64:
+		le.PutUint32(b, math.Float32bits(real(x)))
+		le.PutUint32(b[4:], math.Float32bits(imag(x)))
+	case complex128:
+		le.PutUint64(b, math.Float64bits(real(x)))
+		le.PutUint64(b[8:], math.Float64bits(imag(x)))
+	}
+}
@@ -0,0 +1,226 @@
+package np
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// npyBytes builds a .npy stream with the given version, header and payload.
+func npyBytes(major byte, header string, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY")
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if major == 1 {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(header)))
+	}
+	buf.WriteString(header)
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func TestWriteNpy_HeaderMatchesNumpy(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteNpy(&buf, NpArray{1, 2, 3}))
+	out := buf.Bytes()
+	assert.Equal(t, 128+24, len(out))
+	assert.Equal(t, []byte("\x93NUMPY\x01\x00v\x00"), out[:10])
+	header := "{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }"
+	assert.Equal(t, header+strings.Repeat(" ", 118-len(header)-1)+"\n", string(out[10:128]))
+}
+
+func TestSaveLoad_NpArrayRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.npy")
+	arr := NpArray{1.5, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), 1e-310, math.MaxFloat64, math.Float64frombits(0x7ff8000000000001)}
+	assert.NoError(t, Save(path, arr))
+	loaded, err := LoadNpArray(path)
+	assert.NoError(t, err)
+	assert.Equal(t, len(arr), len(loaded))
+	for i := range arr {
+		assert.Equal(t, math.Float64bits(arr[i]), math.Float64bits(loaded[i]))
+	}
+}
+
+func TestSaveLoad_NpStackRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "m.npy")
+	stack := NpStack{
+		NpArray{1, 2, 3},
+		NpArray{4, 5, 6},
+	}
+	assert.NoError(t, Save(path, stack))
+	loaded, err := LoadNpStack(path)
+	assert.NoError(t, err)
+	assert.Equal(t, stack, loaded)
+}
+
+func TestSave_RaggedStackErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "m.npy")
+	assert.Error(t, Save(path, NpStack{{1, 2}, {3}}))
+}
+
+func TestReadNpy_Dtypes(t *testing.T) {
+	f4 := make([]byte, 8)
+	binary.LittleEndian.PutUint32(f4, math.Float32bits(1.5))
+	binary.LittleEndian.PutUint32(f4[4:], math.Float32bits(-2))
+	i8 := make([]byte, 16)
+	binary.LittleEndian.PutUint64(i8, uint64(7))
+	binary.LittleEndian.PutUint64(i8[8:], uint64(0xffffffffffffffff))
+	f8be := make([]byte, 8)
+	binary.BigEndian.PutUint64(f8be, math.Float64bits(3.25))
+
+	cases := []struct {
+		descr    string
+		shape    string
+		payload  []byte
+		expected []float64
+	}{
+		{"<f4", "(2,)", f4, NpArray{1.5, -2}},
+		{"<i8", "(2,)", i8, NpArray{7, -1}},
+		{"|u1", "(3,)", []byte{0, 128, 255}, NpArray{0, 128, 255}},
+		{"|i1", "(2,)", []byte{0x80, 1}, NpArray{-128, 1}},
+		{"|b1", "(2,)", []byte{1, 0}, NpArray{1, 0}},
+		{">f8", "(1,)", f8be, NpArray{3.25}},
+	}
+	for _, c := range cases {
+		header := "{'descr': '" + c.descr + "', 'fortran_order': False, 'shape': " + c.shape + ", }\n"
+		a, err := ReadNpy(bytes.NewReader(npyBytes(1, header, c.payload)))
+		assert.NoError(t, err, c.descr)
+		assert.Equal(t, c.expected, a.Flatten(), c.descr)
+	}
+}
+
+func TestReadNpy_Versions2And3(t *testing.T) {
+	payload := make([]byte, 16)
+	binary.LittleEndian.PutUint64(payload, math.Float64bits(1))
+	binary.LittleEndian.PutUint64(payload[8:], math.Float64bits(2))
+	header := "{'descr': '<f8', 'fortran_order': False, 'shape': (2L,), }\n"
+	for _, major := range []byte{2, 3} {
+		a, err := ReadNpy(bytes.NewReader(npyBytes(major, header, payload)))
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1, 2}, a.Flatten())
+	}
+}
+
+func TestReadNpy_FortranOrder(t *testing.T) {
+	payload := make([]byte, 48)
+	// column major storage of [[1 2 3] [4 5 6]]
+	for i, v := range []float64{1, 4, 2, 5, 3, 6} {
+		binary.LittleEndian.PutUint64(payload[8*i:], math.Float64bits(v))
+	}
+	header := "{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3), }\n"
+	a, err := ReadNpy(bytes.NewReader(npyBytes(1, header, payload)))
+	assert.NoError(t, err)
+	m, err := a.ToNpStack()
+	assert.NoError(t, err)
+	assert.Equal(t, NpStack{{1, 2, 3}, {4, 5, 6}}, m)
+}
+
+func TestReadNpy_Errors(t *testing.T) {
+	_, err := ReadNpy(bytes.NewReader([]byte("not a npy file")))
+	assert.Error(t, err)
+
+	header := "{'descr': '<U4', 'fortran_order': False, 'shape': (1,), }\n"
+	_, err = ReadNpy(bytes.NewReader(npyBytes(1, header, make([]byte, 16))))
+	assert.Error(t, err)
+
+	header = "{'descr': '<f8', 'fortran_order': False, 'shape': (4,), }\n"
+	_, err = ReadNpy(bytes.NewReader(npyBytes(1, header, make([]byte, 16))))
+	assert.Error(t, err)
+
+	header = "{'descr': '<f8', 'shape': (2,), }\n"
+	_, err = ReadNpy(bytes.NewReader(npyBytes(1, header, make([]byte, 16))))
+	assert.Error(t, err)
+}
+
+func TestSaveLoad_TypedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	labels, _ := NewArray([]int64{7, -1, 3}, 3)
+	assert.NoError(t, Save(filepath.Join(dir, "y.npy"), labels))
+	y, err := LoadAs[int64](filepath.Join(dir, "y.npy"))
+	assert.NoError(t, err)
+	assert.Equal(t, labels.Flatten(), y.Flatten())
+
+	mask, _ := NewArray([]bool{true, false, true, true}, 2, 2)
+	assert.NoError(t, Save(filepath.Join(dir, "m.npy"), mask))
+	m, err := LoadAs[bool](filepath.Join(dir, "m.npy"))
+	assert.NoError(t, err)
+	assert.Equal(t, mask.Flatten(), m.Flatten())
+	assert.Equal(t, []int{2, 2}, m.Shape())
+
+	img, _ := NewArray([]uint8{0, 128, 255}, 3)
+	var buf bytes.Buffer
+	assert.NoError(t, WriteNpy(&buf, img))
+	assert.Contains(t, buf.String(), "'descr': '|u1'")
+	pix, err := ReadNpyAs[uint8](bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{0, 128, 255}, pix.Flatten())
+
+	z, _ := NewArray([]complex128{complex(1, -2)}, 1)
+	buf.Reset()
+	assert.NoError(t, WriteNpy(&buf, z))
+	assert.Contains(t, buf.String(), "'descr': '<c16'")
+	zz, err := ReadNpyAs[complex128](bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, z.Flatten(), zz.Flatten())
+}
+
+func TestLoadAs_CastingRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.npy")
+	assert.NoError(t, Save(path, NpArray{1.5, 2}))
+	_, err := LoadAs[int64](path)
+	assert.Error(t, err)
+
+	labels, _ := NewArray([]int64{1, 2}, 2)
+	assert.NoError(t, Save(path, labels))
+	f, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, f.Flatten())
+}
+
+func TestReadNpy_UntrustedShape(t *testing.T) {
+	// a 100 byte file claiming 512 GiB of data is truncated, without the
+	// claimed size being allocated
+	header := "{'descr': '<f8', 'fortran_order': False, 'shape': (68719476736,), }\n"
+	data := npyBytes(1, header, make([]byte, 24))
+	allocs := testing.AllocsPerRun(1, func() {
+		_, err := ReadNpy(bytes.NewReader(data))
+		assert.ErrorContains(t, err, "npy: reading 68719476736 elements: unexpected EOF")
+	})
+	assert.Less(t, allocs, 100.0)
+
+	header = "{'descr': '<f8', 'fortran_order': False, 'shape': (1152921504606846976,), }\n"
+	_, err := ReadNpy(bytes.NewReader(npyBytes(1, header, nil)))
+	assert.EqualError(t, err, "npy: array of shape [1152921504606846976] is too big")
+	header = "{'descr': '<f8', 'fortran_order': False, 'shape': (4294967296, 4294967296), }\n"
+	_, err = ReadNpy(bytes.NewReader(npyBytes(1, header, nil)))
+	assert.EqualError(t, err, "array is too big: [4294967296 4294967296]")
+
+	_, err = ReadNpy(bytes.NewReader([]byte("\x93NUMPY\x02\x00\xff\xff\xff\xff")))
+	assert.EqualError(t, err, "npy: header length 4294967295 exceeds 10000")
+
+	// every truncation of a valid file fails
+	var buf bytes.Buffer
+	assert.NoError(t, WriteNpy(&buf, NpStack{{1, 2, 3}, {4, 5, 6}}))
+	for n := 0; n < buf.Len(); n++ {
+		_, err := ReadNpy(bytes.NewReader(buf.Bytes()[:n]))
+		assert.Error(t, err, "%d of %d bytes", n, buf.Len())
+	}
+}
+
+func FuzzReadNpy(f *testing.F) {
+	var buf bytes.Buffer
+	_ = WriteNpy(&buf, NpStack{{1, 2, 3}, {4, 5, 6}})
+	f.Add(buf.Bytes())
+	f.Add(npyBytes(2, "{'descr': '<i4', 'fortran_order': True, 'shape': (2, 1), }\n", make([]byte, 8)))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = ReadNpy(bytes.NewReader(data))
+	})
+}
@@ -0,0 +1,151 @@
+package np
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SaveNpz writes a .npz archive, an uncompressed zip holding one .npy member
+// per name, as numpy.savez does. Values may be NpArray, NpStack or NDArray.
+func SaveNpz(path string, arrays map[string]interface{}) error {
+	return saveNpz(path, arrays, false)
+}
+
+// SaveNpzCompressed writes a deflate compressed .npz archive, as
+// numpy.savez_compressed does.
+func SaveNpzCompressed(path string, arrays map[string]interface{}) error {
+	return saveNpz(path, arrays, true)
+}
+
+func saveNpz(path string, arrays map[string]interface{}, compressed bool) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteNpz(f, arrays, compressed); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteNpz writes a .npz archive to w, members are written in name order.
+func WriteNpz(w io.Writer, arrays map[string]interface{}, compressed bool) error {
+	method := zip.Store
+	if compressed {
+		method = zip.Deflate
+	}
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		member, err := zw.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: method})
+		if err != nil {
+			return err
+		}
+		if err := WriteNpy(member, arrays[name]); err != nil {
+			return fmt.Errorf("npz: %s: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// LoadNpz reads every .npy member of a .npz archive as float64, keyed by the
+// member name without its .npy suffix. A 64-bit integer member holding a
+// value float64 cannot represent exactly is an error, load such archives
+// with LoadNpzAs.
+func LoadNpz(path string) (map[string]NDArray, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readNpz[float64](&zr.Reader, true)
+}
+
+// LoadNpzAs reads every .npy member of a .npz archive into an Array of
+// element type T, converting each stored dtype as LoadAs does.
+func LoadNpzAs[T Element](path string) (map[string]Array[T], error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readNpz[T](&zr.Reader, false)
+}
+
+// ReadNpz reads a .npz archive from r, see LoadNpz.
+func ReadNpz(r io.ReaderAt, size int64) (map[string]NDArray, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return readNpz[float64](zr, true)
+}
+
+// ReadNpzAs reads a .npz archive from r, see LoadNpzAs.
+func ReadNpzAs[T Element](r io.ReaderAt, size int64) (map[string]Array[T], error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return readNpz[T](zr, false)
+}
+
+// readNpz decodes the members, with exact set rejecting integers that do
+// not survive the conversion to float64.
+func readNpz[T Element](zr *zip.Reader, exact bool) (map[string]Array[T], error) {
+	ret := make(map[string]Array[T], len(zr.File))
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".npy") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		h, order, dtype, raw, err := readNpyRaw(bufio.NewReader(rc))
+		rc.Close()
+		if err == nil && exact && !exactInFloat64(raw, order, dtype) {
+			err = fmt.Errorf("%s value cannot be represented exactly as float64", dtype)
+		}
+		var a Array[T]
+		if err == nil {
+			a, err = decodeNpyData[T](raw, order, dtype, h.shape, h.fortranOrder)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("npz: %s: %w", f.Name, err)
+		}
+		ret[strings.TrimSuffix(f.Name, ".npy")] = a
+	}
+	return ret, nil
+}
+
+// exactInFloat64 reports whether every element of raw converts to float64
+// without rounding, which only 64-bit integers can fail.
+func exactInFloat64(raw []byte, order binary.ByteOrder, dtype DType) bool {
+	if dtype != Int64 && dtype != Uint64 {
+		return true
+	}
+	for i := 0; i+8 <= len(raw); i += 8 {
+		u := order.Uint64(raw[i:])
+		if dtype == Int64 {
+			v := int64(u)
+			if f := float64(v); f >= 0x1p63 || int64(f) != v {
+				return false
+			}
+		} else if f := float64(u); f >= 0x1p64 || uint64(f) != u {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package np
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveNpz_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	arrays := map[string]interface{}{
+		"x": NpStack{{1, 2, 3}, {4, 5, 6}},
+		"y": NpArray{0, 1},
+		"t": NpArray{-0.5, 0, 0.5},
+	}
+	for _, save := range []func(string, map[string]interface{}) error{SaveNpz, SaveNpzCompressed} {
+		path := filepath.Join(dir, "data.npz")
+		assert.NoError(t, save(path, arrays))
+		loaded, err := LoadNpz(path)
+		assert.NoError(t, err)
+		assert.Len(t, loaded, 3)
+		x, err := loaded["x"].ToNpStack()
+		assert.NoError(t, err)
+		assert.Equal(t, arrays["x"], x)
+		y, err := loaded["y"].ToNpArray()
+		assert.NoError(t, err)
+		assert.Equal(t, arrays["y"], y)
+	}
+}
+
+func TestWriteNpz_Members(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		var buf bytes.Buffer
+		assert.NoError(t, WriteNpz(&buf, map[string]interface{}{"b": NpArray{1}, "a": NpArray{2}}, compressed))
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		assert.Equal(t, "a.npy", zr.File[0].Name)
+		assert.Equal(t, "b.npy", zr.File[1].Name)
+		method := zip.Store
+		if compressed {
+			method = zip.Deflate
+		}
+		assert.Equal(t, method, zr.File[0].Method)
+
+		loaded, err := ReadNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{2}, loaded["a"].Flatten())
+	}
+}
+
+func TestWriteNpz_BadValue(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, WriteNpz(&buf, map[string]interface{}{"x": "not an array"}, false))
+}
+
+func TestLoadNpz_Int64(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.npz")
+	labels, err := NewArray([]int64{3, -1, 1<<53 + 1}, 3)
+	assert.NoError(t, err)
+	small, err := NewArray([]int64{3, -1}, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, SaveNpz(path, map[string]interface{}{"labels": labels, "small": small}))
+
+	_, err = LoadNpz(path)
+	assert.EqualError(t, err, "npz: labels.npy: int64 value cannot be represented exactly as float64")
+	loaded, err := LoadNpzAs[int64](path)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, -1, 1<<53 + 1}, loaded["labels"].Flatten())
+	assert.Equal(t, []int64{3, -1}, loaded["small"].Flatten())
+
+	assert.NoError(t, SaveNpz(path, map[string]interface{}{"small": small}))
+	floats, err := LoadNpz(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, -1}, floats["small"].Flatten())
+}
+
+func TestLoadNpz_MissingFile(t *testing.T) {
+	_, err := LoadNpz(filepath.Join(t.TempDir(), "missing.npz"))
+	assert.Error(t, err)
+}
+
+func TestReadNpz_UntrustedShape(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	member, err := zw.Create("x.npy")
+	assert.NoError(t, err)
+	header := "{'descr': '<f8', 'fortran_order': False, 'shape': (68719476736,), }\n"
+	_, err = member.Write(npyBytes(1, header, make([]byte, 24)))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	_, err = ReadNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.EqualError(t, err, "npz: x.npy: npy: reading 68719476736 elements: unexpected EOF")
+}
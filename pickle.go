@@ -0,0 +1,920 @@
+package np
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Unpickle decodes a python pickle (protocols 0 to 5) holding plain python
+// data and numpy arrays, such as the published mnist1d dataset. Only a small
+// set of numpy globals may be referenced, any other class is rejected so an
+// untrusted file can not run code.
+//
+// Python values decode as:
+//
+//	None                nil
+//	bool                bool
+//	int                 int64
+//	float               float64
+//	str                 string
+//	bytes, bytearray    []byte
+//	list, tuple         []interface{}
+//	dict                map[interface{}]interface{}
+//	numpy.ndarray       NpArray (1-D), NpStack (2-D) or NDArray
+//	numpy scalar        float64
+func Unpickle(r io.Reader) (interface{}, error) {
+	u := &unpickler{r: bufio.NewReader(r), memo: map[int]interface{}{}}
+	v, err := u.load()
+	if err != nil {
+		return nil, err
+	}
+	return finishPickled(v)
+}
+
+// LoadPickle reads a pickle file, see Unpickle.
+func LoadPickle(path string) (interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Unpickle(f)
+}
+
+// pickleGlobal is a reference to a python callable, only the names listed in
+// pickleGlobals are ever created.
+type pickleGlobal struct {
+	module, name string
+}
+
+var pickleGlobals = map[string]string{
+	"numpy.core.multiarray._reconstruct":  "reconstruct",
+	"numpy._core.multiarray._reconstruct": "reconstruct",
+	"numpy.core.multiarray.ndarray":       "ndarray",
+	"numpy.ndarray":                       "ndarray",
+	"numpy.dtype":                         "dtype",
+	"numpy.core.multiarray.scalar":        "scalar",
+	"numpy._core.multiarray.scalar":       "scalar",
+	"numpy.core.numeric._frombuffer":      "frombuffer",
+	"numpy._core.numeric._frombuffer":     "frombuffer",
+	"_codecs.encode":                      "encode",
+	"builtins.bytearray":                  "bytearray",
+	"__builtin__.bytearray":               "bytearray",
+}
+
+func (g pickleGlobal) kind() string {
+	return pickleGlobals[g.module+"."+g.name]
+}
+
+// pickledDtype and pickledArray hold numpy objects while they are being built.
+type pickledDtype struct {
+	descr string
+	order byte
+}
+
+type pickledArray struct {
+	shape   []int
+	dtype   *pickledDtype
+	fortran bool
+	data    []byte
+	built   bool
+}
+
+// pickledList is a python list, lists are mutable and may be shared through
+// the memo so they are held by pointer until loading finishes.
+type pickledList struct {
+	items []interface{}
+}
+
+type unpickler struct {
+	r     *bufio.Reader
+	stack []interface{}
+	marks []int
+	memo  map[int]interface{}
+}
+
+func (u *unpickler) push(v interface{}) {
+	u.stack = append(u.stack, v)
+}
+
+// floor is the bottom of the stack an opcode may pop from, the last MARK.
+func (u *unpickler) floor() int {
+	if len(u.marks) == 0 {
+		return 0
+	}
+	return u.marks[len(u.marks)-1]
+}
+
+func (u *unpickler) pop() (interface{}, error) {
+	items, err := u.popN(1)
+	if err != nil {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// popN returns the top n items, in the order they were pushed.
+func (u *unpickler) popN(n int) ([]interface{}, error) {
+	if len(u.stack)-n < u.floor() {
+		return nil, fmt.Errorf("pickle: stack underflow")
+	}
+	items := append([]interface{}{}, u.stack[len(u.stack)-n:]...)
+	u.stack = u.stack[:len(u.stack)-n]
+	return items, nil
+}
+
+func (u *unpickler) top() (interface{}, error) {
+	if len(u.stack) == u.floor() {
+		return nil, fmt.Errorf("pickle: stack underflow")
+	}
+	return u.stack[len(u.stack)-1], nil
+}
+
+// popMark returns the items pushed since the last MARK.
+func (u *unpickler) popMark() ([]interface{}, error) {
+	if len(u.marks) == 0 {
+		return nil, fmt.Errorf("pickle: missing mark")
+	}
+	m := u.marks[len(u.marks)-1]
+	u.marks = u.marks[:len(u.marks)-1]
+	items := append([]interface{}{}, u.stack[m:]...)
+	u.stack = u.stack[:m]
+	return items, nil
+}
+
+// maxPickleLength caps the length prefix of a single string, bytes or long,
+// 4 GiB being the most the opcodes before protocol 4 can hold.
+const maxPickleLength = 1 << 32
+
+// pickleChunk is the most read allocates ahead of the bytes it has received.
+const pickleChunk = 1 << 20
+
+// read returns the next n bytes. n may come from the input, so the buffer
+// grows as the bytes arrive and a forged length fails as truncated input
+// instead of allocating it up front.
+func (u *unpickler) read(n int) ([]byte, error) {
+	buf := make([]byte, 0, min(n, pickleChunk))
+	for len(buf) < n {
+		k := min(n-len(buf), pickleChunk)
+		buf = slices.Grow(buf, k)[:len(buf)+k]
+		if _, err := io.ReadFull(u.r, buf[len(buf)-k:]); err != nil {
+			return nil, fmt.Errorf("pickle: truncated input: %w", err)
+		}
+	}
+	return buf, nil
+}
+
+// readLength reads the size byte length prefix of a value. BINSTRING and
+// LONG4 prefixes are signed.
+func (u *unpickler) readLength(size int, signed bool) (int, error) {
+	v, err := u.readUint(size)
+	if err != nil {
+		return 0, err
+	}
+	if signed && size == 4 && int32(v) < 0 {
+		return 0, fmt.Errorf("pickle: negative byte count %d", int32(v))
+	}
+	if v > maxPickleLength || v > math.MaxInt {
+		return 0, fmt.Errorf("pickle: byte count %d is too large", v)
+	}
+	return int(v), nil
+}
+
+func (u *unpickler) readUint(n int) (uint64, error) {
+	buf, err := u.read(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for i := n - 1; i >= 0; i-- {
+		v = v<<8 | uint64(buf[i])
+	}
+	return v, nil
+}
+
+func (u *unpickler) readLine() (string, error) {
+	line, err := u.r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("pickle: truncated input: %w", err)
+	}
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+func (u *unpickler) load() (interface{}, error) {
+	for {
+		op, err := u.r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("pickle: missing STOP opcode: %w", err)
+		}
+		switch op {
+		case '.': // STOP
+			return u.pop()
+		case 0x80: // PROTO
+			proto, err := u.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if proto > 5 {
+				return nil, fmt.Errorf("pickle: unsupported protocol %d", proto)
+			}
+		case 0x95: // FRAME
+			if _, err := u.readUint(8); err != nil {
+				return nil, err
+			}
+		case '(': // MARK
+			u.marks = append(u.marks, len(u.stack))
+		case '0': // POP
+			if _, err := u.pop(); err != nil {
+				return nil, err
+			}
+		case '1': // POP_MARK
+			if _, err := u.popMark(); err != nil {
+				return nil, err
+			}
+		case '2': // DUP
+			v, err := u.top()
+			if err != nil {
+				return nil, err
+			}
+			u.push(v)
+		case 'N': // NONE
+			u.push(nil)
+		case 0x88: // NEWTRUE
+			u.push(true)
+		case 0x89: // NEWFALSE
+			u.push(false)
+		case 'I': // INT
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			switch line {
+			case "01":
+				u.push(true)
+			case "00":
+				u.push(false)
+			default:
+				v, err := strconv.ParseInt(line, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("pickle: bad INT %q", line)
+				}
+				u.push(v)
+			}
+		case 'L': // LONG
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			v, err := strconv.ParseInt(strings.TrimSuffix(line, "L"), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("pickle: unsupported LONG %q", line)
+			}
+			u.push(v)
+		case 'J': // BININT
+			v, err := u.readUint(4)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(int32(v)))
+		case 'K': // BININT1
+			v, err := u.readUint(1)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(v))
+		case 'M': // BININT2
+			v, err := u.readUint(2)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(v))
+		case 0x8a, 0x8b: // LONG1, LONG4
+			size := 1
+			if op == 0x8b {
+				size = 4
+			}
+			n, err := u.readLength(size, op == 0x8b)
+			if err != nil {
+				return nil, err
+			}
+			buf, err := u.read(n)
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodePickleLong(buf)
+			if err != nil {
+				return nil, err
+			}
+			u.push(v)
+		case 'F': // FLOAT
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			v, err := strconv.ParseFloat(line, 64)
+			if err != nil {
+				return nil, fmt.Errorf("pickle: bad FLOAT %q", line)
+			}
+			u.push(v)
+		case 'G': // BINFLOAT
+			buf, err := u.read(8)
+			if err != nil {
+				return nil, err
+			}
+			u.push(math.Float64frombits(binary.BigEndian.Uint64(buf)))
+		case 'S': // STRING
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			v, err := strconv.Unquote(line)
+			if err != nil && len(line) >= 2 && line[0] == '\'' {
+				v, err = strconv.Unquote(`"` + strings.ReplaceAll(line[1:len(line)-1], `"`, `\"`) + `"`)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("pickle: bad STRING %q", line)
+			}
+			u.push(v)
+		case 'V': // UNICODE
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			u.push(decodeRawUnicode(line))
+		case 'T', 'X', 0x8d, 'U', 0x8c, 'B', 'C', 0x8e, 0x96:
+			// length prefixed strings, bytes and bytearrays
+			size := map[byte]int{'T': 4, 'X': 4, 0x8d: 8, 'U': 1, 0x8c: 1, 'B': 4, 'C': 1, 0x8e: 8, 0x96: 8}[op]
+			n, err := u.readLength(size, op == 'T')
+			if err != nil {
+				return nil, err
+			}
+			buf, err := u.read(n)
+			if err != nil {
+				return nil, err
+			}
+			switch op {
+			case 'B', 'C', 0x8e, 0x96:
+				u.push(buf)
+			default:
+				u.push(string(buf))
+			}
+		case 0x98: // READONLY_BUFFER
+		case ']': // EMPTY_LIST
+			u.push(&pickledList{})
+		case ')': // EMPTY_TUPLE
+			u.push([]interface{}{})
+		case '}': // EMPTY_DICT
+			u.push(map[interface{}]interface{}{})
+		case 'l': // LIST
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			u.push(&pickledList{items: items})
+		case 't': // TUPLE
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			u.push(items)
+		case 0x85, 0x86, 0x87: // TUPLE1, TUPLE2, TUPLE3
+			items, err := u.popN(int(op - 0x84))
+			if err != nil {
+				return nil, err
+			}
+			u.push(items)
+		case 'd': // DICT
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			d := map[interface{}]interface{}{}
+			if err := setItems(d, items); err != nil {
+				return nil, err
+			}
+			u.push(d)
+		case 'a': // APPEND
+			v, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			if err := u.appendItems([]interface{}{v}); err != nil {
+				return nil, err
+			}
+		case 'e': // APPENDS
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			if err := u.appendItems(items); err != nil {
+				return nil, err
+			}
+		case 's', 'u': // SETITEM, SETITEMS
+			var items []interface{}
+			if op == 's' {
+				items, err = u.popN(2)
+			} else {
+				items, err = u.popMark()
+			}
+			if err != nil {
+				return nil, err
+			}
+			top, err := u.top()
+			if err != nil {
+				return nil, err
+			}
+			d, ok := top.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("pickle: SETITEM on %T", top)
+			}
+			if err := setItems(d, items); err != nil {
+				return nil, err
+			}
+		case 'p': // PUT
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, fmt.Errorf("pickle: bad PUT %q", line)
+			}
+			if err := u.put(idx); err != nil {
+				return nil, err
+			}
+		case 'q', 'r': // BINPUT, LONG_BINPUT
+			size := 1
+			if op == 'r' {
+				size = 4
+			}
+			idx, err := u.readUint(size)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.put(int(idx)); err != nil {
+				return nil, err
+			}
+		case 0x94: // MEMOIZE
+			if err := u.put(len(u.memo)); err != nil {
+				return nil, err
+			}
+		case 'g', 'h', 'j': // GET, BINGET, LONG_BINGET
+			var idx int
+			switch op {
+			case 'g':
+				line, err := u.readLine()
+				if err != nil {
+					return nil, err
+				}
+				if idx, err = strconv.Atoi(line); err != nil {
+					return nil, fmt.Errorf("pickle: bad GET %q", line)
+				}
+			default:
+				size := 1
+				if op == 'j' {
+					size = 4
+				}
+				v, err := u.readUint(size)
+				if err != nil {
+					return nil, err
+				}
+				idx = int(v)
+			}
+			v, ok := u.memo[idx]
+			if !ok {
+				return nil, fmt.Errorf("pickle: memo key %d not found", idx)
+			}
+			u.push(v)
+		case 'c': // GLOBAL
+			module, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			name, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			if err := u.pushGlobal(module, name); err != nil {
+				return nil, err
+			}
+		case 0x93: // STACK_GLOBAL
+			name, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			module, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			ms, ok1 := module.(string)
+			ns, ok2 := name.(string)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("pickle: STACK_GLOBAL requires strings")
+			}
+			if err := u.pushGlobal(ms, ns); err != nil {
+				return nil, err
+			}
+		case 'R': // REDUCE
+			args, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			fn, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			v, err := reducePickled(fn, args)
+			if err != nil {
+				return nil, err
+			}
+			u.push(v)
+		case 'b': // BUILD
+			state, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			obj, err := u.top()
+			if err != nil {
+				return nil, err
+			}
+			if err := buildPickled(obj, state); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("pickle: unsupported opcode 0x%02x", op)
+		}
+	}
+}
+
+func (u *unpickler) put(idx int) error {
+	v, err := u.top()
+	if err != nil {
+		return err
+	}
+	u.memo[idx] = v
+	return nil
+}
+
+func (u *unpickler) pushGlobal(module, name string) error {
+	g := pickleGlobal{module: module, name: name}
+	if g.kind() == "" {
+		return fmt.Errorf("pickle: refusing to load global %s.%s", module, name)
+	}
+	u.push(g)
+	return nil
+}
+
+// appendItems extends the list on top of the stack.
+func (u *unpickler) appendItems(items []interface{}) error {
+	top, err := u.top()
+	if err != nil {
+		return err
+	}
+	list, ok := top.(*pickledList)
+	if !ok {
+		return fmt.Errorf("pickle: APPEND on %T", top)
+	}
+	list.items = append(list.items, items...)
+	return nil
+}
+
+func setItems(d map[interface{}]interface{}, items []interface{}) error {
+	if len(items)%2 != 0 {
+		return fmt.Errorf("pickle: odd number of dict items")
+	}
+	for i := 0; i < len(items); i += 2 {
+		switch items[i].(type) {
+		case nil, bool, int64, float64, string:
+		default:
+			return fmt.Errorf("pickle: unsupported dict key of type %T", items[i])
+		}
+		d[items[i]] = items[i+1]
+	}
+	return nil
+}
+
+// decodePickleLong decodes a little endian two's complement integer.
+func decodePickleLong(buf []byte) (int64, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	be := make([]byte, len(buf))
+	for i, b := range buf {
+		be[len(buf)-1-i] = b
+	}
+	v := new(big.Int).SetBytes(be)
+	if buf[len(buf)-1]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(buf))))
+	}
+	if !v.IsInt64() {
+		return 0, fmt.Errorf("pickle: integer %s does not fit in int64", v)
+	}
+	return v.Int64(), nil
+}
+
+// decodeRawUnicode undoes python's raw-unicode-escape encoding.
+func decodeRawUnicode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == 'u' || s[i+1] == 'U') {
+			n := 4
+			if s[i+1] == 'U' {
+				n = 8
+			}
+			if i+2+n <= len(s) {
+				if r, err := strconv.ParseUint(s[i+2:i+2+n], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 1 + n
+					continue
+				}
+			}
+		}
+		b.WriteRune(rune(s[i]))
+	}
+	return b.String()
+}
+
+// latin1 converts a string of code points below 256 back to the raw bytes.
+func latin1(s string) ([]byte, error) {
+	ret := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			return nil, fmt.Errorf("pickle: %q is not latin-1", s)
+		}
+		ret = append(ret, byte(r))
+	}
+	return ret, nil
+}
+
+func reducePickled(fn, args interface{}) (interface{}, error) {
+	g, ok := fn.(pickleGlobal)
+	if !ok {
+		return nil, fmt.Errorf("pickle: cannot call %T", fn)
+	}
+	argv, ok := args.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("pickle: %s.%s arguments are %T", g.module, g.name, args)
+	}
+	switch g.kind() {
+	case "reconstruct":
+		return &pickledArray{}, nil
+	case "dtype":
+		if len(argv) < 1 {
+			return nil, fmt.Errorf("pickle: dtype requires a descr")
+		}
+		descr, ok := argv[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("pickle: unsupported dtype %v", argv[0])
+		}
+		return &pickledDtype{descr: descr, order: '|'}, nil
+	case "encode":
+		if len(argv) < 1 {
+			return nil, fmt.Errorf("pickle: encode requires a string")
+		}
+		s, ok := argv[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("pickle: encode of %T", argv[0])
+		}
+		return latin1(s)
+	case "bytearray":
+		if len(argv) == 0 {
+			return []byte{}, nil
+		}
+		if b, ok := argv[0].([]byte); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("pickle: bytearray of %T", argv[0])
+	case "scalar":
+		if len(argv) != 2 {
+			return nil, fmt.Errorf("pickle: scalar requires a dtype and data")
+		}
+		dt, ok := argv[0].(*pickledDtype)
+		if !ok {
+			return nil, fmt.Errorf("pickle: scalar dtype is %T", argv[0])
+		}
+		a := &pickledArray{shape: []int{}, dtype: dt, built: true}
+		var err error
+		if a.data, err = pickledBytes(argv[1]); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case "frombuffer":
+		if len(argv) != 4 {
+			return nil, fmt.Errorf("pickle: _frombuffer requires 4 arguments")
+		}
+		data, err := pickledBytes(argv[0])
+		if err != nil {
+			return nil, err
+		}
+		dt, ok := argv[1].(*pickledDtype)
+		if !ok {
+			return nil, fmt.Errorf("pickle: _frombuffer dtype is %T", argv[1])
+		}
+		shape, err := pickledShape(argv[2])
+		if err != nil {
+			return nil, err
+		}
+		return &pickledArray{shape: shape, dtype: dt, data: data, fortran: argv[3] == "F", built: true}, nil
+	}
+	return nil, fmt.Errorf("pickle: cannot call %s.%s", g.module, g.name)
+}
+
+func buildPickled(obj, state interface{}) error {
+	st, ok := state.([]interface{})
+	if !ok {
+		return fmt.Errorf("pickle: unsupported state %T for %T", state, obj)
+	}
+	switch o := obj.(type) {
+	case *pickledDtype:
+		// (version, byteorder, subdtype, names, fields, elsize, alignment, flags)
+		if len(st) < 2 {
+			return fmt.Errorf("pickle: short dtype state")
+		}
+		order, ok := st[1].(string)
+		if !ok || len(order) != 1 {
+			return fmt.Errorf("pickle: bad dtype byte order %v", st[1])
+		}
+		o.order = order[0]
+		if len(st) > 3 && st[3] != nil {
+			return fmt.Errorf("pickle: structured dtypes are not supported")
+		}
+		return nil
+	case *pickledArray:
+		// (version, shape, dtype, is_fortran, data), older files omit version
+		if len(st) == 5 {
+			st = st[1:]
+		}
+		if len(st) != 4 {
+			return fmt.Errorf("pickle: bad ndarray state of length %d", len(st))
+		}
+		shape, err := pickledShape(st[0])
+		if err != nil {
+			return err
+		}
+		dt, ok := st[1].(*pickledDtype)
+		if !ok {
+			return fmt.Errorf("pickle: ndarray dtype is %T", st[1])
+		}
+		fortran, ok := st[2].(bool)
+		if !ok {
+			return fmt.Errorf("pickle: ndarray fortran flag is %T", st[2])
+		}
+		data, err := pickledBytes(st[3])
+		if err != nil {
+			return err
+		}
+		o.shape, o.dtype, o.fortran, o.data, o.built = shape, dt, fortran, data, true
+		return nil
+	}
+	return fmt.Errorf("pickle: cannot set state of %T", obj)
+}
+
+func pickledBytes(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		// python 2 str holds raw bytes
+		return []byte(t), nil
+	}
+	return nil, fmt.Errorf("pickle: array data of type %T is not supported", v)
+}
+
+func pickledShape(v interface{}) ([]int, error) {
+	dims, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("pickle: shape is %T", v)
+	}
+	shape := make([]int, len(dims))
+	for i, d := range dims {
+		n, ok := d.(int64)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("pickle: bad shape %v", dims)
+		}
+		shape[i] = int(n)
+	}
+	return shape, nil
+}
+
+// toNDArray decodes the raw buffer using the same dtype rules as .npy files.
+func (a *pickledArray) toNDArray() (NDArray, error) {
+	if !a.built {
+		return NDArray{}, fmt.Errorf("pickle: ndarray was never given its state")
+	}
+	order := string(a.dtype.order)
+	if order == "=" {
+		order = "<"
+	}
+	byteOrder, dtype, err := parseDescr(order + a.dtype.descr)
+	if err != nil {
+		return NDArray{}, fmt.Errorf("pickle: %w", err)
+	}
+	size, err := shapeSize(a.shape)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if size > math.MaxInt/dtype.ItemSize() || len(a.data) != size*dtype.ItemSize() {
+		return NDArray{}, fmt.Errorf("pickle: ndarray of shape %v has %d bytes of data", a.shape, len(a.data))
+	}
+	return decodeNpyData[float64](a.data, byteOrder, dtype, a.shape, a.fortran)
+}
+
+// finishPickled replaces the intermediate numpy objects with package types.
+// Containers shared through the memo are converted once, so they stay shared,
+// and a container that holds itself is rejected.
+func finishPickled(v interface{}) (interface{}, error) {
+	f := &pickleFinisher{done: map[pickleRef]interface{}{}, active: map[pickleRef]bool{}}
+	return f.finish(v)
+}
+
+// pickleRef identifies a mutable container by its address; slices also by
+// length, as a tuple and a list may share a backing array.
+type pickleRef struct {
+	ptr uintptr
+	n   int
+}
+
+type pickleFinisher struct {
+	done   map[pickleRef]interface{}
+	active map[pickleRef]bool
+}
+
+func (f *pickleFinisher) finish(v interface{}) (interface{}, error) {
+	var ref pickleRef
+	switch t := v.(type) {
+	case *pickledArray, *pickledList, map[interface{}]interface{}:
+		ref = pickleRef{ptr: reflect.ValueOf(v).Pointer(), n: -1}
+	case []interface{}:
+		if len(t) == 0 {
+			return t, nil
+		}
+		ref = pickleRef{ptr: reflect.ValueOf(v).Pointer(), n: len(t)}
+	default:
+		return f.convert(v)
+	}
+	if ret, ok := f.done[ref]; ok {
+		return ret, nil
+	}
+	if f.active[ref] {
+		return nil, fmt.Errorf("pickle: recursive containers are not supported")
+	}
+	f.active[ref] = true
+	ret, err := f.convert(v)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.active, ref)
+	f.done[ref] = ret
+	return ret, nil
+}
+
+func (f *pickleFinisher) convert(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case *pickledArray:
+		a, err := t.toNDArray()
+		if err != nil {
+			return nil, err
+		}
+		switch a.Ndim() {
+		case 0:
+			return a.data[0], nil
+		case 1:
+			return a.ToNpArray()
+		case 2:
+			return a.ToNpStack()
+		}
+		return a, nil
+	case *pickledDtype, pickleGlobal:
+		return nil, fmt.Errorf("pickle: unsupported value %T", v)
+	case *pickledList:
+		return f.finish(t.items)
+	case []interface{}:
+		ret := make([]interface{}, len(t))
+		for i, item := range t {
+			var err error
+			if ret[i], err = f.finish(item); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	case map[interface{}]interface{}:
+		ret := make(map[interface{}]interface{}, len(t))
+		for k, item := range t {
+			var err error
+			if ret[k], err = f.finish(item); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+	return v, nil
+}
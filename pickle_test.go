@@ -0,0 +1,149 @@
+package np
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testdata/pickles.py writes the fixtures with the same opcode streams
+// pickle.dump produces for numpy arrays.
+func TestLoadPickle_Protocols(t *testing.T) {
+	for _, name := range []string{"mnist1d_p0.pkl", "mnist1d_p2.pkl", "mnist1d_p4.pkl", "mnist1d_p5.pkl"} {
+		v, err := LoadPickle(filepath.Join("testdata", name))
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		d := v.(map[interface{}]interface{})
+		assert.Equal(t, NpStack{{1, 2, 3}, {4, 5, 6}}, d["x"], name)
+		assert.Equal(t, NpStack{{1, 2, 3}, {4, 5, 6}}, d["x_fortran"], name)
+		assert.Equal(t, NpArray{0, 1, -2}, d["y"], name)
+		assert.Equal(t, NpArray{1, 0}, d["mask"], name)
+		assert.Equal(t, NpArray{0.5, -1.5}, d["t"], name)
+		cube := d["cube"].(NDArray)
+		assert.Equal(t, []int{2, 1, 2}, cube.Shape(), name)
+		assert.Equal(t, []float64{1, 2, 3, 4}, cube.Flatten(), name)
+		templates := d["templates"].(map[interface{}]interface{})
+		assert.Equal(t, NpStack{{7, 8}}, templates["x"], name)
+		assert.Equal(t, []interface{}{int64(0), int64(1)}, templates["y"], name)
+		assert.Equal(t, "mnist1d", d["name"], name)
+		assert.Equal(t, int64(5000), d["n"], name)
+		assert.Equal(t, int64(1<<40), d["big"], name)
+		assert.Equal(t, 0.8, d["ratio"], name)
+		assert.Equal(t, []interface{}{int64(36), int64(60)}, d["padding"], name)
+		assert.Equal(t, []interface{}{true, false, nil}, d["flags"], name)
+		assert.Equal(t, []byte{0, 0xff}, d["raw"], name)
+	}
+}
+
+func TestUnpickle_ScalarsAndMemo(t *testing.T) {
+	// l = [1]; pickle.dumps([-5, 3.5, 'é', l, l], protocol=2)
+	data := []byte("\x80\x02]q\x00(J\xfb\xff\xff\xffG@\x0c\x00\x00\x00\x00\x00\x00X\x02\x00\x00\x00\xc3\xa9q\x01]q\x02K\x01ah\x02e.")
+	v, err := Unpickle(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(-5), 3.5, "é", []interface{}{int64(1)}, []interface{}{int64(1)}}, v)
+	// the shared list is converted once
+	items := v.([]interface{})
+	items[3].([]interface{})[0] = "changed"
+	assert.Equal(t, "changed", items[4].([]interface{})[0])
+}
+
+func TestUnpickle_Recursive(t *testing.T) {
+	for _, data := range []string{
+		"\x80\x02]q\x00h\x00a.",                        // l = []; l.append(l)
+		"\x80\x02}q\x00X\x01\x00\x00\x00aq\x01h\x00s.", // d = {}; d['a'] = d
+	} {
+		_, err := Unpickle(bytes.NewReader([]byte(data)))
+		assert.EqualError(t, err, "pickle: recursive containers are not supported", "%q", data)
+	}
+}
+
+func TestUnpickle_RejectsUnknownGlobals(t *testing.T) {
+	// pickle.dumps(os.system) style payload
+	data := []byte("cos\nsystem\n(S'echo hi'\ntR.")
+	_, err := Unpickle(bytes.NewReader(data))
+	assert.EqualError(t, err, "pickle: refusing to load global os.system")
+}
+
+func TestUnpickle_Errors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"\x80\x02K\x01",
+		"\x80\x06.",
+		"t.",
+		"\x80\x02K\x01K\x02K\x03s.",
+		"\x80\x02\x8a\x09\x00\x00\x00\x00\x00\x00\x00\x00\x01.",
+	} {
+		_, err := Unpickle(bytes.NewReader([]byte(data)))
+		assert.Error(t, err, "%q", data)
+	}
+}
+
+func TestUnpickle_Lengths(t *testing.T) {
+	for data, msg := range map[string]string{
+		// BINBYTES8 and BINUNICODE8 claiming 2**64-1 and 64 GiB
+		"\x80\x04\x8e\xff\xff\xff\xff\xff\xff\xff\xff": "pickle: byte count 18446744073709551615 is too large",
+		"\x80\x04\x8d\x00\x00\x00\x00\x10\x00\x00\x00": "pickle: byte count 68719476736 is too large",
+		// BINSTRING and LONG4 lengths are signed
+		"\x80\x02T\xff\xff\xff\xffabc.": "pickle: negative byte count -1",
+		"\x80\x02\x8b\x00\x00\x00\x80.": "pickle: negative byte count -2147483648",
+	} {
+		_, err := Unpickle(bytes.NewReader([]byte(data)))
+		assert.EqualError(t, err, msg, "%q", data)
+	}
+	// a length within the cap but beyond the input is truncated, without
+	// allocating the claimed size
+	data := []byte("\x80\x04\x8e\x00\x00\x00\x00\x01\x00\x00\x00abc")
+	allocs := testing.AllocsPerRun(1, func() {
+		_, err := Unpickle(bytes.NewReader(data))
+		assert.ErrorContains(t, err, "pickle: truncated input")
+	})
+	assert.Less(t, allocs, 100.0)
+}
+
+func pickleFixtures(t testing.TB) [][]byte {
+	var ret [][]byte
+	for _, name := range []string{"mnist1d_p0.pkl", "mnist1d_p2.pkl", "mnist1d_p4.pkl", "mnist1d_p5.pkl"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		assert.NoError(t, err)
+		ret = append(ret, data)
+	}
+	return ret
+}
+
+func TestUnpickle_Truncated(t *testing.T) {
+	for _, data := range pickleFixtures(t) {
+		for n := 0; n < len(data); n++ {
+			_, err := Unpickle(bytes.NewReader(data[:n]))
+			assert.Error(t, err, "%d of %d bytes", n, len(data))
+		}
+	}
+}
+
+func TestUnpickle_Mutated(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, data := range pickleFixtures(t) {
+		for i := 0; i < 2000; i++ {
+			b := append([]byte{}, data...)
+			for k := rnd.Intn(4); k >= 0; k-- {
+				b[rnd.Intn(len(b))] = byte(rnd.Intn(256))
+			}
+			assert.NotPanics(t, func() { _, _ = Unpickle(bytes.NewReader(b)) })
+		}
+	}
+}
+
+func FuzzUnpickle(f *testing.F) {
+	for _, data := range pickleFixtures(f) {
+		f.Add(data)
+	}
+	f.Add([]byte("\x80\x04\x8e\xff\xff\xff\xff\xff\xff\xff\xff"))
+	f.Add([]byte("\x80\x02T\xff\xff\xff\xffabc."))
+	f.Add([]byte("\x80\x02]q\x00h\x00a."))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = Unpickle(bytes.NewReader(data))
+	})
+}
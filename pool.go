@@ -0,0 +1,72 @@
+package np
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// Pool recycles the buffers of temporary arrays, so that a pipeline which
+// puts back what it gets runs in flat memory. Buffers are kept in size
+// classes of powers of two. The zero value is ready to use and a Pool is
+// safe for concurrent use.
+type Pool struct {
+	classes [bits.UintSize]sync.Pool
+	// headers recycles the *NpArray the classes hold, so that neither Get
+	// nor Put allocates once the pool is warm.
+	headers sync.Pool
+}
+
+// Get returns a zeroed array of length n, reusing a buffer put back earlier
+// when one is big enough.
+func (p *Pool) Get(n int) NpArray {
+	if n <= 0 {
+		return NpArray{}
+	}
+	c := bits.Len(uint(n - 1))
+	if h, ok := p.classes[c].Get().(*NpArray); ok {
+		a := (*h)[:n]
+		*h = nil
+		p.headers.Put(h)
+		clear(a)
+		return a
+	}
+	return make(NpArray, n, 1<<c)
+}
+
+// Put gives a back to the pool. Neither a nor anything sharing its memory
+// may be used afterwards.
+func (p *Pool) Put(a NpArray) {
+	if cap(a) == 0 {
+		return
+	}
+	// a can serve any request up to the power of two below its capacity
+	c := bits.Len(uint(cap(a))) - 1
+	h, ok := p.headers.Get().(*NpArray)
+	if !ok {
+		h = new(NpArray)
+	}
+	*h = a[:0]
+	p.classes[c].Put(h)
+}
+
+// GetStack returns a zeroed stack of rows arrays of length cols.
+func (p *Pool) GetStack(rows, cols int) NpStack {
+	m := make(NpStack, rows)
+	for i := range m {
+		m[i] = p.Get(cols)
+	}
+	return m
+}
+
+// PutStack gives every row of m back to the pool.
+func (p *Pool) PutStack(m NpStack) {
+	for _, a := range m {
+		p.Put(a)
+	}
+}
+
+// scratch holds the package's own temporary buffers.
+var scratch Pool
+
+// headerScratch holds *NpStack row headers for reordering a stack in place.
+var headerScratch sync.Pool
@@ -0,0 +1,42 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	var p Pool
+	a := p.Get(5)
+	assert.Equal(t, NpArray{0, 0, 0, 0, 0}, a)
+	assert.Equal(t, 8, cap(a))
+	a[0] = 7
+	p.Put(a)
+	// a reused buffer comes back zeroed
+	b := p.Get(6)
+	assert.Equal(t, NpArray{0, 0, 0, 0, 0, 0}, b)
+	assert.GreaterOrEqual(t, cap(b), 6)
+	assert.Equal(t, NpArray{}, p.Get(0))
+	p.Put(nil)
+	// a buffer is only reused for requests it can hold
+	p.Put(make(NpArray, 3))
+	assert.Len(t, p.Get(4), 4)
+
+	m := p.GetStack(3, 4)
+	assert.Len(t, m, 3)
+	for _, row := range m {
+		assert.Len(t, row, 4)
+	}
+	p.PutStack(m)
+}
+
+func TestPool_Allocs(t *testing.T) {
+	var p Pool
+	p.Put(p.Get(100))
+	allocs := testing.AllocsPerRun(100, func() {
+		a := p.Get(100)
+		SubFloat64Into(a, a, 1)
+		p.Put(a)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
@@ -0,0 +1,263 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// QuantileMethod selects how quantiles between two samples are estimated,
+// the method argument of numpy.quantile. The zero value is numpy's default.
+type QuantileMethod int
+
+const (
+	// QuantileLinear interpolates linearly at (n-1)q, Hyndman and Fan's
+	// method 7.
+	QuantileLinear QuantileMethod = iota
+	// QuantileLower, QuantileHigher, QuantileNearest and QuantileMidpoint
+	// take the sample below or above (n-1)q, the nearer one (ties to even)
+	// or their average.
+	QuantileLower
+	QuantileHigher
+	QuantileNearest
+	QuantileMidpoint
+	// QuantileInvertedCDF, QuantileAveragedInvertedCDF and
+	// QuantileClosestObservation are Hyndman and Fan's discontinuous methods
+	// 1 to 3.
+	QuantileInvertedCDF
+	QuantileAveragedInvertedCDF
+	QuantileClosestObservation
+	// QuantileInterpolatedInvertedCDF, QuantileHazen, QuantileWeibull,
+	// QuantileMedianUnbiased and QuantileNormalUnbiased are Hyndman and Fan's
+	// continuous methods 4, 5, 6, 8 and 9.
+	QuantileInterpolatedInvertedCDF
+	QuantileHazen
+	QuantileWeibull
+	QuantileMedianUnbiased
+	QuantileNormalUnbiased
+)
+
+var quantileMethodNames = [...]string{
+	QuantileLinear:                  "linear",
+	QuantileLower:                   "lower",
+	QuantileHigher:                  "higher",
+	QuantileNearest:                 "nearest",
+	QuantileMidpoint:                "midpoint",
+	QuantileInvertedCDF:             "inverted_cdf",
+	QuantileAveragedInvertedCDF:     "averaged_inverted_cdf",
+	QuantileClosestObservation:      "closest_observation",
+	QuantileInterpolatedInvertedCDF: "interpolated_inverted_cdf",
+	QuantileHazen:                   "hazen",
+	QuantileWeibull:                 "weibull",
+	QuantileMedianUnbiased:          "median_unbiased",
+	QuantileNormalUnbiased:          "normal_unbiased",
+}
+
+func (m QuantileMethod) String() string {
+	if m >= 0 && int(m) < len(quantileMethodNames) {
+		return quantileMethodNames[m]
+	}
+	return fmt.Sprintf("QuantileMethod(%d)", int(m))
+}
+
+// checkQuantile validates q in [0, 1] and the method.
+func checkQuantile(q float64, method QuantileMethod) error {
+	if !(q >= 0 && q <= 1) {
+		return fmt.Errorf("quantiles must be in the range [0, 1]")
+	}
+	if method < 0 || int(method) >= len(quantileMethodNames) {
+		return fmt.Errorf("%v is not a valid method", method)
+	}
+	return nil
+}
+
+// quantileSorted returns the q-th quantile of the sorted, NaN free values,
+// following numpy's _quantile for each method.
+func quantileSorted(sorted NpArray, q float64, method QuantileMethod) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return math.NaN()
+	}
+	nf := float64(n)
+	// the methods that pick a sample
+	switch method {
+	case QuantileLower:
+		return sorted[int(math.Floor((nf-1)*q))]
+	case QuantileHigher:
+		return sorted[int(math.Ceil((nf-1)*q))]
+	case QuantileNearest:
+		return sorted[int(math.RoundToEven((nf-1)*q))]
+	case QuantileInvertedCDF:
+		return sorted[discreteIndex(nf*q-1, n, func(gamma, _ float64) bool {
+			return gamma == 0
+		})]
+	case QuantileClosestObservation:
+		// the nearest even order statistic, which is odd when zero based
+		return sorted[discreteIndex(nf*q-1-0.5, n, func(gamma, index float64) bool {
+			return gamma == 0 && math.Mod(math.Floor(index), 2) == 1
+		})]
+	}
+	var virtual float64
+	switch method {
+	case QuantileLinear:
+		virtual = (nf - 1) * q
+	case QuantileMidpoint:
+		virtual = 0.5 * (math.Floor((nf-1)*q) + math.Ceil((nf-1)*q))
+	case QuantileAveragedInvertedCDF:
+		virtual = nf*q - 1
+	case QuantileInterpolatedInvertedCDF:
+		virtual = virtualIndex(nf, q, 0, 1)
+	case QuantileHazen:
+		virtual = virtualIndex(nf, q, 0.5, 0.5)
+	case QuantileWeibull:
+		virtual = virtualIndex(nf, q, 0, 0)
+	case QuantileMedianUnbiased:
+		virtual = virtualIndex(nf, q, 1/3.0, 1/3.0)
+	case QuantileNormalUnbiased:
+		virtual = virtualIndex(nf, q, 3/8.0, 3/8.0)
+	}
+	prev, next := quantileIndexes(virtual, n)
+	gamma := virtual - math.Floor(virtual)
+	switch method {
+	case QuantileAveragedInvertedCDF:
+		gamma = 1
+		if virtual == math.Floor(virtual) {
+			gamma = 0.5
+		}
+	case QuantileMidpoint:
+		gamma = 0.5
+		if virtual == math.Floor(virtual) {
+			gamma = 0
+		}
+	}
+	return lerp(sorted[prev], sorted[next], gamma)
+}
+
+// virtualIndex places the quantile for the plotting positions alpha and
+// beta, numpy's _compute_virtual_index.
+func virtualIndex(n, q, alpha, beta float64) float64 {
+	return n*q + (alpha + q*(1-alpha-beta)) - 1
+}
+
+// discreteIndex rounds a virtual index down when previous holds and up
+// otherwise, clipped to the data.
+func discreteIndex(index float64, n int, previous func(gamma, index float64) bool) int {
+	prev := math.Floor(index)
+	ret := int(prev) + 1
+	if previous(index-prev, index) {
+		ret = int(prev)
+	}
+	if ret < 0 {
+		ret = 0
+	}
+	if ret > n-1 {
+		ret = n - 1
+	}
+	return ret
+}
+
+// quantileIndexes returns the samples either side of a virtual index,
+// clamped to the data as numpy's _get_indexes does.
+func quantileIndexes(virtual float64, n int) (int, int) {
+	switch {
+	case virtual >= float64(n-1):
+		return n - 1, n - 1
+	case virtual < 0:
+		return 0, 0
+	}
+	prev := int(math.Floor(virtual))
+	return prev, prev + 1
+}
+
+// lerp interpolates from a to b as numpy's _lerp does, working back from b
+// for t >= 0.5 so that t = 1 gives b exactly.
+func lerp(a, b, t float64) float64 {
+	diff := b - a
+	if t >= 0.5 {
+		return b - diff*(1-t)
+	}
+	return a + diff*t
+}
+
+// medianSorted returns the median of sorted, NaN free values, averaging the
+// middle two as numpy.median does rather than interpolating.
+func medianSorted(sorted NpArray) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return math.NaN()
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// quantileLine returns the quantile of a line, NaN if it holds a NaN.
+func quantileLine(line NpArray, q float64, method QuantileMethod) float64 {
+	sorted := line.Copy()
+	for _, v := range sorted {
+		if math.IsNaN(v) {
+			return math.NaN()
+		}
+	}
+	sort.Float64s(sorted)
+	return quantileSorted(sorted, q, method)
+}
+
+// Quantile returns the q-th quantile, q in [0, 1], of a along axis, like
+// numpy.quantile. Slices holding NaN give NaN.
+func Quantile(a interface{}, q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	if err := checkQuantile(q, method); err != nil {
+		return NDArray{}, err
+	}
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return quantileLine(line, q, method)
+	})
+}
+
+// Percentile returns the q-th percentile, q in [0, 100], of a along axis,
+// like numpy.percentile.
+func Percentile(a interface{}, q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	if !(q >= 0 && q <= 100) {
+		return NDArray{}, fmt.Errorf("percentiles must be in the range [0, 100]")
+	}
+	return Quantile(a, q/100, axis, method, keepdims)
+}
+
+// Median returns the median of a along axis, like numpy.median. Slices
+// holding NaN give NaN.
+func Median(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		sorted := line.Copy()
+		for _, v := range sorted {
+			if math.IsNaN(v) {
+				return math.NaN()
+			}
+		}
+		sort.Float64s(sorted)
+		return medianSorted(sorted)
+	})
+}
+
+// Quantile returns the q-th quantile of the stack along axis.
+func (m NpStack) Quantile(q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	return Quantile(m, q, axis, method, keepdims)
+}
+
+// Percentile returns the q-th percentile of the stack along axis.
+func (m NpStack) Percentile(q float64, axis int, method QuantileMethod, keepdims bool) (NDArray, error) {
+	return Percentile(m, q, axis, method, keepdims)
+}
+
+// Median returns the median of the stack along axis.
+func (m NpStack) Median(axis int, keepdims bool) (NDArray, error) {
+	return Median(m, axis, keepdims)
+}
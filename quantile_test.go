@@ -0,0 +1,81 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestQuantileMethods(t *testing.T) {
+	a := NpArray{3, 1, 4, 1, 5, 9, 2, 6}
+	// Hyndman and Fan's definitions evaluated directly
+	expected := map[QuantileMethod][2]float64{
+		QuantileInvertedCDF:             {1, 6},
+		QuantileAveragedInvertedCDF:     {1.5, 6},
+		QuantileClosestObservation:      {1, 5},
+		QuantileInterpolatedInvertedCDF: {1, 5.4},
+		QuantileHazen:                   {1.5, 5.9},
+		QuantileWeibull:                 {1.25, 6.6},
+		QuantileLinear:                  {1.75, 5.6},
+		QuantileMedianUnbiased:          {1.4166666666666665, 6},
+		QuantileNormalUnbiased:          {1.4375, 5.975},
+		QuantileLower:                   {1, 5},
+		QuantileHigher:                  {2, 6},
+		QuantileNearest:                 {2, 6},
+		QuantileMidpoint:                {1.5, 5.5},
+	}
+	for method, want := range expected {
+		for i, q := range []float64{0.25, 0.8} {
+			ret, err := Quantile(a, q, 0, method, false)
+			assert.NoError(t, err)
+			assert.InDelta(t, want[i], ret.Flatten()[0], 1e-12, "%v at %v", method, q)
+		}
+	}
+
+	// nearest rounds half way to even, (n-1)q = 3.5 picks index 4
+	ret, err := Quantile(a, 0.5, 0, QuantileNearest, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4}, ret.Flatten())
+	ret, err = Percentile(a, 50, 0, QuantileMidpoint, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3.5}, ret.Flatten())
+
+	assert.Equal(t, "median_unbiased", QuantileMedianUnbiased.String())
+	_, err = Quantile(a, 0.5, 0, QuantileMethod(99), false)
+	assert.Error(t, err)
+	_, err = Percentile(a, -1, 0, QuantileLinear, false)
+	assert.Error(t, err)
+}
+
+func TestPercentileMedianAxis(t *testing.T) {
+	// the numpy.percentile examples
+	m := NpStack{{10, 7, 4}, {3, 2, 1}}
+	ret, err := m.Percentile(50, AxisNone, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3.5}, ret.Flatten())
+	ret, err = m.Percentile(50, 0, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6.5, 4.5, 2.5}, ret.Flatten())
+	ret, err = m.Percentile(50, 1, QuantileLinear, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, ret.Shape())
+	assert.Equal(t, []float64{7, 2}, ret.Flatten())
+
+	ret, err = m.Median(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{6.5, 4.5, 2.5}, ret.Flatten())
+	ret, err = Median(m, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{7, 2}, ret.Flatten())
+	ret, err = m.Quantile(0.5, AxisNone, QuantileLower, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3}, ret.Flatten())
+
+	// NaN propagates, unlike NanMedian
+	ret, err = Median(NpArray{1, math.NaN(), 3}, 0, false)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret.Flatten()[0]))
+	ret, err = Quantile(NpArray{1, math.NaN(), 3}, 0.5, 0, QuantileLinear, false)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret.Flatten()[0]))
+}
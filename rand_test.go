@@ -5,7 +5,7 @@ import (
 	"github.com/pa-m/randomkit"
 )
 
-func ExampleRandom() {
+func Example_random() {
 	rnd := randomkit.RKState{}
 	rnd.Seed(42)
 	for i := 1; i < 10; i++ {
@@ -24,7 +24,7 @@ func ExampleRandom() {
 
 }
 
-func ExampleRandomNormal() {
+func Example_randomNormal() {
 	rnd := randomkit.RKState{}
 	rnd.Seed(42)
 	for i := 1; i < 10; i++ {
@@ -42,7 +42,7 @@ func ExampleRandomNormal() {
 	// -0.4694743859349521
 }
 
-func ExampleTestRandN() {
+func ExampleRandN_first() {
 	rnd := randomkit.RKState{}
 	rnd.Seed(42)
 	fmt.Println(RandN(&rnd, 10))
@@ -69,7 +69,7 @@ func ExampleRandN() {
 	// true
 }
 
-func ExampleRandomChoice() {
+func ExampleRandChoice() {
 	rnd := randomkit.RKState{}
 	rnd.Seed(42)
 	for i := 0; i < 10; i++ {
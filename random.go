@@ -0,0 +1,626 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pa-m/randomkit"
+)
+
+// RandomState reproduces the streams of numpy.random.RandomState(seed), the
+// legacy MT19937 generator, using the same algorithms numpy uses for each
+// distribution. The logarithms, exponentials and powers in them are ports
+// of glibc's, so they round as numpy's do on Linux.
+// Invalid parameters panic, as they raise in numpy.
+type RandomState struct {
+	rk       *randomkit.RKState
+	binomial binomialState
+	// gauss holds the second normal of the last pair when hasGauss is set.
+	hasGauss bool
+	gauss    float64
+}
+
+// binomialState caches the set up of the last binomial draw, as numpy does.
+type binomialState struct {
+	has                            bool
+	n                              int64
+	p                              float64
+	q, r, c                        float64
+	m                              int64
+	fm, p1, xm, xl, xr, laml, lamr float64
+	p2, p3, p4                     float64
+}
+
+func NewRandomState(seed uint64) *RandomState {
+	return &RandomState{rk: randomkit.NewRandomkitSource(seed)}
+}
+
+func (r *RandomState) Seed(seed uint64) {
+	r.rk.Seed(seed)
+	r.binomial = binomialState{}
+	r.hasGauss, r.gauss = false, 0
+}
+
+// RKState returns the underlying generator for use with RandN and RandChoice.
+// Its own NormFloat64 keeps a separate spare normal and rounds as Go's
+// math.Log does, so normals should come from the RandomState.
+func (r *RandomState) RKState() *randomkit.RKState {
+	return r.rk
+}
+
+// RandomSample returns a float64 in [0, 1), like random_sample().
+func (r *RandomState) RandomSample() float64 {
+	return r.rk.Float64()
+}
+
+// Rand returns n samples in [0, 1), like rand(n).
+func (r *RandomState) Rand(n int) NpArray {
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = r.rk.Float64()
+	}
+	return ret
+}
+
+// StandardNormal returns a standard normal sample by the polar method,
+// keeping the second of each pair for the next call, as numpy's legacy
+// gauss does.
+func (r *RandomState) StandardNormal() float64 {
+	if r.hasGauss {
+		r.hasGauss = false
+		return r.gauss
+	}
+	for {
+		x1 := 2.0*r.rk.Float64() - 1.0
+		x2 := 2.0*r.rk.Float64() - 1.0
+		// the conversions keep the products from being fused
+		r2 := float64(x1*x1) + float64(x2*x2)
+		if r2 >= 1.0 || r2 == 0.0 {
+			continue
+		}
+		f := math.Sqrt(-2.0 * logLibm(r2) / r2)
+		r.gauss, r.hasGauss = f*x1, true
+		return f * x2
+	}
+}
+
+// RandN returns n standard normal samples, like randn(n).
+func (r *RandomState) RandN(n int) NpArray {
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = r.StandardNormal()
+	}
+	return ret
+}
+
+func (r *RandomState) Normal(loc, scale float64, n int) NpArray {
+	if scale < 0 {
+		panic(fmt.Errorf("scale < 0"))
+	}
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = loc + scale*r.StandardNormal()
+	}
+	return ret
+}
+
+func (r *RandomState) Uniform(low, high float64, n int) NpArray {
+	ret := make(NpArray, n)
+	for i := range ret {
+		ret[i] = low + (high-low)*r.rk.Float64()
+	}
+	return ret
+}
+
+// RandInt returns n integers in [low, high), like randint(low, high, n).
+func (r *RandomState) RandInt(low, high, n int) []int {
+	if low >= high {
+		panic(fmt.Errorf("low >= high"))
+	}
+	rng := uint64(high - low - 1)
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = low + int(r.maskedInterval(rng))
+	}
+	return ret
+}
+
+// maskedInterval draws from [0, max] by rejection sampling on the smallest
+// bit mask covering max, numpy's random_interval.
+func (r *RandomState) maskedInterval(max uint64) uint64 {
+	if max == 0 {
+		return 0
+	}
+	mask := uint64(RandBItMask(int(max)))
+	if max <= 0xffffffff {
+		for {
+			if v := uint64(r.rk.Uint32()) & mask; v <= max {
+				return v
+			}
+		}
+	}
+	for {
+		if v := r.rk.Uint64() & mask; v <= max {
+			return v
+		}
+	}
+}
+
+// Shuffle shuffles a in place, like shuffle(a).
+func (r *RandomState) Shuffle(a NpArray) {
+	for i := len(a) - 1; i > 0; i-- {
+		j := r.maskedInterval(uint64(i))
+		a[i], a[j] = a[j], a[i]
+	}
+}
+
+// Permutation returns a shuffled range of n integers, like permutation(n).
+func (r *RandomState) Permutation(n int) []int {
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := r.maskedInterval(uint64(i))
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret
+}
+
+// Choice draws size indices from range(a), like choice(a, size, replace, p).
+// p may be nil for a uniform choice.
+func (r *RandomState) Choice(a, size int, replace bool, p NpArray) []int {
+	if a <= 0 {
+		panic(fmt.Errorf("a must be greater than 0 unless no samples are taken"))
+	}
+	if p != nil {
+		if len(p) != a {
+			panic(fmt.Errorf("a and p must have same size"))
+		}
+		sum, comp := 0.0, 0.0
+		for _, v := range p {
+			if v < 0 || math.IsNaN(v) {
+				panic(fmt.Errorf("probabilities are not non-negative"))
+			}
+			// Kahan summation, as numpy checks the total
+			y := v - comp
+			t := sum + y
+			comp = (t - sum) - y
+			sum = t
+		}
+		if math.Abs(sum-1) > math.Sqrt(2.220446049250313e-16) {
+			panic(fmt.Errorf("probabilities do not sum to 1"))
+		}
+	}
+	if !replace && size > a {
+		panic(fmt.Errorf("cannot take a larger sample than population when replace is false"))
+	}
+	switch {
+	case replace && p == nil:
+		return r.RandInt(0, a, size)
+	case replace:
+		return searchCDF(cumulative(p), r.Rand(size))
+	case p == nil:
+		return r.Permutation(a)[:size]
+	}
+	nonzero := 0
+	for _, v := range p {
+		if v > 0 {
+			nonzero++
+		}
+	}
+	if nonzero < size {
+		panic(fmt.Errorf("fewer non-zero entries in p than size"))
+	}
+	found := make([]int, 0, size)
+	p = append(NpArray(nil), p...)
+	for len(found) < size {
+		x := r.Rand(size - len(found))
+		for _, idx := range found {
+			p[idx] = 0
+		}
+		// keep the first occurrence of each new index, in draw order
+		seen := map[int]bool{}
+		for _, idx := range searchCDF(cumulative(p), x) {
+			if !seen[idx] {
+				seen[idx] = true
+				found = append(found, idx)
+			}
+		}
+	}
+	return found
+}
+
+// cumulative returns the normalised running sum of p.
+func cumulative(p NpArray) NpArray {
+	cdf := make(NpArray, len(p))
+	sum := 0.0
+	for i, v := range p {
+		sum += v
+		cdf[i] = sum
+	}
+	total := cdf[len(cdf)-1]
+	for i := range cdf {
+		cdf[i] /= total
+	}
+	return cdf
+}
+
+// searchCDF finds, for each sample, the first cdf entry greater than it,
+// like cdf.searchsorted(x, side='right').
+func searchCDF(cdf, x NpArray) []int {
+	ret := make([]int, len(x))
+	for i, v := range x {
+		ret[i] = sort.Search(len(cdf), func(j int) bool { return cdf[j] > v })
+	}
+	return ret
+}
+
+func (r *RandomState) StandardExponential() float64 {
+	return -logLibm(1.0 - r.rk.Float64())
+}
+
+func (r *RandomState) Exponential(scale float64) float64 {
+	if scale < 0 {
+		panic(fmt.Errorf("scale < 0"))
+	}
+	return scale * r.StandardExponential()
+}
+
+// StandardGamma uses Marsaglia and Tsang's method for shape >= 1 and
+// rejection from a Weibull for shape < 1, as the legacy generator does.
+func (r *RandomState) StandardGamma(shape float64) float64 {
+	if shape < 0 {
+		panic(fmt.Errorf("shape < 0"))
+	}
+	switch {
+	case shape == 1.0:
+		return r.StandardExponential()
+	case shape == 0.0:
+		return 0.0
+	case shape < 1.0:
+		for {
+			u := r.rk.Float64()
+			v := r.StandardExponential()
+			if u <= 1.0-shape {
+				x := powLibm(u, 1.0/shape)
+				if x <= v {
+					return x
+				}
+			} else {
+				y := -logLibm((1 - u) / shape)
+				x := powLibm(1.0-shape+shape*y, 1.0/shape)
+				if x <= v+y {
+					return x
+				}
+			}
+		}
+	}
+	b := shape - 1.0/3.0
+	c := 1.0 / math.Sqrt(9*b)
+	for {
+		var x, v float64
+		for {
+			x = r.StandardNormal()
+			v = 1.0 + c*x
+			if v > 0.0 {
+				break
+			}
+		}
+		v = v * v * v
+		u := r.rk.Float64()
+		if u < 1.0-0.0331*(x*x)*(x*x) {
+			return b * v
+		}
+		if logLibm(u) < 0.5*x*x+b*(1.0-v+logLibm(v)) {
+			return b * v
+		}
+	}
+}
+
+func (r *RandomState) Gamma(shape, scale float64) float64 {
+	if scale < 0 {
+		panic(fmt.Errorf("scale < 0"))
+	}
+	return scale * r.StandardGamma(shape)
+}
+
+func (r *RandomState) ChiSquare(df float64) float64 {
+	if df <= 0 {
+		panic(fmt.Errorf("df <= 0"))
+	}
+	return 2.0 * r.StandardGamma(df/2.0)
+}
+
+func (r *RandomState) LogNormal(mean, sigma float64) float64 {
+	if sigma < 0 {
+		panic(fmt.Errorf("sigma < 0"))
+	}
+	return expLibm(mean + sigma*r.StandardNormal())
+}
+
+// Beta uses Johnk's algorithm when both parameters are at most one and a
+// ratio of gammas otherwise.
+func (r *RandomState) Beta(a, b float64) float64 {
+	if a <= 0 || b <= 0 {
+		panic(fmt.Errorf("a <= 0 or b <= 0"))
+	}
+	if a > 1.0 || b > 1.0 {
+		ga := r.StandardGamma(a)
+		gb := r.StandardGamma(b)
+		return ga / (ga + gb)
+	}
+	for {
+		u := r.rk.Float64()
+		v := r.rk.Float64()
+		x := powLibm(u, 1.0/a)
+		y := powLibm(v, 1.0/b)
+		if x+y <= 1.0 {
+			if x+y > 0 {
+				return x / (x + y)
+			}
+			logX := logLibm(u) / a
+			logY := logLibm(v) / b
+			logM := math.Max(logX, logY)
+			logX -= logM
+			logY -= logM
+			return expLibm(logX - logLibm(expLibm(logX)+expLibm(logY)))
+		}
+	}
+}
+
+// Poisson uses multiplication of uniforms for small lam and the PTRS
+// transformed rejection method for lam >= 10.
+func (r *RandomState) Poisson(lam float64) int64 {
+	if lam < 0 || math.IsNaN(lam) {
+		panic(fmt.Errorf("lam < 0 or lam is NaN"))
+	}
+	if lam > 9.223372006484771e+18 {
+		panic(fmt.Errorf("lam value too large"))
+	}
+	if lam >= 10 {
+		return r.poissonPTRS(lam)
+	}
+	if lam == 0 {
+		return 0
+	}
+	enlam := expLibm(-lam)
+	x := int64(0)
+	prod := 1.0
+	for {
+		prod *= r.rk.Float64()
+		if prod <= enlam {
+			return x
+		}
+		x++
+	}
+}
+
+func (r *RandomState) poissonPTRS(lam float64) int64 {
+	slam := math.Sqrt(lam)
+	loglam := logLibm(lam)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invalpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := r.rk.Float64() - 0.5
+		v := r.rk.Float64()
+		us := 0.5 - math.Abs(u)
+		k := int64(math.Floor((2*a/us+b)*u + lam + 0.43))
+		if us >= 0.07 && v <= vr {
+			return k
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		if logLibm(v)+logLibm(invalpha)-logLibm(a/(us*us)+b) <= -lam+float64(k)*loglam-logGamma(float64(k+1)) {
+			return k
+		}
+	}
+}
+
+// logGamma is numpy's random_loggam, kept separate from math.Lgamma so the
+// rejection decisions match bit for bit.
+func logGamma(x float64) float64 {
+	a := [10]float64{
+		8.333333333333333e-02, -2.777777777777778e-03,
+		7.936507936507937e-04, -5.952380952380952e-04,
+		8.417508417508418e-04, -1.917526917526918e-03,
+		6.410256410256410e-03, -2.955065359477124e-02,
+		1.796443723688307e-01, -1.39243221690590e+00,
+	}
+	if x == 1.0 || x == 2.0 {
+		return 0.0
+	}
+	n := 0
+	if x < 7.0 {
+		n = int(7 - x)
+	}
+	x0 := x + float64(n)
+	x2 := (1.0 / x0) * (1.0 / x0)
+	gl0 := a[9]
+	for k := 8; k >= 0; k-- {
+		gl0 *= x2
+		gl0 += a[k]
+	}
+	gl := gl0/x0 + 0.5*1.8378770664093453e+00 + (x0-0.5)*logLibm(x0) - x0
+	if x < 7.0 {
+		for k := 1; k <= n; k++ {
+			gl -= logLibm(x0 - 1.0)
+			x0 -= 1.0
+		}
+	}
+	return gl
+}
+
+// Binomial uses inversion when n*min(p, 1-p) <= 30 and the BTPE algorithm
+// otherwise.
+func (r *RandomState) Binomial(n int64, p float64) int64 {
+	if n < 0 {
+		panic(fmt.Errorf("n < 0"))
+	}
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		panic(fmt.Errorf("p < 0, p > 1 or p is NaN"))
+	}
+	if n == 0 || p == 0 {
+		return 0
+	}
+	if p <= 0.5 {
+		if p*float64(n) <= 30.0 {
+			return r.binomialInversion(n, p)
+		}
+		return r.binomialBTPE(n, p)
+	}
+	q := 1.0 - p
+	if q*float64(n) <= 30.0 {
+		return n - r.binomialInversion(n, q)
+	}
+	return n - r.binomialBTPE(n, q)
+}
+
+func (r *RandomState) binomialInversion(n int64, p float64) int64 {
+	s := &r.binomial
+	if !s.has || s.n != n || s.p != p {
+		s.n, s.p, s.has = n, p, true
+		s.q = 1.0 - p
+		s.r = expLibm(float64(n) * logLibm(s.q))
+		s.c = float64(n) * p
+		s.m = int64(math.Min(float64(n), s.c+10.0*math.Sqrt(s.c*s.q+1)))
+	}
+	q, qn, bound := s.q, s.r, s.m
+	x := int64(0)
+	px := qn
+	u := r.rk.Float64()
+	for u > px {
+		x++
+		if x > bound {
+			x = 0
+			px = qn
+			u = r.rk.Float64()
+		} else {
+			u -= px
+			px = (float64(n-x+1) * p * px) / (float64(x) * q)
+		}
+	}
+	return x
+}
+
+func (r *RandomState) binomialBTPE(n int64, p float64) int64 {
+	s := &r.binomial
+	if !s.has || s.n != n || s.p != p {
+		s.n, s.p, s.has = n, p, true
+		s.r = math.Min(p, 1.0-p)
+		s.q = 1.0 - s.r
+		s.fm = float64(n)*s.r + s.r
+		s.m = int64(math.Floor(s.fm))
+		s.p1 = math.Floor(2.195*math.Sqrt(float64(n)*s.r*s.q)-4.6*s.q) + 0.5
+		s.xm = float64(s.m) + 0.5
+		s.xl = s.xm - s.p1
+		s.xr = s.xm + s.p1
+		s.c = 0.134 + 20.5/(15.3+float64(s.m))
+		a := (s.fm - s.xl) / (s.fm - s.xl*s.r)
+		s.laml = a * (1.0 + a/2.0)
+		a = (s.xr - s.fm) / (s.xr * s.q)
+		s.lamr = a * (1.0 + a/2.0)
+		s.p2 = s.p1 * (1.0 + 2.0*s.c)
+		s.p3 = s.p2 + s.c/s.laml
+		s.p4 = s.p3 + s.c/s.lamr
+	}
+	rr, q, m := s.r, s.q, s.m
+	p1, xm, xl, xr, c := s.p1, s.xm, s.xl, s.xr, s.c
+	laml, lamr, p2, p3, p4 := s.laml, s.lamr, s.p2, s.p3, s.p4
+	nf := float64(n)
+	nrq := nf * rr * q
+	var y int64
+	for {
+		// Step 10
+		u := r.rk.Float64() * p4
+		v := r.rk.Float64()
+		if u <= p1 {
+			y = int64(math.Floor(xm - p1*v + u))
+			break
+		}
+		if u <= p2 {
+			// Step 20, parallelogram
+			x := xl + (u-p1)/c
+			v = v*c + 1.0 - math.Abs(float64(m)-x+0.5)/p1
+			if v > 1.0 {
+				continue
+			}
+			y = int64(math.Floor(x))
+		} else if u <= p3 {
+			// Step 30, left exponential tail
+			y = int64(math.Floor(xl + logLibm(v)/laml))
+			if y < 0 || v == 0.0 {
+				continue
+			}
+			v = v * (u - p2) * laml
+		} else {
+			// Step 40, right exponential tail
+			y = int64(math.Floor(xr - logLibm(v)/lamr))
+			if y > n || v == 0.0 {
+				continue
+			}
+			v = v * (u - p3) * lamr
+		}
+		// Step 50
+		k := y - m
+		if k < 0 {
+			k = -k
+		}
+		kf := float64(k)
+		if k <= 20 || kf >= nrq/2.0-1 {
+			// explicit evaluation
+			sr := rr / q
+			a := sr * (nf + 1)
+			f := 1.0
+			if m < y {
+				for i := m + 1; i <= y; i++ {
+					f *= a/float64(i) - sr
+				}
+			} else if m > y {
+				for i := y + 1; i <= m; i++ {
+					f /= a/float64(i) - sr
+				}
+			}
+			if v > f {
+				continue
+			}
+			break
+		}
+		// Step 52, squeezing using upper and lower bounds on log(f(x))
+		rho := (kf / nrq) * ((kf*(kf/3.0+0.625)+0.16666666666666666)/nrq + 0.5)
+		t := -float64(k*k) / (2 * nrq)
+		a := logLibm(v)
+		if a < t-rho {
+			break
+		}
+		if a > t+rho {
+			continue
+		}
+		x1 := float64(y + 1)
+		f1 := float64(m + 1)
+		z := float64(n + 1 - m)
+		w := float64(n - y + 1)
+		x2 := x1 * x1
+		f2 := f1 * f1
+		z2 := z * z
+		w2 := w * w
+		bound := xm*logLibm(f1/x1) + (float64(n-m)+0.5)*logLibm(z/w) + float64(y-m)*logLibm(w*rr/(x1*q)) +
+			(13680.-(462.-(132.-(99.-140./f2)/f2)/f2)/f2)/f1/166320. +
+			(13680.-(462.-(132.-(99.-140./z2)/z2)/z2)/z2)/z/166320. +
+			(13680.-(462.-(132.-(99.-140./x2)/x2)/x2)/x2)/x1/166320. +
+			(13680.-(462.-(132.-(99.-140./w2)/w2)/w2)/w2)/w/166320.
+		if a > bound {
+			continue
+		}
+		break
+	}
+	// Step 60
+	if p > 0.5 {
+		y = n - y
+	}
+	return y
+}
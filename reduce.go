@@ -0,0 +1,366 @@
+package np
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mdcfrancis/gonp/internal/vec"
+)
+
+// AxisNone reduces over every axis of an array, numpy's axis=None.
+const AxisNone = math.MinInt
+
+// normalizeAxis resolves a negative axis against n dimensions.
+func normalizeAxis(axis, n int) (int, error) {
+	if axis < 0 {
+		axis += n
+	}
+	if axis < 0 || axis >= n {
+		return 0, fmt.Errorf("axis %d is out of bounds for array of dimension %d", axis, n)
+	}
+	return axis, nil
+}
+
+// reduction describes where a reduction over a runs.
+type reduction struct {
+	a    NDArray
+	axis int
+	// all is set for AxisNone
+	all bool
+}
+
+func newReduction(v interface{}, axis int) (reduction, error) {
+	a, err := AsNDArray(v)
+	if err != nil {
+		return reduction{}, err
+	}
+	if axis == AxisNone {
+		return reduction{a: a, all: true}, nil
+	}
+	if axis, err = normalizeAxis(axis, a.Ndim()); err != nil {
+		return reduction{}, err
+	}
+	return reduction{a: a, axis: axis}, nil
+}
+
+// inner reports whether the reduction runs along the contiguous last axis,
+// where numpy sums pairwise rather than element by element.
+func (r reduction) inner() bool {
+	return r.all || r.axis == r.a.Ndim()-1
+}
+
+// length is the number of elements reduced into each result.
+func (r reduction) length() int {
+	if r.all {
+		return r.a.Size()
+	}
+	return r.a.shape[r.axis]
+}
+
+// shape is the shape of the result.
+func (r reduction) shape(keepdims bool) []int {
+	var shape []int
+	for i, d := range r.a.shape {
+		switch {
+		case r.all || i == r.axis:
+			if keepdims {
+				shape = append(shape, 1)
+			}
+		default:
+			shape = append(shape, d)
+		}
+	}
+	if shape == nil {
+		shape = []int{}
+	}
+	return shape
+}
+
+// lines calls f with every 1-D line of the reduction, in C order of the
+// remaining axes.
+func (r reduction) lines(f func(line NpArray)) {
+	if r.all {
+		f(r.a.Flatten())
+		return
+	}
+	a := r.a
+	outer := copyInts(a.shape)
+	outer[r.axis] = 1
+	line := make(NpArray, a.shape[r.axis])
+	eachIndex(outer, func(idx []int) {
+		pos := a.offset
+		for i, v := range idx {
+			pos += v * a.strides[i]
+		}
+		for k := range line {
+			line[k] = a.data[pos+k*a.strides[r.axis]]
+		}
+		f(line)
+	})
+}
+
+// apply reduces every line with f.
+func (r reduction) apply(keepdims bool, f func(line NpArray) float64) (NDArray, error) {
+	var data []float64
+	r.lines(func(line NpArray) {
+		data = append(data, f(line))
+	})
+	shape := r.shape(keepdims)
+	if data == nil {
+		// some other axis is empty
+		data = make([]float64, 0)
+	}
+	return NewNDArray(data, shape...)
+}
+
+// pairwiseSum adds a as numpy's pairwise_sum does, with eight accumulators
+// in blocks of up to 128 elements, which keeps the rounding error to
+// O(log n).
+func pairwiseSum(a NpArray) float64 {
+	return vec.Sum(a)
+}
+
+// sum adds a line the way numpy would for this reduction.
+func (r reduction) sum(line NpArray) float64 {
+	if r.inner() {
+		return pairwiseSum(line)
+	}
+	sum := 0.0
+	for _, v := range line {
+		sum += v
+	}
+	return sum
+}
+
+// SumAxis sums a along axis, or over everything for AxisNone, like
+// numpy.sum. keepdims leaves the reduced axes in the result with length one.
+func SumAxis(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, r.sum)
+}
+
+// MeanAxis averages a along axis, like numpy.mean. The mean of nothing is
+// NaN.
+func MeanAxis(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	n := float64(r.length())
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return r.sum(line) / n
+	})
+}
+
+// VarAxis returns the variance along axis with divisor n - ddof, like
+// numpy.var.
+func VarAxis(a interface{}, axis, ddof int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return r.variance(line, ddof)
+	})
+}
+
+// StdAxis returns the standard deviation along axis with divisor n - ddof,
+// like numpy.std.
+func StdAxis(a interface{}, axis, ddof int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return math.Sqrt(r.variance(line, ddof))
+	})
+}
+
+// variance follows numpy's _var: the squared deviations from the mean are
+// summed the same way as the mean itself.
+func (r reduction) variance(line NpArray, ddof int) float64 {
+	n := len(line)
+	mean := r.sum(line) / float64(n)
+	dev := make(NpArray, n)
+	for i, v := range line {
+		d := v - mean
+		dev[i] = d * d
+	}
+	count := n - ddof
+	if count < 0 {
+		count = 0
+	}
+	return r.sum(dev) / float64(count)
+}
+
+// ProdAxis multiplies a along axis, like numpy.prod.
+func ProdAxis(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		prod := 1.0
+		for _, v := range line {
+			prod *= v
+		}
+		return prod
+	})
+}
+
+// MinAxis returns the minimum along axis, like numpy.min. NaN propagates
+// and an empty reduction is an error.
+func MinAxis(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	return extremum(a, axis, keepdims, "minimum", func(x, y float64) bool { return x < y })
+}
+
+// MaxAxis returns the maximum along axis, like numpy.max.
+func MaxAxis(a interface{}, axis int, keepdims bool) (NDArray, error) {
+	return extremum(a, axis, keepdims, "maximum", func(x, y float64) bool { return x > y })
+}
+
+func extremum(a interface{}, axis int, keepdims bool, name string, better func(x, y float64) bool) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if r.length() == 0 {
+		return NDArray{}, fmt.Errorf("zero-size array to reduction operation %s which has no identity", name)
+	}
+	return r.apply(keepdims, func(line NpArray) float64 {
+		return line[argExtremum(line, better)]
+	})
+}
+
+// argExtremum returns the index of the first best value, or of the first
+// NaN, as numpy's argmin and argmax do.
+func argExtremum(line NpArray, better func(x, y float64) bool) int {
+	best := 0
+	for i, v := range line {
+		if math.IsNaN(v) {
+			return i
+		}
+		if better(v, line[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// ArgMinAxis returns the indices of the minima along axis, like
+// numpy.argmin. With AxisNone the index is into the flattened array.
+func ArgMinAxis(a interface{}, axis int, keepdims bool) (Array[int64], error) {
+	return argExtremumAxis(a, axis, keepdims, "argmin", func(x, y float64) bool { return x < y })
+}
+
+// ArgMaxAxis returns the indices of the maxima along axis, like
+// numpy.argmax.
+func ArgMaxAxis(a interface{}, axis int, keepdims bool) (Array[int64], error) {
+	return argExtremumAxis(a, axis, keepdims, "argmax", func(x, y float64) bool { return x > y })
+}
+
+func argExtremumAxis(a interface{}, axis int, keepdims bool, name string, better func(x, y float64) bool) (Array[int64], error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return Array[int64]{}, err
+	}
+	if r.length() == 0 {
+		return Array[int64]{}, fmt.Errorf("attempt to get %s of an empty sequence", name)
+	}
+	data := make([]int64, 0)
+	r.lines(func(line NpArray) {
+		data = append(data, int64(argExtremum(line, better)))
+	})
+	return NewArray(data, r.shape(keepdims)...)
+}
+
+// CumSum returns the running sums along axis, like numpy.cumsum. With
+// AxisNone the flattened array is summed.
+func CumSum(a interface{}, axis int) (NDArray, error) {
+	return accumulate(a, axis, func(acc, v float64) float64 { return acc + v })
+}
+
+// CumProd returns the running products along axis, like numpy.cumprod.
+func CumProd(a interface{}, axis int) (NDArray, error) {
+	return accumulate(a, axis, func(acc, v float64) float64 { return acc * v })
+}
+
+func accumulate(a interface{}, axis int, op func(acc, v float64) float64) (NDArray, error) {
+	r, err := newReduction(a, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if r.all {
+		line := r.a.Flatten()
+		for i := 1; i < len(line); i++ {
+			line[i] = op(line[i-1], line[i])
+		}
+		return NewNDArray(line, len(line))
+	}
+	return alongAxis(r.a, r.axis, func(line NpArray) NpArray {
+		ret := line.Copy()
+		for i := 1; i < len(ret); i++ {
+			ret[i] = op(ret[i-1], ret[i])
+		}
+		return ret
+	})
+}
+
+// SumAxis sums the stack along axis: 0 for each column, 1 for each row or
+// AxisNone for everything.
+func (m NpStack) SumAxis(axis int, keepdims bool) (NDArray, error) {
+	return SumAxis(m, axis, keepdims)
+}
+
+// MeanAxis averages the stack along axis.
+func (m NpStack) MeanAxis(axis int, keepdims bool) (NDArray, error) {
+	return MeanAxis(m, axis, keepdims)
+}
+
+// VarAxis returns the variance of the stack along axis.
+func (m NpStack) VarAxis(axis, ddof int, keepdims bool) (NDArray, error) {
+	return VarAxis(m, axis, ddof, keepdims)
+}
+
+// StdAxis returns the standard deviation of the stack along axis.
+func (m NpStack) StdAxis(axis, ddof int, keepdims bool) (NDArray, error) {
+	return StdAxis(m, axis, ddof, keepdims)
+}
+
+// ProdAxis multiplies the stack along axis.
+func (m NpStack) ProdAxis(axis int, keepdims bool) (NDArray, error) {
+	return ProdAxis(m, axis, keepdims)
+}
+
+// MinAxis returns the minimum of the stack along axis.
+func (m NpStack) MinAxis(axis int, keepdims bool) (NDArray, error) {
+	return MinAxis(m, axis, keepdims)
+}
+
+// MaxAxis returns the maximum of the stack along axis.
+func (m NpStack) MaxAxis(axis int, keepdims bool) (NDArray, error) {
+	return MaxAxis(m, axis, keepdims)
+}
+
+// ArgMin returns the indices of the minima of the stack along axis.
+func (m NpStack) ArgMin(axis int, keepdims bool) (Array[int64], error) {
+	return ArgMinAxis(m, axis, keepdims)
+}
+
+// ArgMax returns the indices of the maxima of the stack along axis.
+func (m NpStack) ArgMax(axis int, keepdims bool) (Array[int64], error) {
+	return ArgMaxAxis(m, axis, keepdims)
+}
+
+// CumSum returns the running sums of the stack along axis.
+func (m NpStack) CumSum(axis int) (NDArray, error) {
+	return CumSum(m, axis)
+}
+
+// CumProd returns the running products of the stack along axis.
+func (m NpStack) CumProd(axis int) (NDArray, error) {
+	return CumProd(m, axis)
+}
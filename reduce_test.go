@@ -0,0 +1,184 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestSumAxis(t *testing.T) {
+	m := NpStack{{1, 2, 3}, {4, 5, 6}}
+	ret, err := m.SumAxis(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, ret.Shape())
+	assert.Equal(t, []float64{5, 7, 9}, ret.Flatten())
+
+	ret, err = m.SumAxis(1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, ret.Shape())
+	assert.Equal(t, []float64{6, 15}, ret.Flatten())
+
+	ret, err = m.SumAxis(-2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, ret.Shape())
+
+	ret, err = m.SumAxis(AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ret.Ndim())
+	assert.Equal(t, []float64{21}, ret.Flatten())
+	ret, err = m.SumAxis(AxisNone, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, ret.Shape())
+
+	_, err = m.SumAxis(2, false)
+	assert.Error(t, err)
+	_, err = NpStack{{1, 2}, {3}}.SumAxis(0, false)
+	assert.Error(t, err)
+}
+
+func TestSumAxis_Pairwise(t *testing.T) {
+	tenths := make(NpArray, 10)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+	// numpy sums the contiguous axis pairwise, np.sum([0.1] * 10) == 1.0
+	ret, err := SumAxis(tenths, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1}, ret.Flatten())
+
+	// but accumulates row by row down the columns
+	m := make(NpStack, 10)
+	for i := range m {
+		m[i] = NpArray{0.1, 0.1}
+	}
+	ret, err = m.SumAxis(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.9999999999999999, 0.9999999999999999}, ret.Flatten())
+
+	// pairwise summation of many equal values is far more accurate
+	many := make(NpArray, 100000)
+	for i := range many {
+		many[i] = 0.1
+	}
+	assert.InDelta(t, 10000, pairwiseSum(many), 1e-10)
+	naive := 0.0
+	for _, v := range many {
+		naive += v
+	}
+	assert.Greater(t, math.Abs(naive-10000), 1e-9)
+	assert.Equal(t, pairwiseSum(many), many.Sum())
+}
+
+func TestMeanVarStd(t *testing.T) {
+	m := NpStack{{1, 2, 3}, {4, 5, 6}}
+	ret, err := m.MeanAxis(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2.5, 3.5, 4.5}, ret.Flatten())
+	ret, err = m.MeanAxis(AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3.5}, ret.Flatten())
+
+	ret, err = m.VarAxis(0, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2.25, 2.25, 2.25}, ret.Flatten())
+	ret, err = m.VarAxis(0, 1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, ret.Shape())
+	assert.Equal(t, []float64{4.5, 4.5, 4.5}, ret.Flatten())
+
+	ret, err = m.StdAxis(1, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.816496580927726, 0.816496580927726}, ret.Flatten())
+	ret, err = m.StdAxis(AxisNone, 0, false)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.707825127659933, ret.Flatten()[0], 1e-15)
+
+	// the existing per row helpers agree
+	std, err := m.StdAxis(1, 0, false)
+	assert.NoError(t, err)
+	assert.True(t, m.StandardDeviation().AlmostEqual(std.Flatten(), 1e-15))
+
+	// too few elements for ddof and empty means are NaN, as in numpy
+	ret, err = VarAxis(NpArray{1}, 0, 1, false)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret.Flatten()[0]))
+	ret, err = MeanAxis(NpArray{}, 0, false)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret.Flatten()[0]))
+}
+
+func TestMinMaxProd(t *testing.T) {
+	m := NpStack{{3, 2, 9}, {4, 5, 1}}
+	ret, err := m.MinAxis(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 2, 1}, ret.Flatten())
+	ret, err = m.MaxAxis(1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{9, 5}, ret.Flatten())
+	ret, err = m.ProdAxis(1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{54, 20}, ret.Flatten())
+	ret, err = ProdAxis(NpArray{}, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1}, ret.Flatten())
+
+	ret, err = MaxAxis(NpArray{1, math.NaN(), 3}, 0, false)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(ret.Flatten()[0]))
+
+	_, err = MinAxis(NpArray{}, 0, false)
+	assert.Error(t, err)
+	_, err = MaxAxis(NpArray{}, AxisNone, false)
+	assert.Error(t, err)
+}
+
+func TestArgMinMax(t *testing.T) {
+	m := NpStack{{3, 2, 9}, {4, 9, 1}}
+	idx, err := m.ArgMax(0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 1, 0}, idx.Flatten())
+	idx, err = m.ArgMin(1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, idx.Shape())
+	assert.Equal(t, []int64{1, 2}, idx.Flatten())
+
+	// the first of equal values and the flattened index for AxisNone
+	idx, err = m.ArgMax(AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, idx.Flatten())
+	idx, err = m.ArgMin(AxisNone, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5}, idx.Flatten())
+
+	// NaN wins either way
+	idx, err = ArgMinAxis(NpArray{1, math.NaN(), 0}, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, idx.Flatten())
+
+	_, err = ArgMaxAxis(NpArray{}, 0, false)
+	assert.Error(t, err)
+}
+
+func TestCumSumProd(t *testing.T) {
+	m := NpStack{{1, 2, 3}, {4, 5, 6}}
+	ret, err := m.CumSum(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, ret.Shape())
+	assert.Equal(t, []float64{1, 3, 6, 4, 9, 15}, ret.Flatten())
+	ret, err = m.CumSum(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3, 5, 7, 9}, ret.Flatten())
+	ret, err = m.CumSum(AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6}, ret.Shape())
+	assert.Equal(t, []float64{1, 3, 6, 10, 15, 21}, ret.Flatten())
+	ret, err = m.CumProd(AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 6, 24, 120, 720}, ret.Flatten())
+	ret, err = m.CumProd(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3, 4, 10, 18}, ret.Flatten())
+
+	// the input is left alone
+	assert.Equal(t, NpArray{1, 2, 3}, m[0])
+}
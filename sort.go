@@ -0,0 +1,500 @@
+package np
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// lessNaN orders values as numpy's sorts do, with NaN after everything
+// else.
+func lessNaN(a, b float64) bool {
+	return a < b || (b != b && a == a)
+}
+
+// sameValue reports whether a and b are equal, counting NaN as equal to
+// itself as numpy.unique does.
+func sameValue(a, b float64) bool {
+	return a == b || (a != a && b != b)
+}
+
+// argSortLine returns the stable sorting permutation of line.
+func argSortLine(line NpArray) []int {
+	idx := make([]int, len(line))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return lessNaN(line[idx[i]], line[idx[j]]) })
+	return idx
+}
+
+// indexLine applies f to each line of a along axis, or to the flattened
+// array for AxisNone, and returns the integer results.
+func indexLine(a interface{}, axis int, f func(line NpArray) []int) (Array[int64], error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return Array[int64]{}, err
+	}
+	if axis == AxisNone {
+		nd, _ = NewNDArray(nd.Flatten(), nd.Size())
+		axis = 0
+	}
+	ret, err := alongAxis(nd, axis, func(line NpArray) NpArray {
+		idx := f(line)
+		out := make(NpArray, len(idx))
+		for i, v := range idx {
+			out[i] = float64(v)
+		}
+		return out
+	})
+	if err != nil {
+		return Array[int64]{}, err
+	}
+	return AsType[int64](ret, CastUnsafe)
+}
+
+// Sort returns a copy of a sorted along axis, or the flattened array for
+// AxisNone, like numpy.sort. NaN sorts to the end.
+func Sort(a interface{}, axis int) (NDArray, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, err
+	}
+	if axis == AxisNone {
+		nd, _ = NewNDArray(nd.Flatten(), nd.Size())
+		axis = 0
+	}
+	return alongAxis(nd, axis, func(line NpArray) NpArray {
+		ret := line.Copy()
+		sort.SliceStable(ret, func(i, j int) bool { return lessNaN(ret[i], ret[j]) })
+		return ret
+	})
+}
+
+// ArgSort returns the indices that sort a along axis, like numpy.argsort
+// with kind='stable': equal values keep their order. With AxisNone the
+// indices are into the flattened array.
+func ArgSort(a interface{}, axis int) (Array[int64], error) {
+	return indexLine(a, axis, argSortLine)
+}
+
+// Sort returns a sorted copy of the array.
+func (a NpArray) Sort() NpArray {
+	ret := a.Copy()
+	sort.SliceStable(ret, func(i, j int) bool { return lessNaN(ret[i], ret[j]) })
+	return ret
+}
+
+// ArgSort returns the stable sorting permutation of the array, suitable for
+// Shuffle.
+func (a NpArray) ArgSort() []int {
+	return argSortLine(a)
+}
+
+// Side selects which of several equal positions SearchSorted returns.
+type Side int
+
+const (
+	// SideLeft returns the first suitable position, numpy's default.
+	SideLeft Side = iota
+	// SideRight returns the last suitable position.
+	SideRight
+)
+
+func (s Side) String() string {
+	switch s {
+	case SideLeft:
+		return "left"
+	case SideRight:
+		return "right"
+	}
+	return fmt.Sprintf("Side(%d)", int(s))
+}
+
+// SearchSorted returns the positions at which each value of v would be
+// inserted into the sorted array a to keep it sorted, like
+// numpy.searchsorted. NaN sorts after everything else.
+func SearchSorted(a, v NpArray, side Side) []int {
+	ret := make([]int, len(v))
+	for i, x := range v {
+		if side == SideRight {
+			ret[i] = sort.Search(len(a), func(k int) bool { return lessNaN(x, a[k]) })
+		} else {
+			ret[i] = sort.Search(len(a), func(k int) bool { return !lessNaN(a[k], x) })
+		}
+	}
+	return ret
+}
+
+// UniqueResult holds the outputs of Unique.
+type UniqueResult struct {
+	// Values are the sorted unique values.
+	Values NpArray
+	// Index holds the position of the first occurrence of each value,
+	// numpy's return_index.
+	Index []int
+	// Inverse rebuilds the input as Values[Inverse[i]], return_inverse.
+	Inverse []int
+	// Counts is the number of occurrences of each value, return_counts.
+	Counts []int
+}
+
+// Unique returns the sorted unique values of the flattened a, like
+// numpy.unique with return_index, return_inverse and return_counts. NaN
+// values collapse into one, numpy's equal_nan.
+func Unique(a interface{}) (UniqueResult, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return UniqueResult{}, err
+	}
+	return unique(nd.Flatten()), nil
+}
+
+func unique(line NpArray) UniqueResult {
+	ret := UniqueResult{Values: NpArray{}, Index: []int{}, Inverse: make([]int, len(line)), Counts: []int{}}
+	for i, k := range argSortLine(line) {
+		n := len(ret.Values)
+		if i == 0 || !sameValue(line[k], ret.Values[n-1]) {
+			ret.Values = append(ret.Values, line[k])
+			ret.Index = append(ret.Index, k)
+			ret.Counts = append(ret.Counts, 0)
+			n++
+		}
+		ret.Counts[n-1]++
+		ret.Inverse[k] = n - 1
+	}
+	return ret
+}
+
+// LexSort returns the stable permutation that sorts by the last key, then
+// by the one before it and so on, like numpy.lexsort.
+func LexSort(keys NpStack) ([]int, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("need sequence of keys with len > 0 in lexsort")
+	}
+	n := len(keys[0])
+	for _, k := range keys {
+		if len(k) != n {
+			return nil, fmt.Errorf("all keys need to be the same shape")
+		}
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		for k := len(keys) - 1; k >= 0; k-- {
+			x, y := keys[k][idx[i]], keys[k][idx[j]]
+			if lessNaN(x, y) {
+				return true
+			}
+			if lessNaN(y, x) {
+				return false
+			}
+		}
+		return false
+	})
+	return idx, nil
+}
+
+// maxPivotStack bounds the pivots remembered between the kth values of
+// one partition, NPY_MAX_PIVOT_STACK.
+const maxPivotStack = 50
+
+// selector partitions values and their original positions together, so
+// the same steps serve Partition and ArgPartition.
+type selector struct {
+	v   NpArray
+	idx []int
+}
+
+func (s selector) less(i, j int) bool {
+	return lessNaN(s.v[i], s.v[j])
+}
+
+func (s selector) swap(i, j int) {
+	s.v[i], s.v[j] = s.v[j], s.v[i]
+	s.idx[i], s.idx[j] = s.idx[j], s.idx[i]
+}
+
+func (s selector) sub(off int) selector {
+	return selector{s.v[off:], s.idx[off:]}
+}
+
+// introselect places the kth smallest value at kth, smaller values before
+// it and larger ones after, following the portable introselect of numpy's
+// selection.cpp step for step.
+func (s selector) introselect(num, kth int, pivots *[]int) {
+	low, high := 0, num-1
+	for pivots != nil && len(*pivots) > 0 {
+		top := (*pivots)[len(*pivots)-1]
+		if top > kth {
+			high = top - 1
+			break
+		}
+		if top == kth {
+			return
+		}
+		low = top + 1
+		*pivots = (*pivots)[:len(*pivots)-1]
+	}
+	// an O(n kth) selection for very small kth
+	if kth-low < 3 {
+		s.sub(low).dumbSelect(high-low+1, kth-low)
+		storePivot(kth, kth, pivots)
+		return
+	}
+	// the last place only needs the last maximum, which also moves a NaN
+	// there without partitioning the rest
+	if kth == num-1 {
+		best := low
+		for k := low + 1; k < num; k++ {
+			if !s.less(k, best) {
+				best = k
+			}
+		}
+		s.swap(kth, best)
+		return
+	}
+	depth := (bits.Len(uint(num)) - 1) * 2
+	for low+1 < high {
+		ll, hh := low+1, high
+		if depth > 0 || hh-ll < 5 {
+			s.median3Swap(low, low+(high-low)/2, high)
+		} else {
+			// median of medians of five for a linear worst case
+			mid := ll + s.sub(ll).medianOfMedian5(hh-ll)
+			s.swap(mid, low)
+			ll--
+			hh++
+		}
+		depth--
+		ll, hh = s.unguardedPartition(low, ll, hh)
+		s.swap(low, hh)
+		if hh != kth {
+			storePivot(hh, kth, pivots)
+		}
+		if hh >= kth {
+			high = hh - 1
+		}
+		if hh <= kth {
+			low = ll
+		}
+	}
+	if low+1 == high && s.less(high, low) {
+		s.swap(high, low)
+	}
+	storePivot(kth, kth, pivots)
+}
+
+// storePivot remembers a pivot at or beyond kth for the later, larger kth
+// values.
+func storePivot(pivot, kth int, pivots *[]int) {
+	switch {
+	case pivots == nil:
+	case pivot == kth && len(*pivots) == maxPivotStack:
+		(*pivots)[len(*pivots)-1] = pivot
+	case pivot >= kth && len(*pivots) < maxPivotStack:
+		*pivots = append(*pivots, pivot)
+	}
+}
+
+func (s selector) dumbSelect(num, kth int) {
+	for i := 0; i <= kth; i++ {
+		best := i
+		for k := i + 1; k < num; k++ {
+			if s.less(k, best) {
+				best = k
+			}
+		}
+		s.swap(i, best)
+	}
+}
+
+// median3Swap moves the median of three to low and the smallest to
+// low+1, which lets the partition run without bounds checks.
+func (s selector) median3Swap(low, mid, high int) {
+	if s.less(high, mid) {
+		s.swap(high, mid)
+	}
+	if s.less(high, low) {
+		s.swap(high, low)
+	}
+	if s.less(low, mid) {
+		s.swap(low, mid)
+	}
+	s.swap(mid, low+1)
+}
+
+func (s selector) unguardedPartition(pivot, ll, hh int) (int, int) {
+	p := s.v[pivot]
+	for {
+		for ll++; lessNaN(s.v[ll], p); ll++ {
+		}
+		for hh--; lessNaN(p, s.v[hh]); hh-- {
+		}
+		if hh < ll {
+			return ll, hh
+		}
+		s.swap(ll, hh)
+	}
+}
+
+func (s selector) median5() int {
+	if s.less(1, 0) {
+		s.swap(1, 0)
+	}
+	if s.less(4, 3) {
+		s.swap(4, 3)
+	}
+	if s.less(3, 0) {
+		s.swap(3, 0)
+	}
+	if s.less(4, 1) {
+		s.swap(4, 1)
+	}
+	if s.less(2, 1) {
+		s.swap(2, 1)
+	}
+	if s.less(3, 2) {
+		if s.less(3, 1) {
+			return 1
+		}
+		return 3
+	}
+	return 2
+}
+
+func (s selector) medianOfMedian5(num int) int {
+	nmed := num / 5
+	for i, sub := 0, 0; i < nmed; i, sub = i+1, sub+5 {
+		m := s.sub(sub).median5()
+		s.swap(sub+m, i)
+	}
+	if nmed > 2 {
+		s.introselect(nmed, nmed/2, nil)
+	}
+	return nmed / 2
+}
+
+// partitionLine partitions line about every kth, already normalized and
+// sorted, and returns the values and their original positions.
+func partitionLine(line NpArray, kth []int) selector {
+	s := selector{v: line.Copy(), idx: make([]int, len(line))}
+	for i := range s.idx {
+		s.idx[i] = i
+	}
+	pivots := make([]int, 0, maxPivotStack)
+	for _, k := range kth {
+		s.introselect(len(line), k, &pivots)
+	}
+	return s
+}
+
+// partitionKth resolves negative kth values against n and sorts them.
+func partitionKth(kth []int, n int) ([]int, error) {
+	ret := make([]int, len(kth))
+	for i, k := range kth {
+		if k < 0 {
+			k += n
+		}
+		if k < 0 || k >= n {
+			return nil, fmt.Errorf("kth(=%d) out of bounds (%d)", kth[i], n)
+		}
+		ret[i] = k
+	}
+	sort.Ints(ret)
+	return ret, nil
+}
+
+// partitionAxis resolves axis and kth for a partition of a.
+func partitionAxis(a interface{}, kth []int, axis int) (NDArray, []int, int, error) {
+	nd, err := AsNDArray(a)
+	if err != nil {
+		return NDArray{}, nil, 0, err
+	}
+	if axis == AxisNone {
+		nd, _ = NewNDArray(nd.Flatten(), nd.Size())
+		axis = 0
+	}
+	if axis, err = normalizeAxis(axis, nd.Ndim()); err != nil {
+		return NDArray{}, nil, 0, err
+	}
+	k, err := partitionKth(kth, nd.shape[axis])
+	return nd, k, axis, err
+}
+
+// Partition returns a copy of a with each kth element along axis in its
+// sorted position, smaller values before it and larger ones after, like
+// numpy.partition. The order within the parts follows numpy's portable
+// introselect; numpy builds that use SIMD selection for a single kth may
+// order them differently.
+func Partition(a interface{}, kth []int, axis int) (NDArray, error) {
+	nd, k, axis, err := partitionAxis(a, kth, axis)
+	if err != nil {
+		return NDArray{}, err
+	}
+	return alongAxis(nd, axis, func(line NpArray) NpArray {
+		return partitionLine(line, k).v
+	})
+}
+
+// ArgPartition returns the indices that would partition a along axis, like
+// numpy.argpartition.
+func ArgPartition(a interface{}, kth []int, axis int) (Array[int64], error) {
+	nd, k, axis, err := partitionAxis(a, kth, axis)
+	if err != nil {
+		return Array[int64]{}, err
+	}
+	return indexLine(nd, axis, func(line NpArray) []int {
+		return partitionLine(line, k).idx
+	})
+}
+
+// In1d reports for each value of ar1 whether it is in ar2, or is not when
+// invert is set, like numpy.in1d. NaN is never found.
+func In1d(ar1, ar2 NpArray, invert bool) []bool {
+	set := make(map[float64]bool, len(ar2))
+	for _, v := range ar2 {
+		set[v] = true
+	}
+	ret := make([]bool, len(ar1))
+	for i, v := range ar1 {
+		ret[i] = set[v] != invert
+	}
+	return ret
+}
+
+// Intersect1d returns the sorted unique values found in both a and b, like
+// numpy.intersect1d.
+func Intersect1d(a, b NpArray) NpArray {
+	ua := unique(a).Values
+	ret := NpArray{}
+	for i, found := range In1d(ua, b, false) {
+		if found {
+			ret = append(ret, ua[i])
+		}
+	}
+	return ret
+}
+
+// SetDiff1d returns the sorted unique values of a that are not in b, like
+// numpy.setdiff1d.
+func SetDiff1d(a, b NpArray) NpArray {
+	ua := unique(a).Values
+	ret := NpArray{}
+	for i, missing := range In1d(ua, b, true) {
+		if missing {
+			ret = append(ret, ua[i])
+		}
+	}
+	return ret
+}
+
+// Union1d returns the sorted unique values found in either a or b, like
+// numpy.union1d.
+func Union1d(a, b NpArray) NpArray {
+	both := make(NpArray, 0, len(a)+len(b))
+	both = append(both, a...)
+	return unique(append(both, b...)).Values
+}
@@ -0,0 +1,175 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestSortArgSort(t *testing.T) {
+	nan := math.NaN()
+	m := NpStack{{1, 4}, {3, 1}}
+	ret, err := Sort(m, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 4, 1, 3}, ret.Flatten())
+	ret, err = Sort(m, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 3, 4}, ret.Flatten())
+	ret, err = Sort(m, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4}, ret.Shape())
+	assert.Equal(t, []float64{1, 1, 3, 4}, ret.Flatten())
+
+	// NaN sorts last
+	sorted := NpArray{nan, 2, -1, nan, 0}.Sort()
+	assert.Equal(t, NpArray{-1, 0, 2}, sorted[:3])
+	assert.True(t, math.IsNaN(sorted[3]) && math.IsNaN(sorted[4]))
+
+	// ties keep their order
+	idx, err := ArgSort(NpArray{3, 1, 2, 1, 3, nan, 1}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 6, 2, 0, 4, 5}, idx.Flatten())
+	idx, err = ArgSort(NpStack{{0, 3}, {2, 2}}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 1, 0}, idx.Flatten())
+	idx, err = ArgSort(NpStack{{0, 3}, {2, 2}}, AxisNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 2, 3, 1}, idx.Flatten())
+	assert.Equal(t, []int{1, 0, 2}, NpArray{2, 1, 3}.ArgSort())
+
+	_, err = ArgSort(m, 2)
+	assert.Error(t, err)
+}
+
+func TestSearchSorted(t *testing.T) {
+	a := NpArray{1, 2, 3, 4, 5}
+	assert.Equal(t, []int{2}, SearchSorted(a, NpArray{3}, SideLeft))
+	assert.Equal(t, []int{3}, SearchSorted(a, NpArray{3}, SideRight))
+	assert.Equal(t, []int{0, 5, 1, 2}, SearchSorted(a, NpArray{-10, 10, 2, 3}, SideLeft))
+	// NaN goes after everything, including NaN on the left
+	withNaN := NpArray{1, 2, math.NaN()}
+	assert.Equal(t, []int{2, 2}, SearchSorted(withNaN, NpArray{math.NaN(), 5}, SideLeft))
+	assert.Equal(t, []int{3, 2}, SearchSorted(withNaN, NpArray{math.NaN(), 5}, SideRight))
+	assert.Equal(t, "right", SideRight.String())
+}
+
+func TestUnique(t *testing.T) {
+	// the numpy.unique examples
+	u, err := Unique(NpStack{{1, 1}, {2, 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 2, 3}, u.Values)
+
+	a := NpArray{1, 2, 6, 4, 2, 3, 2}
+	u, err = Unique(a)
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{1, 2, 3, 4, 6}, u.Values)
+	assert.Equal(t, []int{0, 1, 5, 3, 2}, u.Index)
+	assert.Equal(t, []int{0, 1, 4, 3, 1, 2, 1}, u.Inverse)
+	assert.Equal(t, []int{1, 3, 1, 1, 1}, u.Counts)
+	for i, k := range u.Inverse {
+		assert.Equal(t, a[i], u.Values[k])
+	}
+
+	// NaN values collapse into one
+	u, err = Unique(NpArray{math.NaN(), 1, math.NaN()})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(u.Values))
+	assert.True(t, math.IsNaN(u.Values[1]))
+	assert.Equal(t, []int{1, 0}, u.Index)
+	assert.Equal(t, []int{1, 2}, u.Counts)
+
+	u, err = Unique(NpArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, NpArray{}, u.Values)
+}
+
+func TestLexSort(t *testing.T) {
+	// the numpy.lexsort example, sorting by last name then first name
+	surnames := NpArray{3, 2, 3, 1, 2}
+	firstNames := NpArray{2, 1, 1, 3, 2}
+	idx, err := LexSort(NpStack{firstNames, surnames})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 4, 2, 0}, idx)
+
+	a := NpArray{1, 5, 1, 4, 3, 4, 4}
+	b := NpArray{9, 4, 0, 4, 0, 2, 1}
+	idx, err = LexSort(NpStack{b, a})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 0, 4, 6, 5, 3, 1}, idx)
+
+	_, err = LexSort(NpStack{})
+	assert.Error(t, err)
+	_, err = LexSort(NpStack{{1, 2}, {1}})
+	assert.Error(t, err)
+}
+
+func TestPartition(t *testing.T) {
+	// the numpy.partition and numpy.argpartition examples
+	a := NpArray{7, 1, 7, 7, 1, 5, 7, 2, 3, 2, 6, 2, 3, 0}
+	ret, err := Partition(a, []int{4}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2, 1, 2, 5, 2, 3, 3, 6, 7, 7, 7, 7}, ret.Flatten())
+	ret, err = Partition(a, []int{4, 8}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2, 1, 2, 3, 3, 2, 5, 6, 7, 7, 7, 7}, ret.Flatten())
+
+	// numpy's example is on integers; on floats the last place only swaps
+	// in the last maximum
+	x := NpArray{3, 4, 2, 1}
+	idx, err := ArgPartition(x, []int{3}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 3, 2, 1}, idx.Flatten())
+	idx, err = ArgPartition(NpArray{3, 1, 2, 5, 4, 0, 9, 7}, []int{-1}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 7, 6}, idx.Flatten())
+	idx, err = ArgPartition(NpArray{2, math.NaN(), 1, math.NaN(), 0}, []int{-1}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2, 4, 3}, idx.Flatten())
+	idx, err = ArgPartition(x, []int{1, 3}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2, 0, 1}, idx.Flatten())
+
+	// every kth holds its sorted value with the rest either side
+	long := make(NpArray, 200)
+	for i := range long {
+		long[i] = float64((i * 7919) % 211)
+	}
+	sorted := long.Sort()
+	kth := []int{3, 50, -1, 120}
+	ret, err = Partition(long, kth, AxisNone)
+	assert.NoError(t, err)
+	got := ret.Flatten()
+	for _, k := range []int{3, 50, 199, 120} {
+		assert.Equal(t, sorted[k], got[k])
+		for i := range got {
+			if i < k {
+				assert.LessOrEqual(t, got[i], got[k])
+			} else {
+				assert.GreaterOrEqual(t, got[i], got[k])
+			}
+		}
+	}
+	idx, err = ArgPartition(long, kth, 0)
+	assert.NoError(t, err)
+	for i, k := range idx.Flatten() {
+		assert.Equal(t, got[i], long[k])
+	}
+
+	ret, err = Partition(NpStack{{3, 1, 2}, {9, 7, 8}}, []int{0}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 3, 2, 7, 9, 8}, ret.Flatten())
+	_, err = Partition(a, []int{14}, 0)
+	assert.Error(t, err)
+}
+
+func TestSetOperations(t *testing.T) {
+	// the numpy set routine examples
+	assert.Equal(t, []bool{true, false, true, false, true}, In1d(NpArray{0, 1, 2, 5, 0}, NpArray{0, 2}, false))
+	assert.Equal(t, []bool{false, true, false, true, false}, In1d(NpArray{0, 1, 2, 5, 0}, NpArray{0, 2}, true))
+	assert.Equal(t, []bool{false}, In1d(NpArray{math.NaN()}, NpArray{math.NaN()}, false))
+
+	assert.Equal(t, NpArray{1, 3}, Intersect1d(NpArray{1, 3, 4, 3}, NpArray{3, 1, 2, 1}))
+	assert.Equal(t, NpArray{1, 2}, SetDiff1d(NpArray{1, 2, 3, 2, 4, 1}, NpArray{3, 4, 5, 6}))
+	assert.Equal(t, NpArray{-2, -1, 0, 1, 2}, Union1d(NpArray{-1, 0, 1}, NpArray{-2, 0, 2}))
+	assert.Equal(t, NpArray{}, Intersect1d(NpArray{}, NpArray{1}))
+}
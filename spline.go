@@ -0,0 +1,385 @@
+package np
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SplineBoundary is one end condition of a CubicSpline. The zero value is
+// not-a-knot, otherwise Order 1 or 2 fixes that derivative to Value, as
+// scipy's bc_type=(order, value) does.
+type SplineBoundary struct {
+	Order int
+	Value float64
+}
+
+var (
+	// NotAKnot makes the first and second pieces the same cubic.
+	NotAKnot = SplineBoundary{}
+	// Natural sets the second derivative at the end to zero.
+	Natural = SplineBoundary{Order: 2}
+	// Clamped sets the first derivative at the end to zero.
+	Clamped = SplineBoundary{Order: 1}
+)
+
+// CubicSpline is a piecewise cubic with continuous second derivative through
+// the points (x, y), scipy.interpolate.CubicSpline. It extrapolates with the
+// end pieces.
+type CubicSpline struct {
+	x NpArray
+	// c[0..3] hold the cubic, quadratic, linear and constant coefficients of
+	// each interval, in powers of (x - x[i]).
+	c [4]NpArray
+}
+
+// NewCubicSpline fits a cubic spline to strictly increasing x with the given
+// end conditions.
+func NewCubicSpline(x, y NpArray, start, end SplineBoundary) (*CubicSpline, error) {
+	n := len(x)
+	if len(y) != n {
+		return nil, fmt.Errorf("the length of y (%d) doesn't match the length of x (%d)", len(y), n)
+	}
+	if n < 2 {
+		return nil, fmt.Errorf("x must contain at least 2 elements")
+	}
+	if err := checkIncreasing(x); err != nil {
+		return nil, err
+	}
+	for _, bc := range []SplineBoundary{start, end} {
+		if bc.Order < 0 || bc.Order > 2 {
+			return nil, fmt.Errorf("the specified derivative order must be 1 or 2")
+		}
+	}
+	dx := make(NpArray, n-1)
+	slope := make(NpArray, n-1)
+	for i := range dx {
+		dx[i] = x[i+1] - x[i]
+		slope[i] = (y[i+1] - y[i]) / dx[i]
+	}
+	if n == 2 {
+		// a single interval can't be not-a-knot, use the chord slope
+		if start.Order == 0 {
+			start = SplineBoundary{Order: 1, Value: slope[0]}
+		}
+		if end.Order == 0 {
+			end = SplineBoundary{Order: 1, Value: slope[0]}
+		}
+	}
+
+	var s NpArray
+	if n == 3 && start.Order == 0 && end.Order == 0 {
+		// not-a-knot at both ends of two intervals is the parabola through
+		// the three points
+		lower := NpArray{0, dx[1], 1}
+		diag := NpArray{1, 2 * (dx[0] + dx[1]), 1}
+		upper := NpArray{1, dx[0], 0}
+		b := NpArray{2 * slope[0], 3 * (dx[0]*slope[1] + dx[1]*slope[0]), 2 * slope[1]}
+		s = solveTridiagonal(lower, diag, upper, b)
+	} else {
+		// lower[i] is A[i][i-1] and upper[i] is A[i][i+1]
+		lower := make(NpArray, n)
+		diag := make(NpArray, n)
+		upper := make(NpArray, n)
+		b := make(NpArray, n)
+		for i := 1; i < n-1; i++ {
+			diag[i] = 2 * (dx[i-1] + dx[i])
+			upper[i] = dx[i-1]
+			lower[i] = dx[i]
+			b[i] = 3 * (dx[i]*slope[i-1] + dx[i-1]*slope[i])
+		}
+		switch start.Order {
+		case 0:
+			d := x[2] - x[0]
+			diag[0] = dx[1]
+			upper[0] = d
+			b[0] = ((dx[0]+2*d)*dx[1]*slope[0] + dx[0]*dx[0]*slope[1]) / d
+		case 1:
+			diag[0] = 1
+			upper[0] = 0
+			b[0] = start.Value
+		case 2:
+			diag[0] = 2 * dx[0]
+			upper[0] = dx[0]
+			b[0] = -0.5*start.Value*dx[0]*dx[0] + 3*(y[1]-y[0])
+		}
+		switch end.Order {
+		case 0:
+			d := x[n-1] - x[n-3]
+			diag[n-1] = dx[n-3]
+			lower[n-1] = d
+			b[n-1] = (dx[n-2]*dx[n-2]*slope[n-3] + (2*d+dx[n-2])*dx[n-3]*slope[n-2]) / d
+		case 1:
+			diag[n-1] = 1
+			lower[n-1] = 0
+			b[n-1] = end.Value
+		case 2:
+			diag[n-1] = 2 * dx[n-2]
+			lower[n-1] = dx[n-2]
+			b[n-1] = 0.5*end.Value*dx[n-2]*dx[n-2] + 3*(y[n-1]-y[n-2])
+		}
+		s = solveTridiagonal(lower, diag, upper, b)
+	}
+
+	// Hermite form from the values and slopes at each knot
+	cs := &CubicSpline{x: x.Copy()}
+	for k := range cs.c {
+		cs.c[k] = make(NpArray, n-1)
+	}
+	for i := 0; i < n-1; i++ {
+		t := (s[i] + s[i+1] - 2*slope[i]) / dx[i]
+		cs.c[0][i] = t / dx[i]
+		cs.c[1][i] = (slope[i]-s[i])/dx[i] - t
+		cs.c[2][i] = s[i]
+		cs.c[3][i] = y[i]
+	}
+	return cs, nil
+}
+
+// Eval evaluates the spline at each x.
+func (s *CubicSpline) Eval(x NpArray) NpArray {
+	ret := make(NpArray, len(x))
+	for i, v := range x {
+		ret[i] = s.at(v)
+	}
+	return ret
+}
+
+func (s *CubicSpline) at(v float64) float64 {
+	if math.IsNaN(v) {
+		return v
+	}
+	n := len(s.x)
+	// the interval with x[i] <= v < x[i+1], the end pieces extend outwards
+	i := sort.Search(n, func(j int) bool { return s.x[j] > v }) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i > n-2 {
+		i = n - 2
+	}
+	d := v - s.x[i]
+	ret, z := 0.0, 1.0
+	for k := 3; k >= 0; k-- {
+		ret += s.c[k][i] * z
+		z *= d
+	}
+	return ret
+}
+
+// solveTridiagonal solves the system with sub diagonal lower[1:], diagonal
+// diag and super diagonal upper[:n-1] using banded LU with partial pivoting.
+func solveTridiagonal(lower, diag, upper, b NpArray) NpArray {
+	n := len(diag)
+	band := newBandMatrix(n, 1, 1)
+	for i := 0; i < n; i++ {
+		band.set(i, i, diag[i])
+		if i > 0 {
+			band.set(i, i-1, lower[i])
+		}
+		if i < n-1 {
+			band.set(i, i+1, upper[i])
+		}
+	}
+	return band.solve(b)
+}
+
+// bandMatrix stores the rows of a matrix with kl sub diagonals and ku super
+// diagonals, leaving room for the fill in of partial pivoting.
+type bandMatrix struct {
+	kl, ku int
+	rows   []bandRow
+}
+
+type bandRow struct {
+	first int
+	vals  NpArray
+}
+
+func newBandMatrix(n, kl, ku int) *bandMatrix {
+	m := &bandMatrix{kl: kl, ku: ku, rows: make([]bandRow, n)}
+	for i := range m.rows {
+		m.rows[i] = bandRow{first: i - kl, vals: make(NpArray, 2*kl+ku+1)}
+	}
+	return m
+}
+
+func (m *bandMatrix) set(i, j int, v float64) {
+	m.rows[i].vals[j-m.rows[i].first] = v
+}
+
+func (r bandRow) get(j int) float64 {
+	if k := j - r.first; k >= 0 && k < len(r.vals) {
+		return r.vals[k]
+	}
+	return 0
+}
+
+// solve overwrites the matrix with its LU factors and returns the solution
+// of m x = b.
+func (m *bandMatrix) solve(b NpArray) NpArray {
+	n := len(m.rows)
+	x := b.Copy()
+	for j := 0; j < n; j++ {
+		last := j + m.kl
+		if last > n-1 {
+			last = n - 1
+		}
+		p := j
+		for i := j + 1; i <= last; i++ {
+			if math.Abs(m.rows[i].get(j)) > math.Abs(m.rows[p].get(j)) {
+				p = i
+			}
+		}
+		m.rows[j], m.rows[p] = m.rows[p], m.rows[j]
+		x[j], x[p] = x[p], x[j]
+		pivot := m.rows[j].get(j)
+		end := j + m.kl + m.ku
+		if end > n-1 {
+			end = n - 1
+		}
+		for i := j + 1; i <= last; i++ {
+			f := m.rows[i].get(j) / pivot
+			if f == 0 {
+				continue
+			}
+			for c := j; c <= end; c++ {
+				if v := m.rows[j].get(c); v != 0 {
+					m.set(i, c, m.rows[i].get(c)-f*v)
+				}
+			}
+			x[i] -= f * x[j]
+		}
+	}
+	for j := n - 1; j >= 0; j-- {
+		end := j + m.kl + m.ku
+		if end > n-1 {
+			end = n - 1
+		}
+		sum := x[j]
+		for c := j + 1; c <= end; c++ {
+			sum -= m.rows[j].get(c) * x[c]
+		}
+		x[j] = sum / m.rows[j].get(j)
+	}
+	return x
+}
+
+// bspline is a spline of degree k in B-spline form with knots t and
+// coefficients c, as scipy's BSpline.
+type bspline struct {
+	t, c NpArray
+	k    int
+}
+
+// makeInterpSpline builds the B-spline of degree k interpolating (x, y) with
+// scipy's make_interp_spline default knots.
+func makeInterpSpline(x, y NpArray, k int) (*bspline, error) {
+	n := len(x)
+	var t NpArray
+	switch {
+	case k == 0:
+		t = append(x.Copy(), x[n-1])
+		return &bspline{t: t, c: y.Copy(), k: 0}, nil
+	case k == 1:
+		t = append(append(NpArray{x[0]}, x...), x[n-1])
+		return &bspline{t: t, c: y.Copy(), k: 1}, nil
+	case k == 2:
+		// Greville sites omitting the second and second to last points
+		for i := 0; i <= k; i++ {
+			t = append(t, x[0])
+		}
+		for i := 1; i < n-2; i++ {
+			t = append(t, (x[i+1]+x[i])/2)
+		}
+		for i := 0; i <= k; i++ {
+			t = append(t, x[n-1])
+		}
+	default:
+		// not-a-knot, drop the (k-1)/2 interior points at each end
+		m := (k - 1) / 2
+		for i := 0; i <= k; i++ {
+			t = append(t, x[0])
+		}
+		t = append(t, x[m+1:n-m-1]...)
+		for i := 0; i <= k; i++ {
+			t = append(t, x[n-1])
+		}
+	}
+	s := &bspline{t: t, k: k}
+	band := newBandMatrix(n, k, k)
+	h := make(NpArray, k+1)
+	for i, v := range x {
+		l := s.interval(v)
+		s.basis(v, l, h)
+		for a := 0; a <= k; a++ {
+			if col := l - k + a; h[a] != 0 {
+				if col < i-k || col > i+k {
+					return nil, fmt.Errorf("interpolation points are not compatible with the knots")
+				}
+				band.set(i, col, h[a])
+			}
+		}
+	}
+	s.c = band.solve(y)
+	return s, nil
+}
+
+// interval returns l with t[l] <= v < t[l+1], clamped to the base interval so
+// the end pieces extrapolate.
+func (s *bspline) interval(v float64) int {
+	n := len(s.t) - s.k - 1
+	l := sort.Search(len(s.t), func(j int) bool { return s.t[j] > v }) - 1
+	if l < s.k {
+		l = s.k
+	}
+	if l > n-1 {
+		l = n - 1
+	}
+	return l
+}
+
+// basis fills h with the k+1 B-splines that are non zero on interval l,
+// de Boor's recurrence.
+func (s *bspline) basis(v float64, l int, h NpArray) {
+	hh := make(NpArray, s.k)
+	h[0] = 1
+	for j := 1; j <= s.k; j++ {
+		copy(hh, h[:j])
+		h[0] = 0
+		for n := 1; n <= j; n++ {
+			xb := s.t[l+n]
+			xa := s.t[l+n-j]
+			if xb == xa {
+				h[n] = 0
+				continue
+			}
+			w := hh[n-1] / (xb - xa)
+			h[n-1] += w * (xb - v)
+			h[n] = w * (v - xa)
+		}
+	}
+}
+
+func (s *bspline) at(v float64) float64 {
+	if math.IsNaN(v) {
+		return v
+	}
+	l := s.interval(v)
+	h := make(NpArray, s.k+1)
+	s.basis(v, l, h)
+	ret := 0.0
+	for a := 0; a <= s.k; a++ {
+		ret += s.c[l-s.k+a] * h[a]
+	}
+	return ret
+}
+
+func checkIncreasing(x NpArray) error {
+	for i := 1; i < len(x); i++ {
+		if !(x[i] > x[i-1]) {
+			return fmt.Errorf("x must be strictly increasing, x[%d] = %v follows %v", i, x[i], x[i-1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestCubicSpline_ReproducesPolynomials(t *testing.T) {
+	x := NpArray{0, 0.5, 1.5, 2, 3, 4.5}
+	cube := x.Cond(func(v float64) float64 { return v * v * v })
+	s, err := NewCubicSpline(x, cube, NotAKnot, NotAKnot)
+	assert.NoError(t, err)
+	assert.True(t, s.Eval(NpArray{-1, 1.25, 2.5, 5}).AlmostEqual(NpArray{-1, 1.953125, 15.625, 125}, 1e-9))
+
+	square := x.Cond(func(v float64) float64 { return v * v })
+	s, err = NewCubicSpline(x, square, SplineBoundary{Order: 1, Value: 0}, SplineBoundary{Order: 1, Value: 9})
+	assert.NoError(t, err)
+	assert.True(t, s.Eval(NpArray{0.25, 1, 4}).AlmostEqual(NpArray{0.0625, 1, 16}, 1e-9))
+
+	s, err = NewCubicSpline(NpArray{0, 1, 2}, NpArray{0, 1, 4}, NotAKnot, NotAKnot)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.25, s.Eval(NpArray{1.5})[0], 1e-12)
+
+	s, err = NewCubicSpline(NpArray{0, 2}, NpArray{1, 5}, NotAKnot, NotAKnot)
+	assert.NoError(t, err)
+	assert.InDelta(t, 3, s.Eval(NpArray{1})[0], 1e-12)
+}
+
+func TestCubicSpline_Natural(t *testing.T) {
+	x := NpArray{0, 1, 2, 3}
+	y := NpArray{0, 1, 0, 1}
+	s, err := NewCubicSpline(x, y, Natural, Natural)
+	assert.NoError(t, err)
+	assert.Equal(t, y, s.Eval(x))
+	// the second derivative 2*c[1] vanishes at the first knot, and at the
+	// last knot for the end of the last piece
+	assert.InDelta(t, 0, s.c[1][0], 1e-12)
+	assert.InDelta(t, 0, 6*s.c[0][2]+2*s.c[1][2], 1e-12)
+	// by symmetry the middle of the middle piece is half way
+	assert.InDelta(t, 0.5, s.Eval(NpArray{1.5})[0], 1e-12)
+
+	_, err = NewCubicSpline(NpArray{0, 0, 1}, y[:3], Natural, Natural)
+	assert.Error(t, err)
+}
+
+func TestMakeInterpSpline_Degrees(t *testing.T) {
+	x := NpArray{0, 1, 2.5, 3, 4, 6}
+	for k := 0; k <= 3; k++ {
+		y := x.Cond(func(v float64) float64 { return math.Pow(v, float64(k)) })
+		s, err := makeInterpSpline(x, y, k)
+		assert.NoError(t, err)
+		for i, v := range x {
+			assert.InDelta(t, y[i], s.at(v), 1e-9)
+		}
+		if k > 0 {
+			// splines of degree k reproduce polynomials of degree k
+			assert.InDelta(t, math.Pow(3.7, float64(k)), s.at(3.7), 1e-9)
+		}
+	}
+}
@@ -16,7 +16,12 @@ func (m NpStack) String() string {
 	return ret
 }
 
+// Shape returns the number of rows and the length of the first, {0, 0} for
+// an empty stack.
 func (m NpStack) Shape() []int {
+	if len(m) == 0 {
+		return []int{0, 0}
+	}
 	return []int{len(m), len(m[0])}
 }
 
@@ -30,11 +35,7 @@ func (m NpStack) Shuffle(idx []int) NpStack {
 }
 
 func (m NpStack) Mean() NpArray {
-	ret := make(NpArray, len(m))
-	for i, a := range m {
-		ret[i] = a.Mean()
-	}
-	return ret
+	return m.With(nil).Mean()
 }
 
 func (m NpStack) ScalarMean() float64 {
@@ -42,17 +43,17 @@ func (m NpStack) ScalarMean() float64 {
 }
 
 func (m NpStack) StandardDeviation() NpArray {
-	ret := make(NpArray, len(m))
-	for i, a := range m {
-		ret[i] = a.StandardDeviation()
-	}
-	return ret
+	return m.With(nil).StandardDeviation()
 }
 
 func (m NpStack) ScalarStandardDeviation() float64 {
-	std := m.StandardDeviation() // NpArray
-	mean := m.Mean()             // NpArray
-	scalarMean := m.ScalarMean()
+	return combinedStandardDeviation(m.StandardDeviation(), m.Mean())
+}
+
+// combinedStandardDeviation is the standard deviation of equal length rows
+// with the given standard deviations and means.
+func combinedStandardDeviation(std, mean NpArray) float64 {
+	scalarMean := mean.Mean()
 	powStd := std.PowFloat64(2) // NpArray
 	meanDelta := mean.SubFloat64(scalarMean)
 	powMean := meanDelta.PowFloat64(2)
@@ -60,36 +61,25 @@ func (m NpStack) ScalarStandardDeviation() float64 {
 	return math.Sqrt(combinedVariance.Sum())
 }
 
+// Sub subtracts the scalar b[i] from every element of row i, so b holds one
+// value per row. This is not numpy's m - b, which broadcasts b across the
+// columns; use np.Sub for broadcasting.
 func (m NpStack) Sub(b NpArray) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.SubFloat64(b[i])
-	}
-	return ret
+	return m.With(nil).Sub(b)
 }
 
+// Div divides every element of row i by the scalar b[i]. Use np.Div for
+// numpy's broadcasting m / b.
 func (m NpStack) Div(b NpArray) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.DivFloat64(b[i])
-	}
-	return ret
+	return m.With(nil).Div(b)
 }
 
 func (m NpStack) SubSlice(b NpArray, start, end int) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.SubSlice(b, start, end)
-	}
-	return ret
+	return m.With(nil).SubSlice(b, start, end)
 }
 
 func (m NpStack) DivFloat64(b float64) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.DivFloat64(b)
-	}
-	return ret
+	return m.With(nil).DivFloat64(b)
 }
 
 func (m NpStack) Slice(start, end int) NpStack {
@@ -109,51 +99,27 @@ func (m NpStack) Column(i int) NpArray {
 }
 
 func (m NpStack) Cond(f func(float64) float64) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.Cond(f)
-	}
-	return ret
+	return m.With(nil).Cond(f)
 }
 
 func (m NpStack) LinearInterpolate(scale, new_scale NpArray) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.LinearInterpolate(scale, new_scale)
-	}
-	return ret
+	return m.With(nil).LinearInterpolate(scale, new_scale)
 }
 
 func (m NpStack) DivSlice(b float64, start, end int) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.DivSlice(b, start, end)
-	}
-	return ret
+	return m.With(nil).DivSlice(b, start, end)
 }
 
 func (m NpStack) SubFloat64(b float64) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.SubFloat64(b)
-	}
-	return ret
+	return m.With(nil).SubFloat64(b)
 }
 
 func (m NpStack) Add(b NpStack) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.Add(b[i])
-	}
-	return ret
+	return m.With(nil).Add(b)
 }
 
 func (m NpStack) MulFloat64(b float64) NpStack {
-	ret := make(NpStack, len(m))
-	for i, a := range m {
-		ret[i] = a.MulFloat64(b)
-	}
-	return ret
+	return m.With(nil).MulFloat64(b)
 }
 
 func (m NpStack) LinSpace(start, end float64, n int) NpStack {
@@ -169,27 +135,15 @@ func (m NpStack) LinSpace(start, end float64, n int) NpStack {
 }
 
 func (m NpStack) Max() NpArray {
-	ret := make(NpArray, len(m))
-	for i, a := range m {
-		ret[i] = a.Max()
-	}
-	return ret
+	return m.With(nil).Max()
 }
 
 func (m NpStack) Min() NpArray {
-	ret := make(NpArray, len(m))
-	for i, a := range m {
-		ret[i] = a.Min()
-	}
-	return ret
+	return m.With(nil).Min()
 }
 
 func (m NpStack) Sum() NpArray {
-	ret := make(NpArray, len(m))
-	for i, a := range m {
-		ret[i] = a.Sum()
-	}
-	return ret
+	return m.With(nil).Sum()
 }
 
 func (m NpStack) AllMostEqual(b NpStack, epsilon float64) bool {
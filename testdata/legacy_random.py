@@ -0,0 +1,327 @@
+"""Prints the seed 42 streams used by random_test.go.
+
+numpy is not needed: MT19937 comes from the random module, seeded as
+numpy.random.RandomState(seed) seeds it, and each distribution follows
+numpy's legacy C code in numpy/random/src (legacy-distributions.c and
+distributions.c). math.log, math.exp and math.pow call the same C library
+functions numpy does. randn(10) gives numpy's documented
+[0.49671415, -0.1382643, 0.64768854, 1.52302986, -0.23415337, ...].
+
+Besides printing the first ten draws of each stream it writes the first
+2000 to legacy_random.bin as little-endian float64s, stream after stream.
+The fixtures were written with Python 3.11.7 on glibc 2.36 (Debian
+2.36-9+deb12u13), x86-64 with FMA.
+"""
+import math
+import random
+import struct
+
+
+class RandomState:
+    def __init__(self, seed):
+        key = [seed & 0xFFFFFFFF]
+        for i in range(1, 624):
+            key.append((1812433253 * (key[-1] ^ (key[-1] >> 30)) + i) & 0xFFFFFFFF)
+        self.mt = random.Random()
+        self.mt.setstate((3, tuple(key) + (624,), None))
+        self.gauss_next = None
+        self.binomial = None
+
+    def double(self):
+        return self.mt.random()
+
+    def gauss(self):
+        if self.gauss_next is not None:
+            g, self.gauss_next = self.gauss_next, None
+            return g
+        while True:
+            x1 = 2.0 * self.double() - 1.0
+            x2 = 2.0 * self.double() - 1.0
+            r2 = x1 * x1 + x2 * x2
+            if r2 < 1.0 and r2 != 0.0:
+                break
+        f = math.sqrt(-2.0 * math.log(r2) / r2)
+        self.gauss_next = f * x1
+        return f * x2
+
+    def standard_exponential(self):
+        return -math.log(1.0 - self.double())
+
+    def standard_gamma(self, shape):
+        if shape == 1.0:
+            return self.standard_exponential()
+        if shape == 0.0:
+            return 0.0
+        if shape < 1.0:
+            while True:
+                u = self.double()
+                v = self.standard_exponential()
+                if u <= 1.0 - shape:
+                    x = math.pow(u, 1.0 / shape)
+                    if x <= v:
+                        return x
+                else:
+                    y = -math.log((1 - u) / shape)
+                    x = math.pow(1.0 - shape + shape * y, 1.0 / shape)
+                    if x <= v + y:
+                        return x
+        b = shape - 1.0 / 3.0
+        c = 1.0 / math.sqrt(9 * b)
+        while True:
+            while True:
+                x = self.gauss()
+                v = 1.0 + c * x
+                if v > 0.0:
+                    break
+            v = v * v * v
+            u = self.double()
+            if u < 1.0 - 0.0331 * (x * x) * (x * x):
+                return b * v
+            if math.log(u) < 0.5 * x * x + b * (1.0 - v + math.log(v)):
+                return b * v
+
+    def beta(self, a, b):
+        if a <= 1.0 and b <= 1.0:
+            while True:
+                u = self.double()
+                v = self.double()
+                x = math.pow(u, 1.0 / a)
+                y = math.pow(v, 1.0 / b)
+                xpy = x + y
+                if xpy <= 1.0:
+                    if xpy > 0:
+                        return x / xpy
+                    logx = math.log(u) / a
+                    logy = math.log(v) / b
+                    logm = max(logx, logy)
+                    logx -= logm
+                    logy -= logm
+                    return math.exp(logx - math.log(math.exp(logx) + math.exp(logy)))
+        ga = self.standard_gamma(a)
+        gb = self.standard_gamma(b)
+        return ga / (ga + gb)
+
+    def poisson(self, lam):
+        if lam >= 10:
+            return self.poisson_ptrs(lam)
+        if lam == 0:
+            return 0
+        enlam = math.exp(-lam)
+        x, prod = 0, 1.0
+        while True:
+            prod *= self.double()
+            if prod > enlam:
+                x += 1
+            else:
+                return x
+
+    def poisson_ptrs(self, lam):
+        slam = math.sqrt(lam)
+        loglam = math.log(lam)
+        b = 0.931 + 2.53 * slam
+        a = -0.059 + 0.02483 * b
+        invalpha = 1.1239 + 1.1328 / (b - 3.4)
+        vr = 0.9277 - 3.6224 / (b - 2)
+        while True:
+            u = self.double() - 0.5
+            v = self.double()
+            us = 0.5 - abs(u)
+            k = math.floor((2 * a / us + b) * u + lam + 0.43)
+            if us >= 0.07 and v <= vr:
+                return k
+            if k < 0 or (us < 0.013 and v > us):
+                continue
+            if (math.log(v) + math.log(invalpha) - math.log(a / (us * us) + b)
+                    <= -lam + k * loglam - loggam(k + 1)):
+                return k
+
+    def binomial_draw(self, n, p):
+        if n == 0 or p == 0.0:
+            return 0
+        if p <= 0.5:
+            if p * n <= 30.0:
+                return self.binomial_inversion(n, p)
+            return self.binomial_btpe(n, p)
+        q = 1.0 - p
+        if q * n <= 30.0:
+            return n - self.binomial_inversion(n, q)
+        return n - self.binomial_btpe(n, q)
+
+    def binomial_inversion(self, n, p):
+        if self.binomial is None or self.binomial[:2] != (n, p):
+            q = 1.0 - p
+            qn = math.exp(n * math.log(q))
+            np_ = n * p
+            bound = int(min(n, np_ + 10.0 * math.sqrt(np_ * q + 1)))
+            self.binomial = (n, p, q, qn, bound)
+        _, _, q, qn, bound = self.binomial
+        x = 0
+        px = qn
+        u = self.double()
+        while u > px:
+            x += 1
+            if x > bound:
+                x = 0
+                px = qn
+                u = self.double()
+            else:
+                u -= px
+                px = ((n - x + 1) * p * px) / (x * q)
+        return x
+
+    def binomial_btpe(self, n, p):
+        if self.binomial is None or self.binomial[:2] != (n, p):
+            r = min(p, 1.0 - p)
+            q = 1.0 - r
+            fm = n * r + r
+            m = math.floor(fm)
+            p1 = math.floor(2.195 * math.sqrt(n * r * q) - 4.6 * q) + 0.5
+            xm = m + 0.5
+            xl = xm - p1
+            xr = xm + p1
+            c = 0.134 + 20.5 / (15.3 + m)
+            a = (fm - xl) / (fm - xl * r)
+            laml = a * (1.0 + a / 2.0)
+            a = (xr - fm) / (xr * q)
+            lamr = a * (1.0 + a / 2.0)
+            p2 = p1 * (1.0 + 2.0 * c)
+            p3 = p2 + c / laml
+            p4 = p3 + c / lamr
+            self.binomial = (n, p, r, q, fm, m, p1, xm, xl, xr, c, laml, lamr, p2, p3, p4)
+        _, _, r, q, fm, m, p1, xm, xl, xr, c, laml, lamr, p2, p3, p4 = self.binomial
+        while True:
+            nrq = n * r * q
+            u = self.double() * p4
+            v = self.double()
+            if u <= p1:
+                y = math.floor(xm - p1 * v + u)
+                break
+            if u <= p2:
+                x = xl + (u - p1) / c
+                v = v * c + 1.0 - abs(m - x + 0.5) / p1
+                if v > 1.0:
+                    continue
+                y = math.floor(x)
+            elif u <= p3:
+                y = math.floor(xl + math.log(v) / laml) if v > 0 else -1
+                if y < 0 or v == 0.0:
+                    continue
+                v = v * (u - p2) * laml
+            else:
+                y = math.floor(xr - math.log(v) / lamr) if v > 0 else n + 1
+                if y > n or v == 0.0:
+                    continue
+                v = v * (u - p3) * lamr
+            k = abs(y - m)
+            if not (k > 20 and k < nrq / 2.0 - 1):
+                s = r / q
+                a = s * (n + 1)
+                f = 1.0
+                if m < y:
+                    for i in range(m + 1, y + 1):
+                        f *= a / i - s
+                elif m > y:
+                    for i in range(y + 1, m + 1):
+                        f /= a / i - s
+                if v > f:
+                    continue
+                break
+            rho = (k / nrq) * ((k * (k / 3.0 + 0.625) + 0.16666666666666666) / nrq + 0.5)
+            t = -k * k / (2 * nrq)
+            A = math.log(v)
+            if A < t - rho:
+                break
+            if A > t + rho:
+                continue
+            x1 = y + 1
+            f1 = m + 1
+            z = n + 1 - m
+            w = n - y + 1
+            x2 = x1 * x1
+            f2 = f1 * f1
+            z2 = z * z
+            w2 = w * w
+            bound = (xm * math.log(f1 / x1) + (n - m + 0.5) * math.log(z / w)
+                     + (y - m) * math.log(w * r / (x1 * q))
+                     + (13680. - (462. - (132. - (99. - 140. / f2) / f2) / f2) / f2) / f1 / 166320.
+                     + (13680. - (462. - (132. - (99. - 140. / z2) / z2) / z2) / z2) / z / 166320.
+                     + (13680. - (462. - (132. - (99. - 140. / x2) / x2) / x2) / x2) / x1 / 166320.
+                     + (13680. - (462. - (132. - (99. - 140. / w2) / w2) / w2) / w2) / w / 166320.)
+            if A > bound:
+                continue
+            break
+        if p > 0.5:
+            y = n - y
+        return y
+
+    def choice_p(self, size, p):
+        # choice(len(p), size, replace=False, p=p)
+        p = list(p)
+        found = []
+        while len(found) < size:
+            x = [self.double() for _ in range(size - len(found))]
+            for i in found:
+                p[i] = 0.0
+            cdf, s = [], 0.0
+            for v in p:
+                s += v
+                cdf.append(s)
+            cdf = [v / cdf[-1] for v in cdf]
+            new = [next(j for j, c in enumerate(cdf) if c > v) for v in x]
+            for j in new:
+                if j not in found[len(found) - len(new):] and j not in found:
+                    found.append(j)
+        return found
+
+
+def loggam(x):
+    a = [8.333333333333333e-02, -2.777777777777778e-03,
+         7.936507936507937e-04, -5.952380952380952e-04,
+         8.417508417508418e-04, -1.917526917526918e-03,
+         6.410256410256410e-03, -2.955065359477124e-02,
+         1.796443723688307e-01, -1.39243221690590e+00]
+    if x == 1.0 or x == 2.0:
+        return 0.0
+    n = int(7 - x) if x < 7.0 else 0
+    x0 = x + n
+    x2 = (1.0 / x0) * (1.0 / x0)
+    gl0 = a[9]
+    for k in range(8, -1, -1):
+        gl0 *= x2
+        gl0 += a[k]
+    gl = gl0 / x0 + 0.5 * math.log(2 * math.pi) + (x0 - 0.5) * math.log(x0) - x0
+    if x < 7.0:
+        for k in range(1, n + 1):
+            gl -= math.log(x0 - 1.0)
+            x0 -= 1.0
+    return gl
+
+
+streams = []
+
+
+def stream(name, draw, n=2000):
+    r = RandomState(42)
+    values = [float(draw(r)) for _ in range(n)]
+    print(name, values[:10])
+    streams.extend(values)
+
+
+stream("randn", RandomState.gauss)
+stream("normal(1, 2)", lambda r: 1 + 2 * r.gauss())
+stream("standard_exponential", RandomState.standard_exponential)
+stream("gamma(2.5, 2)", lambda r: 2 * r.standard_gamma(2.5))
+stream("standard_gamma(0.5)", lambda r: r.standard_gamma(0.5))
+stream("chisquare(4)", lambda r: 2.0 * r.standard_gamma(4 / 2.0))
+stream("lognormal(0, 0.5)", lambda r: math.exp(0 + 0.5 * r.gauss()))
+stream("beta(2, 3)", lambda r: r.beta(2, 3))
+stream("beta(0.5, 0.5)", lambda r: r.beta(0.5, 0.5))
+stream("poisson(3)", lambda r: r.poisson(3))
+stream("poisson(50)", lambda r: r.poisson(50))
+stream("binomial(20, 0.3)", lambda r: r.binomial_draw(20, 0.3))
+stream("binomial(1000, 0.4)", lambda r: r.binomial_draw(1000, 0.4))
+stream("binomial(1000, 0.9)", lambda r: r.binomial_draw(1000, 0.9))
+for p in ([0.5, 0, 0.25, 0.25], [0.1, 0.2, 0.3, 0.4]):
+    print("choice(4, 3, False, %s)" % p, RandomState(42).choice_p(3, p))
+with open("legacy_random.bin", "wb") as f:
+    f.write(struct.pack("<%dd" % len(streams), *streams))
@@ -0,0 +1,71 @@
+"""Writes libm.bin, the exp, log, log1p and pow cases of libm_test.go.
+
+Python's math module calls the C library's functions directly, so the
+results are what numpy gets from the same library. The fixture was written
+with glibc 2.36 (Debian 2.36-9+deb12u13) on an x86-64 processor with FMA,
+where glibc selects its FMA builds of exp, log and pow. Each function gets
+random arguments across its range plus arguments where glibc does not round
+correctly, found against the decimal module, so the cases tell a port of
+glibc from a correctly rounded function.
+
+The file holds four little-endian uint64 counts, then the (x, log(x)),
+(x, exp(x)), (x, log1p(x)) and (x, y, pow(x, y)) records as float64s.
+"""
+import decimal
+import math
+import random
+import struct
+
+decimal.getcontext().prec = 60
+D = decimal.Decimal
+rng = random.Random(2036)
+
+
+def bits(b):
+    return struct.unpack("<d", struct.pack("<Q", b))[0]
+
+
+def misrounded(f, exact, draw, n):
+    found = []
+    while len(found) < n:
+        args = draw()
+        if f(*args) != float(exact(*args)):
+            found.append(args)
+    return found
+
+
+def cases(f, draw, exact, n=1000, hard=20):
+    out = []
+    while len(out) < n:
+        args = draw()
+        try:
+            out.append(args + (f(*args),))
+        except (ValueError, OverflowError):
+            pass
+    return out + [a + (f(*a),) for a in misrounded(f, exact, draw_plain[f], hard)]
+
+
+draw_plain = {
+    math.log: lambda: (rng.random(),),
+    math.exp: lambda: (rng.uniform(-700, 700),),
+    math.log1p: lambda: (-rng.random(),),
+    math.pow: lambda: (rng.uniform(0, 4), rng.uniform(-40, 40)),
+}
+
+special = [0.0, -0.0, 1.0, -1.0, math.inf, -math.inf, bits(1), bits(0x000fffffffffffff), 0.5, 2.0, 1e-300]
+log = cases(math.log, lambda: (rng.choice([math.exp(rng.uniform(-745, 709)), rng.uniform(0.9, 1.1), bits(rng.getrandbits(63))]),),
+            lambda x: D(x).ln()) + [(x, math.log(x)) for x in special if x > 0]
+exp = cases(math.exp, lambda: (rng.choice([rng.uniform(-746, 709), rng.uniform(-1e-3, 1e-3), rng.uniform(-745.2, -708)]),),
+            lambda x: D(x).exp()) + [(x, math.exp(x)) for x in special if x < 709]
+log1p = cases(math.log1p, lambda: (rng.choice([-rng.random(), rng.uniform(-0.3, 0.42), math.exp(rng.uniform(-60, 700))]),),
+              lambda x: (1 + D(x)).ln()) + [(x, math.log1p(x)) for x in special if x > -1]
+pow_ = cases(math.pow, lambda: rng.choice([(rng.uniform(0, 3), rng.uniform(-1100, 1100)),
+                                          (rng.uniform(-50, 50), float(rng.randint(-300, 300))),
+                                          (rng.random() * 2.0 ** -1060, rng.uniform(-1.2, 1.2))]),
+             lambda x, y: D(x) ** D(y))
+
+with open("libm.bin", "wb") as f:
+    f.write(struct.pack("<4Q", len(log), len(exp), len(log1p), len(pow_)))
+    for rows in (log, exp, log1p, pow_):
+        for row in rows:
+            f.write(struct.pack("<%dd" % len(row), *row))
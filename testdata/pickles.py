@@ -0,0 +1,88 @@
+"""Writes the pickle fixtures used by pickle_test.go.
+
+numpy is not needed: the stand-in classes below reduce exactly the way
+numpy.ndarray and numpy.dtype do (see ndarray.__reduce__/__reduce_ex__), so
+the opcode streams match what pickle.dump writes for real numpy arrays.
+"""
+import pickle
+import struct
+import sys
+import types
+
+
+def _module(name):
+    mod = types.ModuleType(name)
+    sys.modules[name] = mod
+    return mod
+
+
+numpy = _module("numpy")
+numpy.core = _module("numpy.core")
+multiarray = _module("numpy.core.multiarray")
+numeric = _module("numpy.core.numeric")
+
+
+def _reconstruct(*args):
+    raise NotImplementedError
+
+
+def _frombuffer(*args):
+    raise NotImplementedError
+
+
+class ndarray:
+    def __init__(self, shape, descr, data, fortran=False):
+        self.shape, self.dtype, self.data, self.fortran = shape, dtype(descr), data, fortran
+
+    def __reduce_ex__(self, protocol):
+        if protocol >= 5:
+            order = "F" if self.fortran else "C"
+            return (_frombuffer, (pickle.PickleBuffer(self.data), self.dtype, self.shape, order))
+        state = (1, self.shape, self.dtype, self.fortran, self.data)
+        return (_reconstruct, (ndarray, (0,), b"b"), state)
+
+
+class dtype:
+    def __init__(self, descr):
+        self.descr = descr
+
+    def __reduce__(self):
+        order = "|" if self.descr[1:] in ("u1", "i1", "b1") else self.descr[0]
+        return (dtype, (self.descr[1:], False, True), (3, order, None, None, None, -1, -1, 0))
+
+
+for name, obj in [("_reconstruct", _reconstruct), ("ndarray", ndarray)]:
+    obj.__module__ = "numpy.core.multiarray"
+    setattr(multiarray, name, obj)
+_frombuffer.__module__ = "numpy.core.numeric"
+numeric._frombuffer = _frombuffer
+dtype.__module__ = "numpy"
+dtype.__qualname__ = "dtype"
+numpy.dtype = dtype
+
+
+def f8(*values):
+    return struct.pack("<%dd" % len(values), *values)
+
+
+data = {
+    "x": ndarray((2, 3), "<f8", f8(1, 2, 3, 4, 5, 6)),
+    "x_fortran": ndarray((2, 3), "<f8", f8(1, 4, 2, 5, 3, 6), fortran=True),
+    "y": ndarray((3,), "<i8", struct.pack("<3q", 0, 1, -2)),
+    "mask": ndarray((2,), "|b1", b"\x01\x00"),
+    "t": ndarray((2,), ">f4", struct.pack(">2f", 0.5, -1.5)),
+    "cube": ndarray((2, 1, 2), "<f8", f8(1, 2, 3, 4)),
+    "templates": {"x": ndarray((1, 2), "<f8", f8(7, 8)), "y": [0, 1]},
+    "name": "mnist1d",
+    "n": 5000,
+    "big": 1 << 40,
+    "ratio": 0.8,
+    "padding": (36, 60),
+    "flags": [True, False, None],
+    "raw": b"\x00\xff",
+}
+
+if __name__ == "__main__":
+    for protocol in (0, 2, 4, 5):
+        with open("mnist1d_p%d.pkl" % protocol, "wb") as f:
+            pickle.dump(data, f, protocol=protocol)
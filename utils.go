@@ -5,12 +5,27 @@ import (
 	"math"
 )
 
+// LinSpace returns n evenly spaced values from start to end inclusive,
+// computed as numpy.linspace does so that the last value is exactly end.
 func LinSpace(start, end float64, n int) NpArray {
-	step := (end - start) / float64(n-1)
 	ret := make(NpArray, n)
-	for i := 0; i < n; i++ {
-		ret[i] = start + float64(i)*step
+	switch n {
+	case 0:
+		return ret
+	case 1:
+		ret[0] = start
+		return ret
+	}
+	div := float64(n - 1)
+	step := (end - start) / div
+	for i := range ret {
+		if step == 0 {
+			ret[i] = float64(i)/div*(end-start) + start
+		} else {
+			ret[i] = float64(i)*step + start
+		}
 	}
+	ret[n-1] = end
 	return ret
 }
 
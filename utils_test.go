@@ -8,6 +8,10 @@ import (
 func TestLinSpace(t *testing.T) {
 	expected := NpArray{0, 0.5, 1}
 	assert.Equal(t, expected, LinSpace(0, 1, 3))
+	// like numpy the last value is exactly end
+	assert.Equal(t, 0.3, LinSpace(0, 0.3, 4)[3])
+	assert.Equal(t, NpArray{2}, LinSpace(2, 5, 1))
+	assert.Equal(t, NpArray{}, LinSpace(2, 5, 0))
 }
 
 func TestZeros(t *testing.T) {